@@ -1,16 +1,23 @@
 package config
 
 import (
+	"base-gin/domain"
 	"fmt"
 	"os"
 
 	"github.com/caarlos0/env/v9"
 	"github.com/joho/godotenv"
+	"github.com/robfig/cron/v3"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 	"github.com/rs/zerolog/pkgerrors"
 )
 
+const (
+	ThrottleStoreMemory = "memory"
+	ThrottleStoreDB     = "db"
+)
+
 type AppConfig struct {
 	Name    string `env:"APP_NAME"`
 	Address string `env:"SERVER_ADDRESS"`
@@ -18,25 +25,76 @@ type AppConfig struct {
 }
 
 type DBConfig struct {
-	DSN           string `env:"DB_DSN"`
+	DSN           string `env:"DB_DSN"` // mysql://, postgres:// or sqlite://
 	MaxOpenPool   int    `env:"DB_MAX_OPEN_POOL" envDefault:"25"`
 	MaxIdlePool   int    `env:"DB_MAX_IDLE_POOL" envDefault:"25"`
 	MaxIdleSecond int    `env:"DB_MAX_IDLE_SECOND" envDefault:"300"`
+	QueryTimeout  int    `env:"DB_QUERY_TIMEOUT" envDefault:"5"` // in seconds
 }
 
 type AuthNConfig struct {
 	LoginThrottleTTL         int    `env:"LOGIN_THROTTLE_TTL" envDefault:"300"` // in seconds
 	LoginMaxAttempt          int    `env:"LOGIN_MAX_ATTEMPT" envDefault:"10"`
+	LoginThrottleStore       string `env:"LOGIN_THROTTLE_STORE" envDefault:"memory"` // memory or db
 	JWTSecretKey             string `env:"JWT_SECRET"`
 	JWTAuthTTL               int    `env:"JWT_AUTH_TTL" envDefault:"3600"`
 	JWTRefreshTTL            int    `env:"JWT_REFRESH_TTL" envDefault:"2592000"`
+	JWTRevocationSync        int    `env:"JWT_REVOCATION_SYNC" envDefault:"30"` // in seconds
 	PasswordEncryptionSecret string `env:"PWD_SECRET_32CHAR"`
 }
 
+type CirculationConfig struct {
+	LoanPeriodDays      int   `env:"LOAN_PERIOD_DAYS" envDefault:"14"`
+	FinePerDay          int64 `env:"FINE_PER_DAY" envDefault:"1000"`     // per day late
+	FineMaxAmount       int64 `env:"FINE_MAX_AMOUNT" envDefault:"50000"` // per borrowing, 0 for no cap
+	FineGraceDays       int   `env:"FINE_GRACE_DAYS" envDefault:"0"`     // days late not charged
+	MaxLoans            int   `env:"MAX_LOANS" envDefault:"0"`           // concurrent, 0 for no limit
+	MaxRenewals         int   `env:"MAX_RENEWALS" envDefault:"2"`
+	RenewMaxOverdueDays int   `env:"RENEW_MAX_OVERDUE_DAYS" envDefault:"0"` // overdue days still renewable
+	HoldPickupDays      int   `env:"HOLD_PICKUP_DAYS" envDefault:"3"`
+}
+
+// NotificationConfig sets when loan reminders go out and through which
+// channels. A channel is left out when its host or URL is empty.
+type NotificationConfig struct {
+	DueSoonDays     int    `env:"NOTIFY_DUE_SOON_DAYS" envDefault:"2"`      // days before due
+	LongOverdueDays int    `env:"NOTIFY_LONG_OVERDUE_DAYS" envDefault:"14"` // days past due
+	DefaultLocale   string `env:"NOTIFY_LOCALE" envDefault:"id"`            // id or en
+	SMTPHost        string `env:"SMTP_HOST" envDefault:""`
+	SMTPPort        int    `env:"SMTP_PORT" envDefault:"587"`
+	SMTPUsername    string `env:"SMTP_USERNAME" envDefault:""`
+	SMTPPassword    string `env:"SMTP_PASSWORD" envDefault:""`
+	SMTPFrom        string `env:"SMTP_FROM" envDefault:""`
+	SMSGatewayURL   string `env:"SMS_GATEWAY_URL" envDefault:""`
+	SMSGatewayToken string `env:"SMS_GATEWAY_TOKEN" envDefault:""`
+	WebhookURL      string `env:"NOTIFY_WEBHOOK_URL" envDefault:""`
+	SendTimeout     int    `env:"NOTIFY_SEND_TIMEOUT" envDefault:"10"` // in seconds
+}
+
+// SchedulerConfig sets the cron specs of the background jobs. A job with an
+// empty spec only runs when triggered manually.
+type SchedulerConfig struct {
+	Enabled            bool   `env:"SCHEDULER_ENABLED" envDefault:"true"`
+	RemindersSpec      string `env:"JOB_REMINDERS_SPEC" envDefault:"0 8 * * *"`
+	ExpireHoldsSpec    string `env:"JOB_EXPIRE_HOLDS_SPEC" envDefault:"*/15 * * * *"`
+	OverdueSummarySpec string `env:"JOB_OVERDUE_SUMMARY_SPEC" envDefault:"0 7 * * *"`
+	PurgeSpec          string `env:"JOB_PURGE_SPEC" envDefault:"30 2 * * *"`
+	ReindexSpec        string `env:"JOB_REINDEX_SPEC" envDefault:"0 3 * * *"`
+	JobTimeout         int    `env:"JOB_TIMEOUT" envDefault:"300"`           // in seconds
+	RunRetentionDays   int    `env:"JOB_RUN_RETENTION_DAYS" envDefault:"30"` // job run history
+	// Instance names this process among those sharing the database, the host
+	// name when empty. It must stay the same across restarts, since only the
+	// runs of the instance left running are failed when it starts.
+	Instance string `env:"SCHEDULER_INSTANCE" envDefault:""`
+}
+
 type Config struct {
-	App   AppConfig
-	DB    DBConfig
-	AuthN AuthNConfig
+	App          AppConfig
+	DB           DBConfig
+	AuthN        AuthNConfig
+	Circulation  CirculationConfig
+	Notification NotificationConfig
+	Scheduler    SchedulerConfig
 }
 
 func NewConfig() Config {
@@ -63,5 +121,34 @@ func NewConfig() Config {
 		log.Fatal().Err(fmt.Errorf("PWD_SECRET_32CHAR must be %d characters", 32)).Msg("config error")
 	}
 
+	switch cfg.AuthN.LoginThrottleStore {
+	case ThrottleStoreMemory, ThrottleStoreDB:
+	default:
+		log.Fatal().Err(fmt.Errorf("LOGIN_THROTTLE_STORE must be %q or %q",
+			ThrottleStoreMemory, ThrottleStoreDB)).Msg("config error")
+	}
+
+	switch domain.TypeLocale(cfg.Notification.DefaultLocale) {
+	case domain.LocaleID, domain.LocaleEN:
+	default:
+		log.Fatal().Err(fmt.Errorf("NOTIFY_LOCALE must be %q or %q",
+			domain.LocaleID, domain.LocaleEN)).Msg("config error")
+	}
+
+	for name, spec := range map[string]string{
+		"JOB_REMINDERS_SPEC":       cfg.Scheduler.RemindersSpec,
+		"JOB_EXPIRE_HOLDS_SPEC":    cfg.Scheduler.ExpireHoldsSpec,
+		"JOB_OVERDUE_SUMMARY_SPEC": cfg.Scheduler.OverdueSummarySpec,
+		"JOB_PURGE_SPEC":           cfg.Scheduler.PurgeSpec,
+		"JOB_REINDEX_SPEC":         cfg.Scheduler.ReindexSpec,
+	} {
+		if spec == "" {
+			continue
+		}
+		if _, err := cron.ParseStandard(spec); err != nil {
+			log.Fatal().Err(fmt.Errorf("%s: %w", name, err)).Msg("config error")
+		}
+	}
+
 	return cfg
 }
@@ -1,5 +1,10 @@
 package constant
 
 const (
-	DefaultDataLen = 10
+	DefaultDataLen  = 10
+	ImportMaxSizeMb = 10 // catalog CSV upload
+
+	DefaultPatronCategory = "general"
+	DefaultItemType       = "book"
+	RuleWildcard          = "*" // matches any patron category or item type
 )
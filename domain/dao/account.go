@@ -1,6 +1,7 @@
 package dao
 
 import (
+	"base-gin/domain"
 	"base-gin/util"
 	"time"
 )
@@ -9,13 +10,17 @@ type Account struct {
 	ID        uint `gorm:"primarykey"`
 	CreatedAt time.Time
 	UpdatedAt time.Time
-	Username  string `gorm:"size:16;not null;uniqueIndex:user_pass;"`
-	Password  string `gorm:"size:255;not null;uniqueIndex:user_pass;"`
+	Username  string          `gorm:"size:16;not null;uniqueIndex:user_pass;"`
+	Password  string          `gorm:"size:255;not null;uniqueIndex:user_pass;"`
+	Role      domain.TypeRole `gorm:"size:16;not null;default:member;"`
+	// TokensRevokedAt invalidates every token issued up to that moment.
+	TokensRevokedAt *time.Time
 }
 
 func NewUser(uname, paswd, secret string) (Account, error) {
 	account := Account{
 		Username: uname,
+		Role:     domain.RoleMember,
 	}
 
 	if err := account.SetPassword(paswd, secret); err != nil {
@@ -29,6 +34,24 @@ func (t *Account) VerifyPassword(plainPaswd string) bool {
 	return util.VerifyPasswordHash(t.Password, plainPaswd)
 }
 
+func (t *Account) HasRole(roles ...domain.TypeRole) bool {
+	for _, role := range roles {
+		if t.Role == role {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsTokenRevoked tells whether a token issued at issuedAt was revoked along
+// with every token of the account. The issue time of a JWT only has whole
+// seconds, so tokens issued within the second of the revocation are kept
+// rather than rejecting those issued right after it.
+func (t *Account) IsTokenRevoked(issuedAt time.Time) bool {
+	return t.TokensRevokedAt != nil && issuedAt.Before(t.TokensRevokedAt.Truncate(time.Second))
+}
+
 func (t *Account) SetPassword(passsword, secretKey string) error {
 	passwordHashed, err := util.PasswordHash(passsword)
 	if err != nil {
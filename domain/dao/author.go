@@ -7,10 +7,10 @@ import (
 )
 
 type Author struct {
-	ID        uint      `gorm:"primarykey;type:bigint"`
-	FullName  string    `gorm:"type:varchar(56);not null"`
-	Gender    string    `gorm:"type:enum('m','f');default:null"`
-	BirthDate time.Time `gorm:"type:datetime;default:null"`
+	ID        uint      `gorm:"primarykey"`
+	FullName  string    `gorm:"size:56;not null"`
+	Gender    string    `gorm:"size:1;default:null"`
+	BirthDate time.Time `gorm:"default:null"`
 	CreatedAt time.Time
 	UpdatedAt time.Time
 	DeletedAt gorm.DeletedAt `gorm:"index"`
@@ -7,13 +7,18 @@ import (
 )
 
 type Book struct {
-	ID            uint      `gorm:"primaryKey"`
-	Title         string    `gorm:"size:56;"`
-	Subtitle      *string   `gorm:"size:64;"`
-	PublisherID   uint      `gorm:"not null;"`
-	AuthorID      uint      `gorm:"not null"`
-	BookPublisher Publisher `gorm:"foreignKey:PublisherID;"`
-	BookAuthor    Author    `gorm:"foreignKey:AuthorID;"`
+	ID            uint              `gorm:"primaryKey"`
+	Title         string            `gorm:"size:56;"`
+	Subtitle      *string           `gorm:"size:64;"`
+	ISBN10        *string           `gorm:"size:10;uniqueIndex;"`
+	ISBN13        *string           `gorm:"size:13;uniqueIndex;"`
+	PublisherID   uint              `gorm:"not null;"`
+	AuthorID      uint              `gorm:"not null"`
+	ItemType      string            `gorm:"size:32;not null;default:book;"`
+	BookPublisher Publisher         `gorm:"foreignKey:PublisherID;"`
+	BookAuthor    Author            `gorm:"foreignKey:AuthorID;"`
+	Contributors  []BookContributor `gorm:"foreignKey:BookID;"`
+	Categories    []Category        `gorm:"many2many:book_categories;"`
 	CreatedAt     time.Time
 	UpdatedAt     time.Time
 	DeletedAt     gorm.DeletedAt `gorm:"index"`
@@ -0,0 +1,18 @@
+package dao
+
+import "base-gin/domain"
+
+// BookContributor credits an author with a role on a book. Position orders
+// the contributors of a book as they are credited.
+type BookContributor struct {
+	BookID   uint                       `gorm:"primaryKey;autoIncrement:false;"`
+	AuthorID uint                       `gorm:"primaryKey;autoIncrement:false;index;"`
+	Role     domain.TypeContributorRole `gorm:"primaryKey;size:16;"`
+	Position int                        `gorm:"not null;default:0;"`
+	Book     *Book                      `gorm:"foreignKey:BookID;"`
+	Author   *Author                    `gorm:"foreignKey:AuthorID;"`
+}
+
+func (BookContributor) TableName() string {
+	return "book_contributors"
+}
@@ -0,0 +1,26 @@
+package dao
+
+import (
+	"base-gin/domain"
+	"time"
+)
+
+// BookCopy is a physical item of a book that can be lent out on its own.
+type BookCopy struct {
+	ID            uint                  `gorm:"primaryKey"`
+	BookID        uint                  `gorm:"not null;index;"`
+	Book          *Book                 `gorm:"foreignKey:BookID;"`
+	Barcode       string                `gorm:"size:32;not null;uniqueIndex;"`
+	AcquiredAt    *time.Time            `gorm:"default:null"`
+	ShelfLocation *string               `gorm:"size:32;"`
+	Status        domain.TypeCopyStatus `gorm:"size:16;not null;default:available;index;"`
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+// CopyCount holds the number of copies of a book.
+type CopyCount struct {
+	BookID    uint
+	Total     int
+	Available int
+}
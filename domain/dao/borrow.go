@@ -7,14 +7,54 @@ import (
 )
 
 type Borrowing struct {
-	ID         uint       `gorm:"primaryKey"`
-	BorrowDate time.Time  `gorm:"not null;"`
-	ReturnDate *time.Time `gorm:""`
-	BookID     uint       `gorm:"not null;"`
-	PersonID   uint       `gorm:"not null"`
-	Book       Book       `gorm:"foreignKey:BookID"`
-	Person     Person     `gorm:"foreignKey:PersonID"`
-	CreatedAt  time.Time
-	UpdatedAt  time.Time
-	DeletedAt  gorm.DeletedAt `gorm:"index"`
+	ID           uint       `gorm:"primaryKey"`
+	BorrowDate   time.Time  `gorm:"not null;"`
+	ReturnDate   *time.Time `gorm:""`
+	DueDate      time.Time  `gorm:"index;"`
+	RenewalCount int        `gorm:"not null;default:0;"`
+	BookID       uint       `gorm:"not null;"`
+	BookCopyID   *uint      `gorm:"index;"`
+	PersonID     uint       `gorm:"not null"`
+	Book         Book       `gorm:"foreignKey:BookID"`
+	BookCopy     *BookCopy  `gorm:"foreignKey:BookCopyID"`
+	Person       Person     `gorm:"foreignKey:PersonID"`
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+	DeletedAt    gorm.DeletedAt `gorm:"index"`
+}
+
+// IsOverdue tells whether the borrowing is still out past its due date.
+func (b *Borrowing) IsOverdue(now time.Time) bool {
+	return b.ReturnDate == nil && now.After(b.DueDate)
+}
+
+// DaysOverdue returns the number of whole days the borrowing is past its due
+// date, or zero when it is not overdue.
+func (b *Borrowing) DaysOverdue(now time.Time) int {
+	if !b.IsOverdue(now) {
+		return 0
+	}
+
+	return int(now.Sub(b.DueDate).Hours() / 24)
+}
+
+// DaysLate returns the number of whole days the borrowing was returned past
+// its due date, or zero when it is not returned or was returned in time.
+func (b *Borrowing) DaysLate() int {
+	if b.ReturnDate == nil || !b.ReturnDate.After(b.DueDate) {
+		return 0
+	}
+
+	return int(b.ReturnDate.Sub(b.DueDate).Hours() / 24)
+}
+
+// BorrowingRenewal records a renewal of a borrowing and the due date change it
+// made.
+type BorrowingRenewal struct {
+	ID              uint      `gorm:"primaryKey"`
+	BorrowingID     uint      `gorm:"not null;index;"`
+	PreviousDueDate time.Time `gorm:"not null;"`
+	NewDueDate      time.Time `gorm:"not null;"`
+	RenewedByID     *uint     `gorm:"default:null"`
+	CreatedAt       time.Time
 }
@@ -0,0 +1,29 @@
+package dao
+
+import "time"
+
+// Category is a subject of the catalog. Categories form a tree through
+// ParentID, nil for the top ones.
+type Category struct {
+	ID          uint    `gorm:"primaryKey"`
+	ParentID    *uint   `gorm:"index;"`
+	Name        string  `gorm:"size:64;not null;"`
+	Slug        string  `gorm:"size:64;not null;uniqueIndex;"`
+	Description *string `gorm:"size:255;"`
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+func (Category) TableName() string {
+	return "categories"
+}
+
+// BookCategory files a book under a category.
+type BookCategory struct {
+	BookID     uint `gorm:"primaryKey;autoIncrement:false;"`
+	CategoryID uint `gorm:"primaryKey;autoIncrement:false;index;"`
+}
+
+func (BookCategory) TableName() string {
+	return "book_categories"
+}
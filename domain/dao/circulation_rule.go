@@ -0,0 +1,33 @@
+package dao
+
+import (
+	"base-gin/constant"
+	"time"
+)
+
+// CirculationRule sets the lending limits for a patron category and item
+// type, either of which may be the wildcard.
+type CirculationRule struct {
+	ID             uint   `gorm:"primaryKey"`
+	PatronCategory string `gorm:"size:32;not null;uniqueIndex:idx_circulation_rule_scope;"`
+	ItemType       string `gorm:"size:32;not null;uniqueIndex:idx_circulation_rule_scope;"`
+	MaxLoans       int    `gorm:"not null;"` // concurrent, 0 for no limit
+	LoanPeriodDays int    `gorm:"not null;"`
+	MaxRenewals    int    `gorm:"not null;"`
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+// Specificity ranks how narrowly the rule applies. A rule for the patron
+// category outranks one for the item type, which outranks the catch-all.
+func (r *CirculationRule) Specificity() int {
+	var rank int
+	if r.PatronCategory != constant.RuleWildcard {
+		rank += 2
+	}
+	if r.ItemType != constant.RuleWildcard {
+		rank++
+	}
+
+	return rank
+}
@@ -0,0 +1,28 @@
+package dao
+
+import (
+	"base-gin/domain"
+	"time"
+)
+
+// FineEntry is a line of a person's fines ledger. Amounts are always
+// positive; charges add to the balance while payments and waivers take from
+// it.
+type FineEntry struct {
+	ID          uint                 `gorm:"primaryKey"`
+	PersonID    uint                 `gorm:"not null;index;"`
+	Person      *Person              `gorm:"foreignKey:PersonID;"`
+	BorrowingID *uint                `gorm:"index;"`
+	Type        domain.TypeFineEntry `gorm:"size:16;not null;"`
+	Amount      int64                `gorm:"not null;"`
+	Reason      *string              `gorm:"size:255;"`
+	CreatedByID *uint                `gorm:"default:null"`
+	CreatedAt   time.Time
+}
+
+// FineBalance is the outstanding fines of a person.
+type FineBalance struct {
+	PersonID uint
+	Fullname string
+	Balance  int64
+}
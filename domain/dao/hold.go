@@ -0,0 +1,28 @@
+package dao
+
+import (
+	"base-gin/domain"
+	"time"
+)
+
+// Hold is a person's place in the queue for a book. Holds are served first
+// come, first served; once a returned item is set aside for the hold it is
+// ready for pickup until ExpiresAt.
+type Hold struct {
+	ID         uint                  `gorm:"primaryKey"`
+	BookID     uint                  `gorm:"not null;index;"`
+	Book       *Book                 `gorm:"foreignKey:BookID;"`
+	PersonID   uint                  `gorm:"not null;index;"`
+	Person     *Person               `gorm:"foreignKey:PersonID;"`
+	BookCopyID *uint                 `gorm:"default:null"`
+	Status     domain.TypeHoldStatus `gorm:"size:16;not null;default:waiting;index;"`
+	ReadyAt    *time.Time            `gorm:"default:null"`
+	ExpiresAt  *time.Time            `gorm:"default:null;index;"`
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+// IsActive tells whether the hold is still queued or waiting for pickup.
+func (h *Hold) IsActive() bool {
+	return h.Status == domain.HoldWaiting || h.Status == domain.HoldReady
+}
@@ -0,0 +1,25 @@
+package dao
+
+import (
+	"base-gin/domain"
+	"time"
+)
+
+// JobRun records one run of a scheduled job. Message sums up what a finished
+// run did and Error why it failed.
+type JobRun struct {
+	ID         uint                  `gorm:"primaryKey"`
+	JobName    string                `gorm:"size:64;not null;index;"`
+	Instance   string                `gorm:"size:64;not null;default:'';"` // of the scheduler
+	Trigger    domain.TypeJobTrigger `gorm:"size:16;not null;"`
+	Status     domain.TypeJobStatus  `gorm:"size:16;not null;index;"`
+	Message    *string               `gorm:"size:255;"`
+	Error      *string               `gorm:"size:255;"`
+	StartedAt  time.Time             `gorm:"not null;index;"`
+	FinishedAt *time.Time
+	DurationMs int64 `gorm:"not null;default:0;"`
+}
+
+func (JobRun) TableName() string {
+	return "job_runs"
+}
@@ -0,0 +1,11 @@
+package dao
+
+import "time"
+
+// LoginAttempt counts failed logins of a throttle key, e.g. a username or an
+// IP address, within the window ending at ExpiresAt.
+type LoginAttempt struct {
+	ThrottleKey string    `gorm:"primaryKey;size:128;"`
+	Attempts    int       `gorm:"not null;"`
+	ExpiresAt   time.Time `gorm:"not null;index;"`
+}
@@ -0,0 +1,67 @@
+package dao
+
+import (
+	"base-gin/domain"
+	"time"
+)
+
+// NotificationPreference tells how a person is reached and which channels
+// they opted out of. Persons without one get the default locale and nothing
+// but webhook notifications, having no contact to send to.
+type NotificationPreference struct {
+	PersonID      uint    `gorm:"primaryKey;autoIncrement:false"`
+	Person        *Person `gorm:"foreignKey:PersonID;"`
+	Email         *string `gorm:"size:128;"`
+	Phone         *string `gorm:"size:20;"`
+	Locale        string  `gorm:"size:2;not null;"`
+	EmailOptOut   bool    `gorm:"not null;"`
+	SMSOptOut     bool    `gorm:"not null;"`
+	WebhookOptOut bool    `gorm:"not null;"`
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+func (NotificationPreference) TableName() string {
+	return "notification_preferences"
+}
+
+// Recipient returns where the channel reaches the person and whether it does
+// at all, which it does not without a contact or once opted out. Webhooks are
+// not addressed to anyone in particular.
+func (p *NotificationPreference) Recipient(channel domain.TypeChannel) (string, bool) {
+	switch channel {
+	case domain.ChannelEmail:
+		if p.EmailOptOut || p.Email == nil {
+			return "", false
+		}
+		return *p.Email, true
+	case domain.ChannelSMS:
+		if p.SMSOptOut || p.Phone == nil {
+			return "", false
+		}
+		return *p.Phone, true
+	case domain.ChannelWebhook:
+		return "", !p.WebhookOptOut
+	}
+
+	return "", false
+}
+
+// NotificationDelivery logs a notification sent, or tried to be sent, to a
+// person about a borrowing due on DueDate.
+type NotificationDelivery struct {
+	ID          uint                      `gorm:"primaryKey"`
+	PersonID    uint                      `gorm:"not null;index;"`
+	BorrowingID uint                      `gorm:"not null;index;"`
+	Kind        domain.TypeNotification   `gorm:"size:16;not null;"`
+	Channel     domain.TypeChannel        `gorm:"size:16;not null;"`
+	DueDate     time.Time                 `gorm:"not null;"`
+	Recipient   string                    `gorm:"size:128;not null;"`
+	Status      domain.TypeDeliveryStatus `gorm:"size:8;not null;"`
+	Error       *string                   `gorm:"size:255;"`
+	CreatedAt   time.Time
+}
+
+func (NotificationDelivery) TableName() string {
+	return "notification_deliveries"
+}
@@ -12,8 +12,9 @@ type Person struct {
 	AccountID *uint              `gorm:"uniqueIndex;"`
 	Account   *Account           `gorm:"foreignKey:AccountID;"`
 	Fullname  string             `gorm:"size:56;not null;"`
-	Gender    *domain.TypeGender `gorm:"type:enum('f','m');"`
+	Gender    *domain.TypeGender `gorm:"size:1;"`
 	BirthDate *time.Time
+	Category  string `gorm:"size:32;not null;default:general;"`
 }
 
 func (Person) TableName() string {
@@ -0,0 +1,16 @@
+package dao
+
+import "time"
+
+// RefreshToken records every issued refresh token so it can be rotated and
+// revoked. ReplacedBy holds the token ID issued in exchange for this one.
+type RefreshToken struct {
+	ID         uint      `gorm:"primaryKey"`
+	TokenID    string    `gorm:"size:36;not null;uniqueIndex;"`
+	AccountID  uint      `gorm:"not null;index;"`
+	Account    Account   `gorm:"foreignKey:AccountID;"`
+	ExpiresAt  time.Time `gorm:"not null;"`
+	RevokedAt  *time.Time
+	ReplacedBy *string `gorm:"size:36;"`
+	CreatedAt  time.Time
+}
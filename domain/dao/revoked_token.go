@@ -0,0 +1,12 @@
+package dao
+
+import "time"
+
+// RevokedToken is a denylist entry for a token revoked before it expires. The
+// entry is useless once the token expires and can be purged then.
+type RevokedToken struct {
+	TokenID   string    `gorm:"primaryKey;size:36;"`
+	AccountID uint      `gorm:"not null;index;"`
+	ExpiresAt time.Time `gorm:"not null;index;"`
+	CreatedAt time.Time
+}
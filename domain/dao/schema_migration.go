@@ -0,0 +1,13 @@
+package dao
+
+import "time"
+
+type SchemaMigration struct {
+	Version   uint      `gorm:"primaryKey;autoIncrement:false"`
+	Name      string    `gorm:"size:128;not null;"`
+	AppliedAt time.Time `gorm:"autoCreateTime"`
+}
+
+func (SchemaMigration) TableName() string {
+	return "schema_migrations"
+}
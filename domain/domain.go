@@ -6,3 +6,101 @@ const (
 	GenderMale   TypeGender = "m"
 	GenderFemale TypeGender = "f"
 )
+
+type TypeRole string
+
+const (
+	RoleAdmin     TypeRole = "admin"
+	RoleLibrarian TypeRole = "librarian"
+	RoleMember    TypeRole = "member"
+)
+
+type TypeCopyStatus string
+
+const (
+	CopyAvailable TypeCopyStatus = "available"
+	CopyOnLoan    TypeCopyStatus = "on_loan"
+	CopyOnHold    TypeCopyStatus = "on_hold"
+	CopyLost      TypeCopyStatus = "lost"
+	CopyDamaged   TypeCopyStatus = "damaged"
+	CopyWithdrawn TypeCopyStatus = "withdrawn"
+)
+
+type TypeBorrowingStatus string
+
+const (
+	BorrowingActive   TypeBorrowingStatus = "active"
+	BorrowingOverdue  TypeBorrowingStatus = "overdue"
+	BorrowingReturned TypeBorrowingStatus = "returned"
+)
+
+type TypeFineEntry string
+
+const (
+	FineCharge  TypeFineEntry = "charge"
+	FinePayment TypeFineEntry = "payment"
+	FineWaiver  TypeFineEntry = "waiver"
+)
+
+type TypeHoldStatus string
+
+const (
+	HoldWaiting   TypeHoldStatus = "waiting"
+	HoldReady     TypeHoldStatus = "ready"
+	HoldFulfilled TypeHoldStatus = "fulfilled"
+	HoldCancelled TypeHoldStatus = "cancelled"
+	HoldExpired   TypeHoldStatus = "expired"
+)
+
+type TypeLocale string
+
+const (
+	LocaleID TypeLocale = "id"
+	LocaleEN TypeLocale = "en"
+)
+
+type TypeNotification string
+
+const (
+	NotifyDueSoon     TypeNotification = "due_soon"
+	NotifyLongOverdue TypeNotification = "long_overdue"
+)
+
+type TypeChannel string
+
+const (
+	ChannelEmail   TypeChannel = "email"
+	ChannelSMS     TypeChannel = "sms"
+	ChannelWebhook TypeChannel = "webhook"
+)
+
+type TypeDeliveryStatus string
+
+const (
+	DeliverySent   TypeDeliveryStatus = "sent"
+	DeliveryFailed TypeDeliveryStatus = "failed"
+)
+
+type TypeJobStatus string
+
+const (
+	JobRunning   TypeJobStatus = "running"
+	JobSucceeded TypeJobStatus = "succeeded"
+	JobFailed    TypeJobStatus = "failed"
+)
+
+type TypeJobTrigger string
+
+const (
+	JobTriggerSchedule TypeJobTrigger = "schedule"
+	JobTriggerManual   TypeJobTrigger = "manual"
+)
+
+type TypeContributorRole string
+
+const (
+	ContributorAuthor      TypeContributorRole = "author"
+	ContributorEditor      TypeContributorRole = "editor"
+	ContributorTranslator  TypeContributorRole = "translator"
+	ContributorIllustrator TypeContributorRole = "illustrator"
+)
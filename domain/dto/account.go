@@ -16,6 +16,48 @@ type AccountLoginResp struct {
 	RefreshToken string `json:"refresh_token"`
 }
 
+type AccountRegisterReq struct {
+	Username     string `json:"uname" binding:"required,min=4,max=16,alphanum"`
+	Password     string `json:"paswd" binding:"required,min=8,max=255"`
+	Fullname     string `json:"fullname" binding:"required,min=4,max=56"`
+	Gender       string `json:"gender" binding:"omitempty,oneof=m f"`
+	BirthDateStr string `json:"birth_date" binding:"omitempty,datetime=2006-01-02"`
+	Login        bool   `json:"login"`
+}
+
+func (o *AccountRegisterReq) ToPerson() (dao.Person, error) {
+	person := dao.Person{Fullname: o.Fullname}
+
+	if o.Gender != "" {
+		gender := domain.GenderMale
+		if o.Gender == "f" {
+			gender = domain.GenderFemale
+		}
+		person.Gender = &gender
+	}
+
+	if o.BirthDateStr != "" {
+		birthDate, err := time.Parse("2006-01-02", o.BirthDateStr)
+		if err != nil {
+			return person, err
+		}
+		person.BirthDate = &birthDate
+	}
+
+	return person, nil
+}
+
+type AccountRegisterResp struct {
+	ID       uint              `json:"id"`
+	Username string            `json:"uname"`
+	Fullname string            `json:"fullname"`
+	Token    *AccountLoginResp `json:"token,omitempty"`
+}
+
+type TokenRevokeReq struct {
+	Token string `json:"token" binding:"required"`
+}
+
 type AccountProfileResp struct {
 	Fullname string `json:"fullname"`
 	Gender   string `json:"gender"`
@@ -41,3 +83,7 @@ func (o *AccountProfileResp) FromPerson(person *dao.Person) {
 	o.Gender = gender
 	o.Age = int(age)
 }
+
+type AccountRoleReq struct {
+	Role string `json:"role" binding:"required,oneof=admin librarian member"`
+}
@@ -21,6 +21,11 @@ func (a *AuthorDTO) ToEntity() dao.Author {
 	}
 }
 
+type AuthorFilter struct {
+	Filter
+	Gender string `form:"gender" binding:"omitempty,oneof=m f"`
+}
+
 type AuthorResp struct {
 	ID        uint       `json:"id"`
 	FullName  string     `json:"full_name"`
@@ -1,55 +1,220 @@
 package dto
 
-import "base-gin/domain/dao"
+import (
+	"base-gin/constant"
+	"base-gin/domain"
+	"base-gin/domain/dao"
+	"base-gin/util"
+)
 
 type BookDTO struct {
 	ID          uint    `json:"-"`
 	Title       string  `json:"title" binding:"required,min=2,max=56"`
 	Subtitle    *string `json:"subtitle" binding:"min=2,max=56"`
+	ISBN        *string `json:"isbn" binding:"omitempty,max=17,isbn"`
 	PublisherID uint    `gorm:"not null;"`
 	AuthorID    uint    `gorm:"not null"`
+	ItemType    string  `json:"item_type" binding:"omitempty,max=32,ne=*"`
+	// Contributors credits the authors in the given order. Without any,
+	// AuthorID is the sole author.
+	Contributors []BookContributorReq `json:"contributors" binding:"omitempty,min=1,dive"`
+	CategoryIDs  []uint               `json:"category_ids" binding:"omitempty,dive,min=1"`
 }
 
 func (o *BookDTO) ToEntity() dao.Book {
-	return dao.Book{
+	item := dao.Book{
 		ID:          o.ID,
 		Title:       o.Title,
 		Subtitle:    o.Subtitle,
 		PublisherID: o.PublisherID,
-		AuthorID:    o.AuthorID,
+		ItemType:    o.ItemType,
 	}
+	item.AuthorID, item.Contributors = contributorEntities(o.AuthorID, o.Contributors)
+	if item.ItemType == "" {
+		item.ItemType = constant.DefaultItemType
+	}
+	item.ISBN10, item.ISBN13 = isbnColumns(o.ISBN)
+
+	return item
+}
+
+type BookFilter struct {
+	Filter
+	PublisherID uint `form:"publisher_id" binding:"omitempty,min=1"`
+	// AuthorID keeps the books the author contributed to in any role.
+	AuthorID uint   `form:"author_id" binding:"omitempty,min=1"`
+	ItemType string `form:"item_type" binding:"omitempty,max=32"`
 }
 
 type BookResp struct {
-	ID          uint    `json:"id"`
-	Title       string  `json:"title"`
-	Subtitle    *string `json:"subtitle"`
-	PublisherID uint    `json:"publisher_id"`
-	AuthorID    uint    `json:"author_id"`
+	ID              uint    `json:"id"`
+	Title           string  `json:"title"`
+	Subtitle        *string `json:"subtitle"`
+	ISBN10          *string `json:"isbn_10"`
+	ISBN13          *string `json:"isbn_13"`
+	PublisherID     uint    `json:"publisher_id"`
+	AuthorID        uint    `json:"author_id"`
+	ItemType        string  `json:"item_type"`
+	TotalCopies     int     `json:"total_copies"`
+	AvailableCopies int     `json:"available_copies"`
+
+	Contributors []BookContributorResp `json:"contributors"`
+	Categories   []CategoryRefResp     `json:"categories"`
 }
 
 func (o *BookResp) FromEntity(item *dao.Book) {
 	o.ID = item.ID
 	o.Title = item.Title
 	o.Subtitle = item.Subtitle
+	o.ISBN10 = item.ISBN10
+	o.ISBN13 = item.ISBN13
 	o.PublisherID = item.PublisherID
 	o.AuthorID = item.AuthorID
+	o.ItemType = item.ItemType
+
+	o.Contributors = make([]BookContributorResp, len(item.Contributors))
+	for i := range item.Contributors {
+		o.Contributors[i].FromEntity(&item.Contributors[i])
+	}
+	o.Categories = make([]CategoryRefResp, len(item.Categories))
+	for i := range item.Categories {
+		o.Categories[i].FromEntity(&item.Categories[i])
+	}
+}
+
+func (o *BookResp) SetCopyCount(count dao.CopyCount) {
+	o.TotalCopies = count.Total
+	o.AvailableCopies = count.Available
 }
 
 type BookUpdate struct {
-    ID          uint    `json:"-"`
-    Title       string  `json:"title" binding:"required,min=2,max=56"`
-    Subtitle    *string `json:"subtitle" binding:"min=2,max=56"`
-    PublisherID uint    `json:"publisher_id" binding:"required"`
-    AuthorID    uint    `json:"author_id" binding:"required"`
+	ID          uint    `json:"-"`
+	Title       string  `json:"title" binding:"required,min=2,max=56"`
+	Subtitle    *string `json:"subtitle" binding:"min=2,max=56"`
+	ISBN        *string `json:"isbn" binding:"omitempty,max=17,isbn"`
+	PublisherID uint    `json:"publisher_id" binding:"required"`
+	AuthorID    uint    `json:"author_id" binding:"required_without=Contributors"`
+	ItemType    string  `json:"item_type" binding:"omitempty,max=32,ne=*"`
+	// Contributors replaces the credited authors. Without any, AuthorID is the
+	// sole author.
+	Contributors []BookContributorReq `json:"contributors" binding:"omitempty,min=1,dive"`
+	CategoryIDs  []uint               `json:"category_ids" binding:"omitempty,dive,min=1"`
 }
 
 func (b *BookUpdate) ToEntity() *dao.Book {
-    return &dao.Book{
-        ID:          b.ID,
-        Title:       b.Title,
-        Subtitle:    b.Subtitle,
-        PublisherID: b.PublisherID,
-        AuthorID:    b.AuthorID,
-    }
-}
\ No newline at end of file
+	item := &dao.Book{
+		ID:          b.ID,
+		Title:       b.Title,
+		Subtitle:    b.Subtitle,
+		PublisherID: b.PublisherID,
+		ItemType:    b.ItemType,
+	}
+	item.AuthorID, item.Contributors = contributorEntities(b.AuthorID, b.Contributors)
+	item.ISBN10, item.ISBN13 = isbnColumns(b.ISBN)
+
+	return item
+}
+
+// isbnColumns returns both forms of a validated ISBN. The ISBN-10 is nil for
+// ISBN-13s having none.
+func isbnColumns(isbn *string) (*string, *string) {
+	if isbn == nil {
+		return nil, nil
+	}
+
+	isbn10, isbn13, err := util.ParseISBN(*isbn)
+	if err != nil {
+		return nil, nil
+	}
+	if isbn10 == "" {
+		return nil, &isbn13
+	}
+	return &isbn10, &isbn13
+}
+
+type BookContributorReq struct {
+	AuthorID uint   `json:"author_id" binding:"required"`
+	Role     string `json:"role" binding:"required,oneof=author editor translator illustrator"`
+}
+
+type BookContributorResp struct {
+	AuthorID uint   `json:"author_id"`
+	FullName string `json:"full_name"`
+	Role     string `json:"role"`
+}
+
+func (o *BookContributorResp) FromEntity(item *dao.BookContributor) {
+	o.AuthorID = item.AuthorID
+	o.Role = string(item.Role)
+	if item.Author != nil {
+		o.FullName = item.Author.FullName
+	}
+}
+
+// contributorEntities positions the contributors by their order, dropping
+// repeats of an author in the same role, and returns them along with the
+// primary author: the first one credited as author, or else the first one.
+// Without contributors authorID is credited as the sole author.
+func contributorEntities(authorID uint, items []BookContributorReq) (uint, []dao.BookContributor) {
+	if len(items) == 0 {
+		if authorID == 0 {
+			return 0, nil
+		}
+		return authorID, []dao.BookContributor{
+			{AuthorID: authorID, Role: domain.ContributorAuthor},
+		}
+	}
+
+	type key struct {
+		authorID uint
+		role     domain.TypeContributorRole
+	}
+	seen := make(map[key]bool, len(items))
+
+	var primaryID uint
+	entities := make([]dao.BookContributor, 0, len(items))
+	for _, item := range items {
+		k := key{item.AuthorID, domain.TypeContributorRole(item.Role)}
+		if seen[k] {
+			continue
+		}
+		seen[k] = true
+
+		if primaryID == 0 && k.role == domain.ContributorAuthor {
+			primaryID = k.authorID
+		}
+		entities = append(entities, dao.BookContributor{
+			AuthorID: k.authorID,
+			Role:     k.role,
+			Position: len(entities),
+		})
+	}
+	if primaryID == 0 {
+		primaryID = entities[0].AuthorID
+	}
+
+	return primaryID, entities
+}
+
+type AuthorWorkFilter struct {
+	Role string `form:"role" binding:"omitempty,oneof=author editor translator illustrator"`
+}
+
+// AuthorWorkResp is a book an author contributed to in Role.
+type AuthorWorkResp struct {
+	Role     string  `json:"role"`
+	BookID   uint    `json:"book_id"`
+	Title    string  `json:"title"`
+	Subtitle *string `json:"subtitle"`
+	ISBN13   *string `json:"isbn_13"`
+}
+
+func (o *AuthorWorkResp) FromEntity(item *dao.BookContributor) {
+	o.Role = string(item.Role)
+	o.BookID = item.BookID
+	if item.Book != nil {
+		o.Title = item.Book.Title
+		o.Subtitle = item.Book.Subtitle
+		o.ISBN13 = item.Book.ISBN13
+	}
+}
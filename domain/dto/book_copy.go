@@ -0,0 +1,61 @@
+package dto
+
+import (
+	"base-gin/domain"
+	"base-gin/domain/dao"
+	"time"
+)
+
+type BookCopyReq struct {
+	ID            uint    `json:"-"`
+	BookID        uint    `json:"-"`
+	Barcode       string  `json:"barcode" binding:"required,min=1,max=32"`
+	AcquiredAtStr string  `json:"acquired_at" binding:"omitempty,datetime=2006-01-02"`
+	ShelfLocation *string `json:"shelf_location" binding:"omitempty,max=32"`
+	Status        string  `json:"status" binding:"omitempty,oneof=available lost damaged withdrawn"`
+}
+
+func (o *BookCopyReq) ToEntity() (dao.BookCopy, error) {
+	item := dao.BookCopy{
+		ID:            o.ID,
+		BookID:        o.BookID,
+		Barcode:       o.Barcode,
+		ShelfLocation: o.ShelfLocation,
+		Status:        domain.CopyAvailable,
+	}
+	if o.Status != "" {
+		item.Status = domain.TypeCopyStatus(o.Status)
+	}
+
+	if o.AcquiredAtStr != "" {
+		acquiredAt, err := time.Parse("2006-01-02", o.AcquiredAtStr)
+		if err != nil {
+			return item, err
+		}
+		item.AcquiredAt = &acquiredAt
+	}
+
+	return item, nil
+}
+
+type BookCopyResp struct {
+	ID            uint    `json:"id"`
+	BookID        uint    `json:"book_id"`
+	Barcode       string  `json:"barcode"`
+	AcquiredAt    *string `json:"acquired_at"`
+	ShelfLocation *string `json:"shelf_location"`
+	Status        string  `json:"status"`
+}
+
+func (o *BookCopyResp) FromEntity(item *dao.BookCopy) {
+	o.ID = item.ID
+	o.BookID = item.BookID
+	o.Barcode = item.Barcode
+	o.ShelfLocation = item.ShelfLocation
+	o.Status = string(item.Status)
+	o.AcquiredAt = nil
+	if item.AcquiredAt != nil {
+		acquiredAt := item.AcquiredAt.Format("2006-01-02")
+		o.AcquiredAt = &acquiredAt
+	}
+}
@@ -1,50 +1,100 @@
 package dto
 
 import (
+	"base-gin/domain"
 	"base-gin/domain/dao"
 	"time"
 )
 
-type BorrowingDTO struct {
-	BookID     uint       `json:"book_id" binding:"required"`
-	PersonID   uint       `json:"person_id" binding:"required"`
-	BorrowDate time.Time  `json:"borrow_date" binding:"required"`
-	ReturnDate *time.Time `json:"return_date"`
+type BorrowingBookResp struct {
+	ID    uint   `json:"id"`
+	Title string `json:"title"`
 }
 
-func (b *BorrowingDTO) ToEntity() dao.Borrowing {
-	return dao.Borrowing{
-		BookID:     b.BookID,
-		PersonID:   b.PersonID,
-		BorrowDate: b.BorrowDate,
-		ReturnDate: b.ReturnDate,
-	}
+type BorrowingPersonResp struct {
+	ID       uint   `json:"id"`
+	Fullname string `json:"fullname"`
 }
 
 type BorrowingResp struct {
-	ID         uint       `json:"id"`
-	BookID     uint       `json:"book_id"`
-	PersonID   uint       `json:"person_id"`
-	BorrowDate time.Time  `json:"borrow_date"`
-	ReturnDate *time.Time `json:"return_date,omitempty"`
+	ID           uint                 `json:"id"`
+	BookID       uint                 `json:"book_id"`
+	BookCopyID   *uint                `json:"book_copy_id"`
+	PersonID     uint                 `json:"person_id"`
+	BorrowDate   time.Time            `json:"borrow_date"`
+	DueDate      time.Time            `json:"due_date"`
+	RenewalCount int                  `json:"renewal_count"`
+	ReturnDate   *time.Time           `json:"return_date,omitempty"`
+	Status       string               `json:"status"`
+	Overdue      bool                 `json:"overdue"`
+	DaysOverdue  int                  `json:"days_overdue"`
+	Book         *BorrowingBookResp   `json:"book,omitempty"`
+	Person       *BorrowingPersonResp `json:"person,omitempty"`
 }
 
-func (b *BorrowingResp) FromEntity(borrowing *dao.Borrowing) {
+// FromEntity fills the response from borrowing, computing its status as of
+// now. Book and person details are included when they were loaded.
+func (b *BorrowingResp) FromEntity(borrowing *dao.Borrowing, now time.Time) {
 	b.ID = borrowing.ID
 	b.BookID = borrowing.BookID
+	b.BookCopyID = borrowing.BookCopyID
 	b.PersonID = borrowing.PersonID
 	b.BorrowDate = borrowing.BorrowDate
+	b.DueDate = borrowing.DueDate
+	b.RenewalCount = borrowing.RenewalCount
 	b.ReturnDate = borrowing.ReturnDate
+	b.Overdue = borrowing.IsOverdue(now)
+	b.DaysOverdue = borrowing.DaysOverdue(now)
+
+	switch {
+	case borrowing.ReturnDate != nil:
+		b.Status = string(domain.BorrowingReturned)
+	case b.Overdue:
+		b.Status = string(domain.BorrowingOverdue)
+	default:
+		b.Status = string(domain.BorrowingActive)
+	}
+
+	if borrowing.Book.ID != 0 {
+		b.Book = &BorrowingBookResp{ID: borrowing.Book.ID, Title: borrowing.Book.Title}
+	}
+	if borrowing.Person.ID != 0 {
+		b.Person = &BorrowingPersonResp{ID: borrowing.Person.ID, Fullname: borrowing.Person.Fullname}
+	}
+}
+
+// BorrowingFilter narrows down a borrowing history. From & To bound the borrow
+// date, both days included.
+type BorrowingFilter struct {
+	Filter
+	PersonID uint       `form:"-"`
+	BookID   uint       `form:"-"`
+	Status   string     `form:"status" binding:"omitempty,oneof=active returned overdue"`
+	From     *time.Time `form:"from" time_format:"2006-01-02"`
+	To       *time.Time `form:"to" time_format:"2006-01-02"`
+}
+
+type OverdueFilter struct {
+	MinDays int `form:"min_days" binding:"omitempty,min=0"`
+}
+
+type BorrowingRenewReq struct {
+	ID          uint
+	RenewedByID *uint
 }
 
-type BorrowingUpdate struct {
-    ID         uint       `json:"-"`
-    ReturnDate *time.Time `json:"return_date"`
+type BorrowingRenewalResp struct {
+	ID              uint      `json:"id"`
+	PreviousDueDate time.Time `json:"previous_due_date"`
+	NewDueDate      time.Time `json:"new_due_date"`
+	RenewedByID     *uint     `json:"renewed_by_id"`
+	CreatedAt       time.Time `json:"created_at"`
 }
 
-func (b *BorrowingUpdate) ToEntity() *dao.Borrowing {
-    return &dao.Borrowing{
-        ID:         b.ID,
-        ReturnDate: b.ReturnDate,
-    }
+func (b *BorrowingRenewalResp) FromEntity(item *dao.BorrowingRenewal) {
+	b.ID = item.ID
+	b.PreviousDueDate = item.PreviousDueDate
+	b.NewDueDate = item.NewDueDate
+	b.RenewedByID = item.RenewedByID
+	b.CreatedAt = item.CreatedAt
 }
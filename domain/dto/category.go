@@ -0,0 +1,65 @@
+package dto
+
+import (
+	"base-gin/domain/dao"
+	"base-gin/util"
+)
+
+type CategoryReq struct {
+	ID          uint    `json:"-"`
+	ParentID    *uint   `json:"parent_id" binding:"omitempty,min=1"`
+	Name        string  `json:"name" binding:"required,min=2,max=64"`
+	Slug        string  `json:"slug" binding:"omitempty,max=64"`
+	Description *string `json:"description" binding:"omitempty,max=255"`
+}
+
+// ToEntity slugifies the slug, or the name when no slug is given.
+func (o *CategoryReq) ToEntity() dao.Category {
+	slug := o.Slug
+	if slug == "" {
+		slug = o.Name
+	}
+
+	return dao.Category{
+		ID:          o.ID,
+		ParentID:    o.ParentID,
+		Name:        o.Name,
+		Slug:        util.Slugify(slug),
+		Description: o.Description,
+	}
+}
+
+// CategoryResp is a category along with the number of books filed under it
+// and under it or any of its descendants. Children and Path, the ancestors
+// from the top one down, are only given where noted.
+type CategoryResp struct {
+	ID             uint              `json:"id"`
+	ParentID       *uint             `json:"parent_id"`
+	Name           string            `json:"name"`
+	Slug           string            `json:"slug"`
+	Description    *string           `json:"description"`
+	BookCount      int               `json:"book_count"`
+	TotalBookCount int               `json:"total_book_count"`
+	Path           []CategoryRefResp `json:"path,omitempty"`
+	Children       []CategoryResp    `json:"children,omitempty"`
+}
+
+func (o *CategoryResp) FromEntity(item *dao.Category) {
+	o.ID = item.ID
+	o.ParentID = item.ParentID
+	o.Name = item.Name
+	o.Slug = item.Slug
+	o.Description = item.Description
+}
+
+type CategoryRefResp struct {
+	ID   uint   `json:"id"`
+	Name string `json:"name"`
+	Slug string `json:"slug"`
+}
+
+func (o *CategoryRefResp) FromEntity(item *dao.Category) {
+	o.ID = item.ID
+	o.Name = item.Name
+	o.Slug = item.Slug
+}
@@ -0,0 +1,25 @@
+package dto
+
+// CheckoutReq lends a book out to a person, either a given copy by its barcode
+// or any available copy of the book.
+type CheckoutReq struct {
+	PersonID uint   `json:"person_id" binding:"required"`
+	BookID   uint   `json:"book_id" binding:"required_without=Barcode"`
+	Barcode  string `json:"barcode" binding:"required_without=BookID,max=32"`
+}
+
+// CheckinReq returns the loan of a copy by its barcode, or of a book by the
+// person who borrowed it.
+type CheckinReq struct {
+	PersonID uint   `json:"person_id" binding:"required_with=BookID"`
+	BookID   uint   `json:"book_id" binding:"required_without=Barcode"`
+	Barcode  string `json:"barcode" binding:"required_without=BookID,max=32"`
+}
+
+// BorrowingLookup narrows down the borrowings of a book or copy. Zero fields
+// match any.
+type BorrowingLookup struct {
+	PersonID   uint
+	BookID     uint
+	BookCopyID uint
+}
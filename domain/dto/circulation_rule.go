@@ -0,0 +1,52 @@
+package dto
+
+import "base-gin/domain/dao"
+
+type CirculationRuleReq struct {
+	ID             uint   `json:"-"`
+	PatronCategory string `json:"patron_category" binding:"required,max=32"`
+	ItemType       string `json:"item_type" binding:"required,max=32"`
+	MaxLoans       int    `json:"max_loans" binding:"min=0"`
+	LoanPeriodDays int    `json:"loan_period_days" binding:"required,min=1"`
+	MaxRenewals    int    `json:"max_renewals" binding:"min=0"`
+}
+
+func (o *CirculationRuleReq) ToEntity() dao.CirculationRule {
+	return dao.CirculationRule{
+		ID:             o.ID,
+		PatronCategory: o.PatronCategory,
+		ItemType:       o.ItemType,
+		MaxLoans:       o.MaxLoans,
+		LoanPeriodDays: o.LoanPeriodDays,
+		MaxRenewals:    o.MaxRenewals,
+	}
+}
+
+type CirculationRuleResp struct {
+	ID             uint   `json:"id"`
+	PatronCategory string `json:"patron_category"`
+	ItemType       string `json:"item_type"`
+	MaxLoans       int    `json:"max_loans"`
+	LoanPeriodDays int    `json:"loan_period_days"`
+	MaxRenewals    int    `json:"max_renewals"`
+}
+
+func (o *CirculationRuleResp) FromEntity(item *dao.CirculationRule) {
+	o.ID = item.ID
+	o.PatronCategory = item.PatronCategory
+	o.ItemType = item.ItemType
+	o.MaxLoans = item.MaxLoans
+	o.LoanPeriodDays = item.LoanPeriodDays
+	o.MaxRenewals = item.MaxRenewals
+}
+
+type PersonCategoryReq struct {
+	Category string `json:"category" binding:"required,max=32,ne=*"`
+}
+
+// PolicyViolationResp tells which check and rule blocked an action. RuleID is
+// empty when the configured defaults applied.
+type PolicyViolationResp struct {
+	Code   string `json:"code"`
+	RuleID *uint  `json:"rule_id,omitempty"`
+}
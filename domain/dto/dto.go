@@ -1,9 +1,10 @@
 package dto
 
 type SuccessResponse[T any] struct {
-	Success bool   `json:"success" binding:"default:true" example:"true"`
-	Message string `json:"message"`
-	Data    T      `json:"data,omitempty"`
+	Success bool      `json:"success" binding:"default:true" example:"true"`
+	Message string    `json:"message"`
+	Data    T         `json:"data,omitempty"`
+	Meta    *ListMeta `json:"meta,omitempty"`
 }
 
 type ErrorResponse struct {
@@ -28,4 +29,19 @@ type Filter struct {
 	Keyword string `form:"q" binding:"omitempty"`
 	Start   int    `form:"s" binding:"omitempty,min=0"`
 	Limit   int    `form:"l" binding:"omitempty,min=1"`
+	// Sort orders by the given fields, comma separated, each descending when
+	// prefixed with a minus, e.g. "-created_at,name".
+	Sort string `form:"sort" binding:"omitempty,max=100"`
+	// Cursor continues from the next or prev cursor of a page in place of
+	// the offset. The sort must stay the same.
+	Cursor string `form:"cursor" binding:"omitempty,max=1000"`
+}
+
+// ListMeta describes a page of a list. The cursors are only given for
+// limited pages with more items that way.
+type ListMeta struct {
+	Total      int64  `json:"total"`
+	Limit      int    `json:"limit,omitempty"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	PrevCursor string `json:"prev_cursor,omitempty"`
 }
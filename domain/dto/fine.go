@@ -0,0 +1,79 @@
+package dto
+
+import (
+	"base-gin/domain"
+	"base-gin/domain/dao"
+	"time"
+)
+
+type FinePaymentReq struct {
+	PersonID    uint    `json:"-"`
+	CreatedByID uint    `json:"-"`
+	Amount      int64   `json:"amount" binding:"required,min=1"`
+	Note        *string `json:"note" binding:"omitempty,max=255"`
+}
+
+func (o *FinePaymentReq) ToEntity() dao.FineEntry {
+	return dao.FineEntry{
+		PersonID:    o.PersonID,
+		Type:        domain.FinePayment,
+		Amount:      o.Amount,
+		Reason:      o.Note,
+		CreatedByID: &o.CreatedByID,
+	}
+}
+
+type FineWaiverReq struct {
+	PersonID    uint   `json:"-"`
+	CreatedByID uint   `json:"-"`
+	BorrowingID *uint  `json:"borrowing_id"`
+	Amount      int64  `json:"amount" binding:"required,min=1"`
+	Reason      string `json:"reason" binding:"required,min=3,max=255"`
+}
+
+func (o *FineWaiverReq) ToEntity() dao.FineEntry {
+	return dao.FineEntry{
+		PersonID:    o.PersonID,
+		BorrowingID: o.BorrowingID,
+		Type:        domain.FineWaiver,
+		Amount:      o.Amount,
+		Reason:      &o.Reason,
+		CreatedByID: &o.CreatedByID,
+	}
+}
+
+type FineEntryResp struct {
+	ID          uint      `json:"id"`
+	BorrowingID *uint     `json:"borrowing_id"`
+	Type        string    `json:"type"`
+	Amount      int64     `json:"amount"`
+	Reason      *string   `json:"reason"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+func (o *FineEntryResp) FromEntity(item *dao.FineEntry) {
+	o.ID = item.ID
+	o.BorrowingID = item.BorrowingID
+	o.Type = string(item.Type)
+	o.Amount = item.Amount
+	o.Reason = item.Reason
+	o.CreatedAt = item.CreatedAt
+}
+
+type FineBalanceResp struct {
+	PersonID uint   `json:"person_id"`
+	Fullname string `json:"fullname"`
+	Balance  int64  `json:"balance"`
+}
+
+func (o *FineBalanceResp) FromEntity(item *dao.FineBalance) {
+	o.PersonID = item.PersonID
+	o.Fullname = item.Fullname
+	o.Balance = item.Balance
+}
+
+type FineLedgerResp struct {
+	PersonID uint            `json:"person_id"`
+	Balance  int64           `json:"balance"`
+	Entries  []FineEntryResp `json:"entries"`
+}
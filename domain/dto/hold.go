@@ -0,0 +1,55 @@
+package dto
+
+import (
+	"base-gin/domain/dao"
+	"time"
+)
+
+type HoldReq struct {
+	AccountID uint `json:"-"`
+	BookID    uint `json:"book_id" binding:"required"`
+	PersonID  uint `json:"person_id"`
+}
+
+func (o *HoldReq) ToEntity() dao.Hold {
+	return dao.Hold{
+		BookID:   o.BookID,
+		PersonID: o.PersonID,
+	}
+}
+
+type HoldFilter struct {
+	Filter
+	BookID   uint   `form:"book_id"`
+	PersonID uint   `form:"person_id"`
+	Status   string `form:"status" binding:"omitempty,oneof=waiting ready fulfilled cancelled expired"`
+}
+
+type HoldResp struct {
+	ID         uint       `json:"id"`
+	BookID     uint       `json:"book_id"`
+	BookTitle  string     `json:"book_title,omitempty"`
+	PersonID   uint       `json:"person_id"`
+	BookCopyID *uint      `json:"book_copy_id"`
+	Status     string     `json:"status"`
+	Position   int        `json:"position,omitempty"`
+	ReadyAt    *time.Time `json:"ready_at"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// FromEntity fills the response from item. The queue position is left to the
+// caller since it depends on the other holds of the book.
+func (o *HoldResp) FromEntity(item *dao.Hold) {
+	o.ID = item.ID
+	o.BookID = item.BookID
+	o.PersonID = item.PersonID
+	o.BookCopyID = item.BookCopyID
+	o.Status = string(item.Status)
+	o.ReadyAt = item.ReadyAt
+	o.ExpiresAt = item.ExpiresAt
+	o.CreatedAt = item.CreatedAt
+	if item.Book != nil {
+		o.BookTitle = item.Book.Title
+	}
+}
@@ -0,0 +1,29 @@
+package dto
+
+type CatalogImportQuery struct {
+	DryRun bool `form:"dry_run"`
+}
+
+// CatalogImportResp reports an import of the catalog. On a dry run nothing
+// is saved and the counts tell what would have been.
+type CatalogImportResp struct {
+	DryRun            bool                   `json:"dry_run"`
+	Rows              int                    `json:"rows"`
+	Imported          int                    `json:"imported"`
+	Failed            int                    `json:"failed"`
+	AuthorsCreated    int                    `json:"authors_created"`
+	PublishersCreated int                    `json:"publishers_created"`
+	BookIDs           []uint                 `json:"book_ids"`
+	Errors            []CatalogImportRowResp `json:"errors"`
+}
+
+// CatalogImportRowResp tells why a row was skipped. Line is the line of the
+// CSV the row starts on, the header being line 1.
+type CatalogImportRowResp struct {
+	Line   int         `json:"line"`
+	Errors interface{} `json:"errors"`
+	// Field & Err are the failure, told in Errors by the handler. Field
+	// prefixes the fields of a failed validation.
+	Field string `json:"-"`
+	Err   error  `json:"-"`
+}
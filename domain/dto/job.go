@@ -0,0 +1,55 @@
+package dto
+
+import (
+	"base-gin/domain/dao"
+	"time"
+)
+
+type JobRunFilter struct {
+	JobName string `form:"job"`
+	Status  string `form:"status" binding:"omitempty,oneof=running succeeded failed"`
+	Start   int    `form:"s" binding:"omitempty,min=0"`
+	Limit   int    `form:"l" binding:"omitempty,min=1"`
+}
+
+type JobRunResp struct {
+	ID         uint       `json:"id"`
+	JobName    string     `json:"job_name"`
+	Trigger    string     `json:"trigger"`
+	Status     string     `json:"status"`
+	Message    *string    `json:"message,omitempty"`
+	Error      *string    `json:"error,omitempty"`
+	StartedAt  time.Time  `json:"started_at"`
+	FinishedAt *time.Time `json:"finished_at"`
+	DurationMs int64      `json:"duration_ms"`
+}
+
+func (o *JobRunResp) FromEntity(item *dao.JobRun) {
+	o.ID = item.ID
+	o.JobName = item.JobName
+	o.Trigger = string(item.Trigger)
+	o.Status = string(item.Status)
+	o.Message = item.Message
+	o.Error = item.Error
+	o.StartedAt = item.StartedAt
+	o.FinishedAt = item.FinishedAt
+	o.DurationMs = item.DurationMs
+}
+
+// JobResp describes a registered job. NextRun is nil for jobs that only run
+// when triggered.
+type JobResp struct {
+	Name    string      `json:"name"`
+	Spec    string      `json:"spec"`
+	Running bool        `json:"running"`
+	NextRun *time.Time  `json:"next_run"`
+	LastRun *JobRunResp `json:"last_run"`
+}
+
+// OverdueSummary sums up the loans past their due date. LongOverdue counts the
+// ones past the long overdue notice.
+type OverdueSummary struct {
+	Overdue        int `json:"overdue"`
+	LongOverdue    int `json:"long_overdue"`
+	MaxDaysOverdue int `json:"max_days_overdue"`
+}
@@ -0,0 +1,80 @@
+package dto
+
+import (
+	"base-gin/domain/dao"
+	"time"
+)
+
+type NotificationPreferenceReq struct {
+	PersonID      uint    `json:"-"`
+	Email         *string `json:"email" binding:"omitempty,email,max=128"`
+	Phone         *string `json:"phone" binding:"omitempty,e164"`
+	Locale        string  `json:"locale" binding:"required,oneof=id en"`
+	EmailOptOut   bool    `json:"email_opt_out"`
+	SMSOptOut     bool    `json:"sms_opt_out"`
+	WebhookOptOut bool    `json:"webhook_opt_out"`
+}
+
+func (o *NotificationPreferenceReq) ToEntity() dao.NotificationPreference {
+	return dao.NotificationPreference{
+		PersonID:      o.PersonID,
+		Email:         o.Email,
+		Phone:         o.Phone,
+		Locale:        o.Locale,
+		EmailOptOut:   o.EmailOptOut,
+		SMSOptOut:     o.SMSOptOut,
+		WebhookOptOut: o.WebhookOptOut,
+	}
+}
+
+type NotificationPreferenceResp struct {
+	PersonID      uint    `json:"person_id"`
+	Email         *string `json:"email"`
+	Phone         *string `json:"phone"`
+	Locale        string  `json:"locale"`
+	EmailOptOut   bool    `json:"email_opt_out"`
+	SMSOptOut     bool    `json:"sms_opt_out"`
+	WebhookOptOut bool    `json:"webhook_opt_out"`
+}
+
+func (o *NotificationPreferenceResp) FromEntity(item *dao.NotificationPreference) {
+	o.PersonID = item.PersonID
+	o.Email = item.Email
+	o.Phone = item.Phone
+	o.Locale = item.Locale
+	o.EmailOptOut = item.EmailOptOut
+	o.SMSOptOut = item.SMSOptOut
+	o.WebhookOptOut = item.WebhookOptOut
+}
+
+type NotificationDeliveryResp struct {
+	ID          uint      `json:"id"`
+	BorrowingID uint      `json:"borrowing_id"`
+	Kind        string    `json:"kind"`
+	Channel     string    `json:"channel"`
+	DueDate     time.Time `json:"due_date"`
+	Recipient   string    `json:"recipient"`
+	Status      string    `json:"status"`
+	Error       *string   `json:"error,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+func (o *NotificationDeliveryResp) FromEntity(item *dao.NotificationDelivery) {
+	o.ID = item.ID
+	o.BorrowingID = item.BorrowingID
+	o.Kind = string(item.Kind)
+	o.Channel = string(item.Channel)
+	o.DueDate = item.DueDate
+	o.Recipient = item.Recipient
+	o.Status = string(item.Status)
+	o.Error = item.Error
+	o.CreatedAt = item.CreatedAt
+}
+
+// NotificationRunResp counts the notifications of a reminder run. Skipped ones
+// were sent before or could not be addressed.
+type NotificationRunResp struct {
+	Sent    int `json:"sent"`
+	Failed  int `json:"failed"`
+	Skipped int `json:"skipped"`
+}
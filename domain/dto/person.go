@@ -6,11 +6,18 @@ import (
 	"time"
 )
 
+type PersonFilter struct {
+	Filter
+	Gender   string `form:"gender" binding:"omitempty,oneof=m f"`
+	Category string `form:"category" binding:"omitempty,max=32"`
+}
+
 type PersonDetailResp struct {
 	ID       int    `json:"id"`
 	Fullname string `json:"fullname"`
 	Gender   string `json:"gender"`
 	Age      int    `json:"age"`
+	Category string `json:"category"`
 }
 
 func (o *PersonDetailResp) FromEntity(item *dao.Person) {
@@ -32,6 +39,7 @@ func (o *PersonDetailResp) FromEntity(item *dao.Person) {
 	o.Gender = gender
 	o.Age = int(age)
 	o.ID = int(item.ID)
+	o.Category = item.Category
 }
 
 type PersonUpdateReq struct {
@@ -15,6 +15,11 @@ func (o *PublisherCreateReq) ToEntity() dao.Publisher {
 	return item
 }
 
+type PublisherFilter struct {
+	Filter
+	City string `form:"city" binding:"omitempty,max=32"`
+}
+
 type PublisherResp struct {
 	ID   int    `json:"id"`
 	Name string `json:"name"`
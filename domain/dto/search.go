@@ -0,0 +1,45 @@
+package dto
+
+import "base-gin/search"
+
+type SearchFilter struct {
+	Query       string `form:"q" binding:"required,max=100"`
+	PublisherID uint   `form:"publisher_id" binding:"omitempty,min=1"`
+	AuthorID    uint   `form:"author_id" binding:"omitempty,min=1"`
+	Start       int    `form:"s" binding:"omitempty,min=0"`
+	Limit       int    `form:"l" binding:"omitempty,min=1,max=100"`
+}
+
+type SearchResp struct {
+	// Total is the number of matching books, all pages included.
+	Total  int              `json:"total"`
+	Hits   []SearchHitResp  `json:"hits"`
+	Facets SearchFacetsResp `json:"facets"`
+}
+
+// SearchHitResp is a matching book along with its relevance and its matching
+// fields as escaped HTML, the matched words wrapped in <mark> tags.
+type SearchHitResp struct {
+	Score      float64           `json:"score"`
+	Highlights map[string]string `json:"highlights"`
+	Book       BookResp          `json:"book"`
+}
+
+// SearchFacetsResp counts the matching books by publisher & by author, the
+// most common first.
+type SearchFacetsResp struct {
+	Publishers []FacetResp `json:"publishers"`
+	Authors    []FacetResp `json:"authors"`
+}
+
+type FacetResp struct {
+	ID    uint   `json:"id"`
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+func (o *FacetResp) FromFacetCount(item *search.FacetCount) {
+	o.ID = item.ID
+	o.Name = item.Name
+	o.Count = item.Count
+}
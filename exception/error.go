@@ -2,19 +2,91 @@ package exception
 
 import (
 	"errors"
+	"time"
 
 	"github.com/rs/zerolog/log"
 )
 
 var (
-	ErrBearerTokenInvalid = errors.New("format token bearer tidak sesuai")
-	ErrDataNotFound       = errors.New("data tidak ditemukan")
-	ErrDateParsing        = errors.New("periksa input tanggal")
-	ErrUserConflict       = errors.New("akun pengguna sudah terdaftar")
-	ErrUserNotFound       = errors.New("akun tidak ditemukan")
-	ErrUserLoginFailed    = errors.New("username/password salah")
+	ErrBearerTokenInvalid  = errors.New("format token bearer tidak sesuai")
+	ErrBarcodeConflict     = errors.New("barcode sudah terdaftar")
+	ErrBookAvailable       = errors.New("buku tersedia, silakan langsung dipinjam")
+	ErrBookBorrowed        = errors.New("buku sedang dipinjam")
+	ErrBookOnHold          = errors.New("buku sedang disimpan untuk peminjam lain")
+	ErrBorrowingReturned   = errors.New("peminjaman sudah dikembalikan")
+	ErrCategoryConflict    = errors.New("slug kategori sudah terdaftar")
+	ErrCategoryCycle       = errors.New("induk kategori tidak boleh kategori itu sendiri atau turunannya")
+	ErrCategoryHasChildren = errors.New("kategori masih memiliki subkategori")
+	ErrCategoryNotFound    = errors.New("kategori tidak ditemukan")
+	ErrContributorNotFound = errors.New("kontributor bukan penulis yang terdaftar")
+	ErrCopyHasHistory      = errors.New("eksemplar memiliki riwayat peminjaman, ubah statusnya menjadi withdrawn")
+	ErrCopyOnHold          = errors.New("eksemplar sedang disimpan untuk peminjam yang mengantre")
+	ErrCopyOnLoan          = errors.New("eksemplar sedang dipinjam")
+	ErrCopyUnavailable     = errors.New("eksemplar buku tidak tersedia")
+	ErrCursorInvalid       = errors.New("cursor tidak valid untuk urutan ini")
+	ErrDataNotFound        = errors.New("data tidak ditemukan")
+	ErrDateParsing         = errors.New("periksa input tanggal")
+	ErrFineExceedsBalance  = errors.New("jumlah melebihi sisa denda")
+	ErrForbidden           = errors.New("akses ditolak")
+	ErrHoldConflict        = errors.New("sudah mengantre untuk buku ini")
+	ErrHoldInactive        = errors.New("antrean sudah tidak aktif")
+	ErrHoldQueued          = errors.New("buku sedang diantre peminjam lain")
+	ErrImportHeader        = errors.New("kolom CSV tidak dikenal atau kolom wajib tidak ada")
+	ErrImportInvalid       = errors.New("berkas CSV tidak valid")
+	ErrImportNoAuthor      = errors.New("penulis wajib diisi")
+	ErrISBNConflict        = errors.New("ISBN sudah terdaftar")
+	ErrJobNotFound         = errors.New("job tidak ditemukan")
+	ErrJobRunning          = errors.New("job sedang berjalan")
+	ErrPolicyViolation     = errors.New("melanggar aturan peminjaman")
+	ErrRefreshTokenRevoked = errors.New("token refresh sudah tidak berlaku")
+	ErrRequestThrottled    = errors.New("terlalu banyak percobaan, silakan coba lagi nanti")
+	ErrRuleConflict        = errors.New("aturan untuk kategori & jenis ini sudah ada")
+	ErrSchedulerStopped    = errors.New("penjadwal sudah berhenti")
+	ErrSlugInvalid         = errors.New("slug harus mengandung huruf atau angka")
+	ErrSortInvalid         = errors.New("urutan tidak valid")
+	ErrTokenRevoked        = errors.New("token sudah dicabut")
+	ErrUserConflict        = errors.New("akun pengguna sudah terdaftar")
+	ErrUserNotFound        = errors.New("akun tidak ditemukan")
+	ErrUserLoginFailed     = errors.New("username/password salah")
 )
 
+// ThrottledError is returned when a request is rejected for exceeding a rate
+// limit. It matches ErrRequestThrottled with errors.Is.
+type ThrottledError struct {
+	RetryAfter time.Duration
+}
+
+func (e *ThrottledError) Error() string {
+	return ErrRequestThrottled.Error()
+}
+
+func (e *ThrottledError) Unwrap() error {
+	return ErrRequestThrottled
+}
+
+const (
+	PolicyLoanLimit    = "loan_limit_reached"
+	PolicyRenewalLimit = "renewal_limit_reached"
+	PolicyRenewOverdue = "renewal_overdue"
+)
+
+// PolicyViolation is returned when a circulation rule blocks an action. Code
+// tells which check failed and RuleID the rule that set the limit, nil for the
+// configured defaults. It matches ErrPolicyViolation with errors.Is.
+type PolicyViolation struct {
+	Code    string
+	RuleID  *uint
+	Message string
+}
+
+func (e *PolicyViolation) Error() string {
+	return e.Message
+}
+
+func (e *PolicyViolation) Unwrap() error {
+	return ErrPolicyViolation
+}
+
 func LogError(err error, message string) {
 	log.Error().Stack().Err(err).Msg(message)
 }
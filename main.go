@@ -8,7 +8,10 @@ import (
 	"base-gin/server"
 	"base-gin/service"
 	"base-gin/storage"
+	"context"
+	"os"
 
+	"github.com/rs/zerolog/log"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
 )
@@ -35,10 +38,25 @@ import (
 func main() {
 	cfg := config.NewConfig()
 	storage.InitDB(cfg)
-	repository.SetupRepositories()
+
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "migrate":
+			runMigrate(os.Args[2:])
+			return
+		case "role":
+			runRole(os.Args[2:])
+			return
+		}
+	}
+
+	repository.SetupRepositories(&cfg)
 	service.SetupServices(&cfg)
+	if _, err := service.GetSearchService().Rebuild(context.Background()); err != nil {
+		log.Fatal().Err(err).Msg("Failed to build the search index")
+	}
 
-	app := server.Init(&cfg, repository.GetAccountRepo())
+	app := server.Init(&cfg, repository.GetAccountRepo(), repository.GetRevokedTokenRepo())
 	rest.SetupRestHandlers(app)
 
 	// Swagger
@@ -46,5 +64,12 @@ func main() {
 		app.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 	}
 
-	server.Serve(app)
+	jobService := service.GetJobService()
+	if cfg.Scheduler.Enabled {
+		if err := jobService.Start(context.Background()); err != nil {
+			log.Fatal().Err(err).Msg("Failed to start scheduler")
+		}
+	}
+
+	server.Serve(app, jobService.Stop)
 }
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"base-gin/migration"
+	"base-gin/storage"
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+const migrateUsage = `Usage:
+  migrate up        apply every pending migration
+  migrate down [N]  revert the last N applied migrations (default 1)
+  migrate status    list migrations and whether they are applied`
+
+func runMigrate(args []string) {
+	if len(args) < 1 {
+		fmt.Fprintln(os.Stderr, migrateUsage)
+		os.Exit(2)
+	}
+
+	db := storage.GetDB()
+
+	switch args[0] {
+	case "up":
+		done, err := migration.Up(db)
+		for _, m := range done {
+			log.Info().Str("migration", m.String()).Msg("Migration applied")
+		}
+		if err != nil {
+			log.Fatal().Err(err).Msg("Migration failed")
+		}
+		if len(done) == 0 {
+			log.Info().Msg("No pending migration")
+		}
+	case "down":
+		n := 1
+		if len(args) > 1 {
+			var err error
+			if n, err = strconv.Atoi(args[1]); err != nil || n < 1 {
+				log.Fatal().Err(migration.ErrInvalidStep).Str("step", args[1]).Msg("Migration failed")
+			}
+		}
+
+		done, err := migration.Down(db, n)
+		for _, m := range done {
+			log.Info().Str("migration", m.String()).Msg("Migration reverted")
+		}
+		if err != nil {
+			log.Fatal().Err(err).Msg("Migration failed")
+		}
+		if len(done) == 0 {
+			log.Info().Msg("No applied migration")
+		}
+	case "status":
+		items, err := migration.GetStatus(db)
+		if err != nil {
+			log.Fatal().Err(err).Msg("Migration failed")
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED AT")
+		for _, item := range items {
+			appliedAt := "pending"
+			if item.Applied() {
+				appliedAt = item.AppliedAt.Format(time.RFC3339)
+			}
+			fmt.Fprintf(w, "%04d\t%s\t%s\n", item.Version, item.Name, appliedAt)
+		}
+		_ = w.Flush()
+	default:
+		fmt.Fprintln(os.Stderr, migrateUsage)
+		os.Exit(2)
+	}
+}
@@ -0,0 +1,137 @@
+package migration
+
+import (
+	"base-gin/storage"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type account0001 struct {
+	ID        uint `gorm:"primarykey"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	Username  string `gorm:"size:16;not null;uniqueIndex:user_pass;"`
+	Password  string `gorm:"size:255;not null;uniqueIndex:user_pass;"`
+}
+
+func (account0001) TableName() string { return "accounts" }
+
+type person0001 struct {
+	gorm.Model
+	AccountID *uint        `gorm:"uniqueIndex;"`
+	Account   *account0001 `gorm:"foreignKey:AccountID;"`
+	Fullname  string       `gorm:"size:56;not null;"`
+	Gender    *string      `gorm:"type:enum('f','m');"`
+	BirthDate *time.Time
+}
+
+func (person0001) TableName() string { return "persons" }
+
+type publisher0001 struct {
+	gorm.Model
+	Name string `gorm:"size:48;not null;uniqueIndex;"`
+	City string `gorm:"size:32;not null;"`
+}
+
+func (publisher0001) TableName() string { return "publishers" }
+
+type author0001 struct {
+	ID        uint      `gorm:"primarykey;type:bigint"`
+	FullName  string    `gorm:"type:varchar(56);not null"`
+	Gender    string    `gorm:"type:enum('m','f');default:null"`
+	BirthDate time.Time `gorm:"type:datetime;default:null"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	DeletedAt gorm.DeletedAt `gorm:"index"`
+}
+
+func (author0001) TableName() string { return "authors" }
+
+// portablePerson0001 & portableAuthor0001 are person0001 & author0001 without
+// the MySQL-only column types, for the drivers that came after this
+// migration was applied. Migration 17 brings MySQL to the same columns.
+type portablePerson0001 struct {
+	gorm.Model
+	AccountID *uint        `gorm:"uniqueIndex;"`
+	Account   *account0001 `gorm:"foreignKey:AccountID;"`
+	Fullname  string       `gorm:"size:56;not null;"`
+	Gender    *string      `gorm:"size:1;"`
+	BirthDate *time.Time
+}
+
+func (portablePerson0001) TableName() string { return "persons" }
+
+type portableAuthor0001 struct {
+	ID        uint      `gorm:"primarykey"`
+	FullName  string    `gorm:"size:56;not null"`
+	Gender    string    `gorm:"size:1;default:null"`
+	BirthDate time.Time `gorm:"default:null"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	DeletedAt gorm.DeletedAt `gorm:"index"`
+}
+
+func (portableAuthor0001) TableName() string { return "authors" }
+
+type book0001 struct {
+	ID            uint          `gorm:"primaryKey"`
+	Title         string        `gorm:"size:56;"`
+	Subtitle      *string       `gorm:"size:64;"`
+	PublisherID   uint          `gorm:"not null;"`
+	AuthorID      uint          `gorm:"not null"`
+	BookPublisher publisher0001 `gorm:"foreignKey:PublisherID;"`
+	BookAuthor    author0001    `gorm:"foreignKey:AuthorID;"`
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+	DeletedAt     gorm.DeletedAt `gorm:"index"`
+}
+
+func (book0001) TableName() string { return "books" }
+
+type borrowing0001 struct {
+	ID         uint       `gorm:"primaryKey"`
+	BorrowDate time.Time  `gorm:"not null;"`
+	ReturnDate *time.Time `gorm:""`
+	BookID     uint       `gorm:"not null;"`
+	PersonID   uint       `gorm:"not null"`
+	Book       book0001   `gorm:"foreignKey:BookID"`
+	Person     person0001 `gorm:"foreignKey:PersonID"`
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+	DeletedAt  gorm.DeletedAt `gorm:"index"`
+}
+
+func (borrowing0001) TableName() string { return "borrowings" }
+
+func initialSchema() Migration {
+	return Migration{
+		Version: 1,
+		Name:    "initial_schema",
+		Up: func(tx *gorm.DB) error {
+			var person, author interface{} = &person0001{}, &author0001{}
+			if tx.Dialector.Name() != storage.DriverMySQL {
+				person, author = &portablePerson0001{}, &portableAuthor0001{}
+			}
+
+			return tx.Migrator().CreateTable(
+				&account0001{},
+				person,
+				&publisher0001{},
+				author,
+				&book0001{},
+				&borrowing0001{},
+			)
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(
+				&borrowing0001{},
+				&book0001{},
+				&author0001{},
+				&publisher0001{},
+				&person0001{},
+				&account0001{},
+			)
+		},
+	}
+}
@@ -0,0 +1,33 @@
+package migration
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type refreshToken0002 struct {
+	ID         uint        `gorm:"primaryKey"`
+	TokenID    string      `gorm:"size:36;not null;uniqueIndex;"`
+	AccountID  uint        `gorm:"not null;index;"`
+	Account    account0001 `gorm:"foreignKey:AccountID;"`
+	ExpiresAt  time.Time   `gorm:"not null;"`
+	RevokedAt  *time.Time
+	ReplacedBy *string `gorm:"size:36;"`
+	CreatedAt  time.Time
+}
+
+func (refreshToken0002) TableName() string { return "refresh_tokens" }
+
+func createRefreshTokens() Migration {
+	return Migration{
+		Version: 2,
+		Name:    "create_refresh_tokens",
+		Up: func(tx *gorm.DB) error {
+			return tx.Migrator().CreateTable(&refreshToken0002{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&refreshToken0002{})
+		},
+	}
+}
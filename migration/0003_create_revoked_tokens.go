@@ -0,0 +1,43 @@
+package migration
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type account0003 struct {
+	TokensRevokedAt *time.Time
+}
+
+func (account0003) TableName() string { return "accounts" }
+
+type revokedToken0003 struct {
+	TokenID   string    `gorm:"primaryKey;size:36;"`
+	AccountID uint      `gorm:"not null;index;"`
+	ExpiresAt time.Time `gorm:"not null;index;"`
+	CreatedAt time.Time
+}
+
+func (revokedToken0003) TableName() string { return "revoked_tokens" }
+
+func createRevokedTokens() Migration {
+	return Migration{
+		Version: 3,
+		Name:    "create_revoked_tokens",
+		Up: func(tx *gorm.DB) error {
+			if err := tx.Migrator().AddColumn(&account0003{}, "TokensRevokedAt"); err != nil {
+				return err
+			}
+
+			return tx.Migrator().CreateTable(&revokedToken0003{})
+		},
+		Down: func(tx *gorm.DB) error {
+			if err := tx.Migrator().DropTable(&revokedToken0003{}); err != nil {
+				return err
+			}
+
+			return tx.Migrator().DropColumn(&account0003{}, "TokensRevokedAt")
+		},
+	}
+}
@@ -0,0 +1,28 @@
+package migration
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type loginAttempt0004 struct {
+	ThrottleKey string    `gorm:"primaryKey;size:128;"`
+	Attempts    int       `gorm:"not null;"`
+	ExpiresAt   time.Time `gorm:"not null;index;"`
+}
+
+func (loginAttempt0004) TableName() string { return "login_attempts" }
+
+func createLoginAttempts() Migration {
+	return Migration{
+		Version: 4,
+		Name:    "create_login_attempts",
+		Up: func(tx *gorm.DB) error {
+			return tx.Migrator().CreateTable(&loginAttempt0004{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&loginAttempt0004{})
+		},
+	}
+}
@@ -0,0 +1,24 @@
+package migration
+
+import (
+	"gorm.io/gorm"
+)
+
+type account0005 struct {
+	Role string `gorm:"size:16;not null;default:member;"`
+}
+
+func (account0005) TableName() string { return "accounts" }
+
+func addAccountRoles() Migration {
+	return Migration{
+		Version: 5,
+		Name:    "add_account_roles",
+		Up: func(tx *gorm.DB) error {
+			return tx.Migrator().AddColumn(&account0005{}, "Role")
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropColumn(&account0005{}, "Role")
+		},
+	}
+}
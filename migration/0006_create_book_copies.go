@@ -0,0 +1,58 @@
+package migration
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type bookCopy0006 struct {
+	ID            uint       `gorm:"primaryKey"`
+	BookID        uint       `gorm:"not null;index;"`
+	Book          *book0001  `gorm:"foreignKey:BookID;"`
+	Barcode       string     `gorm:"size:32;not null;uniqueIndex;"`
+	AcquiredAt    *time.Time `gorm:"default:null"`
+	ShelfLocation *string    `gorm:"size:32;"`
+	Status        string     `gorm:"size:16;not null;default:available;index;"`
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+func (bookCopy0006) TableName() string { return "book_copies" }
+
+type borrowing0006 struct {
+	BookCopyID *uint `gorm:"index;"`
+}
+
+func (borrowing0006) TableName() string { return "borrowings" }
+
+func createBookCopies() Migration {
+	return Migration{
+		Version: 6,
+		Name:    "create_book_copies",
+		Up: func(tx *gorm.DB) error {
+			if err := tx.Migrator().CreateTable(&bookCopy0006{}); err != nil {
+				return err
+			}
+			if err := tx.Migrator().AddColumn(&borrowing0006{}, "BookCopyID"); err != nil {
+				return err
+			}
+
+			return tx.Migrator().CreateIndex(&borrowing0006{}, "BookCopyID")
+		},
+		Down: func(tx *gorm.DB) error {
+			// SQLite loses the index once a later rollback drops a column of
+			// borrowings.
+			if tx.Migrator().HasIndex(&borrowing0006{}, "BookCopyID") {
+				if err := tx.Migrator().DropIndex(&borrowing0006{}, "BookCopyID"); err != nil {
+					return err
+				}
+			}
+			if err := tx.Migrator().DropColumn(&borrowing0006{}, "BookCopyID"); err != nil {
+				return err
+			}
+
+			return tx.Migrator().DropTable(&bookCopy0006{})
+		},
+	}
+}
@@ -0,0 +1,62 @@
+package migration
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type borrowing0007 struct {
+	ID         uint `gorm:"primaryKey"`
+	BorrowDate time.Time
+	DueDate    *time.Time `gorm:"index;"`
+}
+
+func (borrowing0007) TableName() string { return "borrowings" }
+
+// loanPeriodDays0007 is the default of LOAN_PERIOD_DAYS when due dates came.
+const loanPeriodDays0007 = 14
+
+// addBorrowingDueDate adds the due date of borrowings. Existing borrowings are
+// given the default loan period since the configured one may have changed
+// since they were made.
+func addBorrowingDueDate() Migration {
+	return Migration{
+		Version: 7,
+		Name:    "add_borrowing_due_date",
+		Up: func(tx *gorm.DB) error {
+			if err := tx.Migrator().AddColumn(&borrowing0007{}, "DueDate"); err != nil {
+				return err
+			}
+			if err := tx.Migrator().CreateIndex(&borrowing0007{}, "DueDate"); err != nil {
+				return err
+			}
+
+			var items []borrowing0007
+			return tx.Unscoped().Where("due_date IS NULL").
+				FindInBatches(&items, 200, func(batch *gorm.DB, _ int) error {
+					for _, item := range items {
+						dueDate := item.BorrowDate.AddDate(0, 0, loanPeriodDays0007)
+						err := tx.Model(&borrowing0007{}).
+							Where("id = ?", item.ID).
+							Update("due_date", dueDate).Error
+						if err != nil {
+							return err
+						}
+					}
+					return nil
+				}).Error
+		},
+		Down: func(tx *gorm.DB) error {
+			// Rolling back 0009 on SQLite rebuilds borrowings without its
+			// indexes.
+			if tx.Migrator().HasIndex(&borrowing0007{}, "DueDate") {
+				if err := tx.Migrator().DropIndex(&borrowing0007{}, "DueDate"); err != nil {
+					return err
+				}
+			}
+
+			return tx.Migrator().DropColumn(&borrowing0007{}, "DueDate")
+		},
+	}
+}
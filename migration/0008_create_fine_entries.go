@@ -0,0 +1,34 @@
+package migration
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type fineEntry0008 struct {
+	ID          uint        `gorm:"primaryKey"`
+	PersonID    uint        `gorm:"not null;index;"`
+	Person      *person0001 `gorm:"foreignKey:PersonID;"`
+	BorrowingID *uint       `gorm:"index;"`
+	Type        string      `gorm:"size:16;not null;"`
+	Amount      int64       `gorm:"not null;"`
+	Reason      *string     `gorm:"size:255;"`
+	CreatedByID *uint       `gorm:"default:null"`
+	CreatedAt   time.Time
+}
+
+func (fineEntry0008) TableName() string { return "fine_entries" }
+
+func createFineEntries() Migration {
+	return Migration{
+		Version: 8,
+		Name:    "create_fine_entries",
+		Up: func(tx *gorm.DB) error {
+			return tx.Migrator().CreateTable(&fineEntry0008{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&fineEntry0008{})
+		},
+	}
+}
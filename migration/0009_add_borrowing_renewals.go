@@ -0,0 +1,46 @@
+package migration
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type borrowing0009 struct {
+	RenewalCount int `gorm:"not null;default:0;"`
+}
+
+func (borrowing0009) TableName() string { return "borrowings" }
+
+type borrowingRenewal0009 struct {
+	ID              uint           `gorm:"primaryKey"`
+	BorrowingID     uint           `gorm:"not null;index;"`
+	Borrowing       *borrowing0001 `gorm:"foreignKey:BorrowingID;"`
+	PreviousDueDate time.Time      `gorm:"not null;"`
+	NewDueDate      time.Time      `gorm:"not null;"`
+	RenewedByID     *uint          `gorm:"default:null"`
+	CreatedAt       time.Time
+}
+
+func (borrowingRenewal0009) TableName() string { return "borrowing_renewals" }
+
+func addBorrowingRenewals() Migration {
+	return Migration{
+		Version: 9,
+		Name:    "add_borrowing_renewals",
+		Up: func(tx *gorm.DB) error {
+			if err := tx.Migrator().AddColumn(&borrowing0009{}, "RenewalCount"); err != nil {
+				return err
+			}
+
+			return tx.Migrator().CreateTable(&borrowingRenewal0009{})
+		},
+		Down: func(tx *gorm.DB) error {
+			if err := tx.Migrator().DropTable(&borrowingRenewal0009{}); err != nil {
+				return err
+			}
+
+			return tx.Migrator().DropColumn(&borrowing0009{}, "RenewalCount")
+		},
+	}
+}
@@ -0,0 +1,37 @@
+package migration
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type hold0010 struct {
+	ID         uint          `gorm:"primaryKey"`
+	BookID     uint          `gorm:"not null;index;"`
+	Book       *book0001     `gorm:"foreignKey:BookID;"`
+	PersonID   uint          `gorm:"not null;index;"`
+	Person     *person0001   `gorm:"foreignKey:PersonID;"`
+	BookCopyID *uint         `gorm:"default:null"`
+	BookCopy   *bookCopy0006 `gorm:"foreignKey:BookCopyID;"`
+	Status     string        `gorm:"size:16;not null;default:waiting;index;"`
+	ReadyAt    *time.Time    `gorm:"default:null"`
+	ExpiresAt  *time.Time    `gorm:"default:null;index;"`
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+func (hold0010) TableName() string { return "holds" }
+
+func createHolds() Migration {
+	return Migration{
+		Version: 10,
+		Name:    "create_holds",
+		Up: func(tx *gorm.DB) error {
+			return tx.Migrator().CreateTable(&hold0010{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&hold0010{})
+		},
+	}
+}
@@ -0,0 +1,61 @@
+package migration
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type person0011 struct {
+	Category string `gorm:"size:32;not null;default:general;"`
+}
+
+func (person0011) TableName() string { return "persons" }
+
+type book0011 struct {
+	ItemType string `gorm:"size:32;not null;default:book;"`
+}
+
+func (book0011) TableName() string { return "books" }
+
+type circulationRule0011 struct {
+	ID             uint   `gorm:"primaryKey"`
+	PatronCategory string `gorm:"size:32;not null;uniqueIndex:idx_circulation_rule_scope;"`
+	ItemType       string `gorm:"size:32;not null;uniqueIndex:idx_circulation_rule_scope;"`
+	MaxLoans       int    `gorm:"not null;"`
+	LoanPeriodDays int    `gorm:"not null;"`
+	MaxRenewals    int    `gorm:"not null;"`
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+func (circulationRule0011) TableName() string { return "circulation_rules" }
+
+// createCirculationRules adds patron categories and item types, which existing
+// persons and books get the defaults of, and the rules keyed by them.
+func createCirculationRules() Migration {
+	return Migration{
+		Version: 11,
+		Name:    "create_circulation_rules",
+		Up: func(tx *gorm.DB) error {
+			if err := tx.Migrator().AddColumn(&person0011{}, "Category"); err != nil {
+				return err
+			}
+			if err := tx.Migrator().AddColumn(&book0011{}, "ItemType"); err != nil {
+				return err
+			}
+
+			return tx.Migrator().CreateTable(&circulationRule0011{})
+		},
+		Down: func(tx *gorm.DB) error {
+			if err := tx.Migrator().DropTable(&circulationRule0011{}); err != nil {
+				return err
+			}
+			if err := tx.Migrator().DropColumn(&book0011{}, "ItemType"); err != nil {
+				return err
+			}
+
+			return tx.Migrator().DropColumn(&person0011{}, "Category")
+		},
+	}
+}
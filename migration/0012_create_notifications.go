@@ -0,0 +1,58 @@
+package migration
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type notificationPreference0012 struct {
+	PersonID      uint        `gorm:"primaryKey;autoIncrement:false"`
+	Person        *person0001 `gorm:"foreignKey:PersonID;"`
+	Email         *string     `gorm:"size:128;"`
+	Phone         *string     `gorm:"size:20;"`
+	Locale        string      `gorm:"size:2;not null;"`
+	EmailOptOut   bool        `gorm:"not null;"`
+	SMSOptOut     bool        `gorm:"not null;"`
+	WebhookOptOut bool        `gorm:"not null;"`
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+func (notificationPreference0012) TableName() string { return "notification_preferences" }
+
+type notificationDelivery0012 struct {
+	ID          uint           `gorm:"primaryKey"`
+	PersonID    uint           `gorm:"not null;index;"`
+	Person      *person0001    `gorm:"foreignKey:PersonID;"`
+	BorrowingID uint           `gorm:"not null;index;"`
+	Borrowing   *borrowing0001 `gorm:"foreignKey:BorrowingID;"`
+	Kind        string         `gorm:"size:16;not null;"`
+	Channel     string         `gorm:"size:16;not null;"`
+	DueDate     time.Time      `gorm:"not null;"`
+	Recipient   string         `gorm:"size:128;not null;"`
+	Status      string         `gorm:"size:8;not null;"`
+	Error       *string        `gorm:"size:255;"`
+	CreatedAt   time.Time
+}
+
+func (notificationDelivery0012) TableName() string { return "notification_deliveries" }
+
+func createNotifications() Migration {
+	return Migration{
+		Version: 12,
+		Name:    "create_notifications",
+		Up: func(tx *gorm.DB) error {
+			return tx.Migrator().CreateTable(
+				&notificationPreference0012{},
+				&notificationDelivery0012{},
+			)
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(
+				&notificationDelivery0012{},
+				&notificationPreference0012{},
+			)
+		},
+	}
+}
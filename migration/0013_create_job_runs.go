@@ -0,0 +1,34 @@
+package migration
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type jobRun0013 struct {
+	ID         uint       `gorm:"primaryKey"`
+	JobName    string     `gorm:"size:64;not null;index;"`
+	Trigger    string     `gorm:"size:16;not null;"`
+	Status     string     `gorm:"size:16;not null;index;"`
+	Message    *string    `gorm:"size:255;"`
+	Error      *string    `gorm:"size:255;"`
+	StartedAt  time.Time  `gorm:"not null;index;"`
+	FinishedAt *time.Time `gorm:"default:null"`
+	DurationMs int64      `gorm:"not null;default:0;"`
+}
+
+func (jobRun0013) TableName() string { return "job_runs" }
+
+func createJobRuns() Migration {
+	return Migration{
+		Version: 13,
+		Name:    "create_job_runs",
+		Up: func(tx *gorm.DB) error {
+			return tx.Migrator().CreateTable(&jobRun0013{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&jobRun0013{})
+		},
+	}
+}
@@ -0,0 +1,49 @@
+package migration
+
+import "gorm.io/gorm"
+
+type book0014 struct {
+	ISBN10 *string `gorm:"size:10;uniqueIndex;"`
+	ISBN13 *string `gorm:"size:13;uniqueIndex;"`
+}
+
+func (book0014) TableName() string { return "books" }
+
+func addBookISBN() Migration {
+	return Migration{
+		Version: 14,
+		Name:    "add_book_isbn",
+		Up: func(tx *gorm.DB) error {
+			for _, field := range []string{"ISBN10", "ISBN13"} {
+				if err := tx.Migrator().AddColumn(&book0014{}, field); err != nil {
+					return err
+				}
+				if err := tx.Migrator().CreateIndex(&book0014{}, field); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		},
+		Down: func(tx *gorm.DB) error {
+			// SQLite rebuilds the table to drop a column, losing every index
+			// of it, so the indexes go first.
+			fields := []string{"ISBN13", "ISBN10"}
+			for _, field := range fields {
+				if !tx.Migrator().HasIndex(&book0014{}, field) {
+					continue
+				}
+				if err := tx.Migrator().DropIndex(&book0014{}, field); err != nil {
+					return err
+				}
+			}
+			for _, field := range fields {
+				if err := tx.Migrator().DropColumn(&book0014{}, field); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		},
+	}
+}
@@ -0,0 +1,34 @@
+package migration
+
+import "gorm.io/gorm"
+
+type bookContributor0015 struct {
+	BookID   uint        `gorm:"primaryKey;autoIncrement:false;"`
+	AuthorID uint        `gorm:"primaryKey;autoIncrement:false;index;"`
+	Role     string      `gorm:"primaryKey;size:16;"`
+	Position int         `gorm:"not null;default:0;"`
+	Book     *book0001   `gorm:"foreignKey:BookID;"`
+	Author   *author0001 `gorm:"foreignKey:AuthorID;"`
+}
+
+func (bookContributor0015) TableName() string { return "book_contributors" }
+
+// createBookContributors adds the authors of a book in their roles, crediting
+// the author of every existing book.
+func createBookContributors() Migration {
+	return Migration{
+		Version: 15,
+		Name:    "create_book_contributors",
+		Up: func(tx *gorm.DB) error {
+			if err := tx.Migrator().CreateTable(&bookContributor0015{}); err != nil {
+				return err
+			}
+
+			return tx.Exec(`INSERT INTO book_contributors (book_id, author_id, role, position)
+				SELECT id, author_id, ?, 0 FROM books`, "author").Error
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&bookContributor0015{})
+		},
+	}
+}
@@ -0,0 +1,42 @@
+package migration
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type category0016 struct {
+	ID          uint          `gorm:"primaryKey"`
+	ParentID    *uint         `gorm:"index;"`
+	Parent      *category0016 `gorm:"foreignKey:ParentID;"`
+	Name        string        `gorm:"size:64;not null;"`
+	Slug        string        `gorm:"size:64;not null;uniqueIndex;"`
+	Description *string       `gorm:"size:255;"`
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+func (category0016) TableName() string { return "categories" }
+
+type bookCategory0016 struct {
+	BookID     uint          `gorm:"primaryKey;autoIncrement:false;"`
+	CategoryID uint          `gorm:"primaryKey;autoIncrement:false;index;"`
+	Book       *book0001     `gorm:"foreignKey:BookID;"`
+	Category   *category0016 `gorm:"foreignKey:CategoryID;"`
+}
+
+func (bookCategory0016) TableName() string { return "book_categories" }
+
+func createCategories() Migration {
+	return Migration{
+		Version: 16,
+		Name:    "create_categories",
+		Up: func(tx *gorm.DB) error {
+			return tx.Migrator().CreateTable(&category0016{}, &bookCategory0016{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&bookCategory0016{}, &category0016{})
+		},
+	}
+}
@@ -0,0 +1,48 @@
+package migration
+
+import (
+	"base-gin/storage"
+
+	"gorm.io/gorm"
+)
+
+type person0017 struct {
+	Gender *string `gorm:"size:1;"`
+}
+
+func (person0017) TableName() string { return "persons" }
+
+type author0017 struct {
+	Gender string `gorm:"size:1;default:null"`
+}
+
+func (author0017) TableName() string { return "authors" }
+
+// portableGenderColumns turns the MySQL enum columns of migration 1 into the
+// columns every driver has, the other drivers having them already.
+func portableGenderColumns() Migration {
+	return Migration{
+		Version: 17,
+		Name:    "portable_gender_columns",
+		Up: func(tx *gorm.DB) error {
+			if tx.Dialector.Name() != storage.DriverMySQL {
+				return nil
+			}
+			if err := tx.Migrator().AlterColumn(&person0017{}, "Gender"); err != nil {
+				return err
+			}
+
+			return tx.Migrator().AlterColumn(&author0017{}, "Gender")
+		},
+		Down: func(tx *gorm.DB) error {
+			if tx.Dialector.Name() != storage.DriverMySQL {
+				return nil
+			}
+			if err := tx.Migrator().AlterColumn(&author0001{}, "Gender"); err != nil {
+				return err
+			}
+
+			return tx.Migrator().AlterColumn(&person0001{}, "Gender")
+		},
+	}
+}
@@ -0,0 +1,26 @@
+package migration
+
+import (
+	"gorm.io/gorm"
+)
+
+type account0018 struct {
+	Username string `gorm:"size:16;not null;uniqueIndex;"`
+}
+
+func (account0018) TableName() string { return "accounts" }
+
+// addAccountUsernameIndex makes usernames unique on their own, user_pass only
+// being unique for the pair with the password.
+func addAccountUsernameIndex() Migration {
+	return Migration{
+		Version: 18,
+		Name:    "add_account_username_index",
+		Up: func(tx *gorm.DB) error {
+			return tx.Migrator().CreateIndex(&account0018{}, "Username")
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropIndex(&account0018{}, "Username")
+		},
+	}
+}
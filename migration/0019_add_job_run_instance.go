@@ -0,0 +1,26 @@
+package migration
+
+import (
+	"gorm.io/gorm"
+)
+
+type jobRun0019 struct {
+	Instance string `gorm:"size:64;not null;default:'';"`
+}
+
+func (jobRun0019) TableName() string { return "job_runs" }
+
+// addJobRunInstance records which scheduler instance made a run, existing
+// runs getting none.
+func addJobRunInstance() Migration {
+	return Migration{
+		Version: 19,
+		Name:    "add_job_run_instance",
+		Up: func(tx *gorm.DB) error {
+			return tx.Migrator().AddColumn(&jobRun0019{}, "Instance")
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropColumn(&jobRun0019{}, "Instance")
+		},
+	}
+}
@@ -0,0 +1,234 @@
+package migration
+
+import (
+	"base-gin/domain/dao"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidStep      = errors.New("jumlah langkah migrasi tidak valid")
+	ErrUnknownVersion   = errors.New("versi migrasi tidak dikenali")
+	ErrDuplicateVersion = errors.New("versi migrasi ganda")
+)
+
+// Migration is a single versioned schema change. Up and Down receive a
+// transaction-bound *gorm.DB and must not depend on the current DAO structs,
+// so every migration keeps its own snapshot of the tables it touches.
+type Migration struct {
+	Version uint
+	Name    string
+	Up      func(tx *gorm.DB) error
+	Down    func(tx *gorm.DB) error
+}
+
+func (m Migration) String() string {
+	return fmt.Sprintf("%04d_%s", m.Version, m.Name)
+}
+
+type Status struct {
+	Version   uint
+	Name      string
+	AppliedAt *time.Time
+}
+
+func (s Status) Applied() bool {
+	return s.AppliedAt != nil
+}
+
+// All returns every known migration ordered by version.
+func All() []Migration {
+	items := []Migration{
+		initialSchema(),
+		createRefreshTokens(),
+		createRevokedTokens(),
+		createLoginAttempts(),
+		addAccountRoles(),
+		createBookCopies(),
+		addBorrowingDueDate(),
+		createFineEntries(),
+		addBorrowingRenewals(),
+		createHolds(),
+		createCirculationRules(),
+		createNotifications(),
+		createJobRuns(),
+		addBookISBN(),
+		createBookContributors(),
+		createCategories(),
+		portableGenderColumns(),
+		addAccountUsernameIndex(),
+		addJobRunInstance(),
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].Version < items[j].Version
+	})
+
+	return items
+}
+
+// Up applies every pending migration in version order and returns the ones
+// that were applied.
+func Up(db *gorm.DB) ([]Migration, error) {
+	items, err := load(db)
+	if err != nil {
+		return nil, err
+	}
+
+	applied, err := appliedVersions(db)
+	if err != nil {
+		return nil, err
+	}
+
+	var done []Migration
+	for _, m := range items {
+		if _, ok := applied[m.Version]; ok {
+			continue
+		}
+
+		err := db.Transaction(func(tx *gorm.DB) error {
+			if err := m.Up(tx); err != nil {
+				return err
+			}
+
+			return tx.Create(&dao.SchemaMigration{Version: m.Version, Name: m.Name}).Error
+		})
+		if err != nil {
+			return done, fmt.Errorf("migration %s up: %w", m, err)
+		}
+
+		done = append(done, m)
+	}
+
+	return done, nil
+}
+
+// Down reverts the last n applied migrations, newest first, and returns the
+// ones that were reverted.
+func Down(db *gorm.DB, n int) ([]Migration, error) {
+	if n < 1 {
+		return nil, ErrInvalidStep
+	}
+
+	items, err := load(db)
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[uint]Migration, len(items))
+	for _, m := range items {
+		byVersion[m.Version] = m
+	}
+
+	var records []dao.SchemaMigration
+	tx := db.Order("version DESC").Limit(n).Find(&records)
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+
+	var done []Migration
+	for _, rec := range records {
+		m, ok := byVersion[rec.Version]
+		if !ok {
+			return done, fmt.Errorf("%w: %d", ErrUnknownVersion, rec.Version)
+		}
+
+		err := db.Transaction(func(tx *gorm.DB) error {
+			if err := m.Down(tx); err != nil {
+				return err
+			}
+
+			return tx.Delete(&dao.SchemaMigration{}, m.Version).Error
+		})
+		if err != nil {
+			return done, fmt.Errorf("migration %s down: %w", m, err)
+		}
+
+		done = append(done, m)
+	}
+
+	return done, nil
+}
+
+// Reset reverts every applied migration.
+func Reset(db *gorm.DB) ([]Migration, error) {
+	if err := ensureTable(db); err != nil {
+		return nil, err
+	}
+
+	var count int64
+	if err := db.Model(&dao.SchemaMigration{}).Count(&count).Error; err != nil {
+		return nil, err
+	}
+	if count == 0 {
+		return nil, nil
+	}
+
+	return Down(db, int(count))
+}
+
+// GetStatus lists every known migration along with the time it was applied,
+// if it was.
+func GetStatus(db *gorm.DB) ([]Status, error) {
+	items, err := load(db)
+	if err != nil {
+		return nil, err
+	}
+
+	applied, err := appliedVersions(db)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]Status, len(items))
+	for i, m := range items {
+		result[i] = Status{Version: m.Version, Name: m.Name}
+		if rec, ok := applied[m.Version]; ok {
+			appliedAt := rec.AppliedAt
+			result[i].AppliedAt = &appliedAt
+		}
+	}
+
+	return result, nil
+}
+
+func load(db *gorm.DB) ([]Migration, error) {
+	if err := ensureTable(db); err != nil {
+		return nil, err
+	}
+
+	items := All()
+	for i := 1; i < len(items); i++ {
+		if items[i].Version == items[i-1].Version {
+			return nil, fmt.Errorf("%w: %d", ErrDuplicateVersion, items[i].Version)
+		}
+	}
+
+	return items, nil
+}
+
+func ensureTable(db *gorm.DB) error {
+	if db.Migrator().HasTable(&dao.SchemaMigration{}) {
+		return nil
+	}
+
+	return db.Migrator().CreateTable(&dao.SchemaMigration{})
+}
+
+func appliedVersions(db *gorm.DB) (map[uint]dao.SchemaMigration, error) {
+	var records []dao.SchemaMigration
+	if err := db.Find(&records).Error; err != nil {
+		return nil, err
+	}
+
+	result := make(map[uint]dao.SchemaMigration, len(records))
+	for _, rec := range records {
+		result[rec.Version] = rec
+	}
+
+	return result, nil
+}
@@ -0,0 +1,101 @@
+package notification
+
+import (
+	"base-gin/domain"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// SMSSender sends the message body as a text message through an HTTP gateway,
+// posting the phone number & text as JSON with the token as bearer.
+type SMSSender struct {
+	url    string
+	token  string
+	client *http.Client
+}
+
+func NewSMSSender(url, token string) *SMSSender {
+	return &SMSSender{url: url, token: token, client: &http.Client{}}
+}
+
+func (s *SMSSender) Channel() domain.TypeChannel {
+	return domain.ChannelSMS
+}
+
+func (s *SMSSender) Send(ctx context.Context, msg Message) error {
+	payload := map[string]string{
+		"to":      msg.To,
+		"message": msg.Body,
+	}
+
+	return postJSON(ctx, s.client, s.url, s.token, payload)
+}
+
+// WebhookSender posts every message as JSON to a URL, e.g. of a chat or
+// messaging service doing the delivery itself.
+type WebhookSender struct {
+	url    string
+	client *http.Client
+}
+
+func NewWebhookSender(url string) *WebhookSender {
+	return &WebhookSender{url: url, client: &http.Client{}}
+}
+
+func (s *WebhookSender) Channel() domain.TypeChannel {
+	return domain.ChannelWebhook
+}
+
+type webhookPayload struct {
+	Kind        domain.TypeNotification `json:"kind"`
+	PersonID    uint                    `json:"person_id"`
+	BorrowingID uint                    `json:"borrowing_id"`
+	Subject     string                  `json:"subject"`
+	Body        string                  `json:"body"`
+}
+
+func (s *WebhookSender) Send(ctx context.Context, msg Message) error {
+	payload := webhookPayload{
+		Kind:        msg.Kind,
+		PersonID:    msg.PersonID,
+		BorrowingID: msg.BorrowingID,
+		Subject:     msg.Subject,
+		Body:        msg.Body,
+	}
+
+	return postJSON(ctx, s.client, s.url, "", payload)
+}
+
+// postJSON posts the payload and fails on any status but 2xx.
+func postJSON(ctx context.Context, client *http.Client, url, token string, payload any) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("%s responded %s", url, resp.Status)
+	}
+
+	return nil
+}
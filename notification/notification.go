@@ -0,0 +1,42 @@
+package notification
+
+import (
+	"base-gin/config"
+	"base-gin/domain"
+	"context"
+)
+
+// Message is a rendered notification about a borrowing, addressed to To on
+// channels that need an address.
+type Message struct {
+	Kind        domain.TypeNotification
+	PersonID    uint
+	BorrowingID uint
+	To          string
+	Subject     string
+	Body        string
+}
+
+// Sender delivers messages through one channel. Send gives up once ctx is
+// done.
+type Sender interface {
+	Channel() domain.TypeChannel
+	Send(ctx context.Context, msg Message) error
+}
+
+// NewSenders builds a sender for every channel set up in the config.
+func NewSenders(cfg *config.NotificationConfig) []Sender {
+	var senders []Sender
+	if cfg.SMTPHost != "" {
+		senders = append(senders, NewSMTPSender(
+			cfg.SMTPHost, cfg.SMTPPort, cfg.SMTPUsername, cfg.SMTPPassword, cfg.SMTPFrom))
+	}
+	if cfg.SMSGatewayURL != "" {
+		senders = append(senders, NewSMSSender(cfg.SMSGatewayURL, cfg.SMSGatewayToken))
+	}
+	if cfg.WebhookURL != "" {
+		senders = append(senders, NewWebhookSender(cfg.WebhookURL))
+	}
+
+	return senders
+}
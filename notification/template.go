@@ -0,0 +1,116 @@
+package notification
+
+import (
+	"base-gin/domain"
+	"base-gin/domain/dao"
+	"bytes"
+	"errors"
+	"math"
+	"text/template"
+	"time"
+)
+
+var ErrNoTemplate = errors.New("notification template not found")
+
+// ReminderData is what the templates are rendered with.
+type ReminderData struct {
+	Fullname    string
+	Title       string
+	DueDate     time.Time
+	DaysLeft    int
+	DaysOverdue int
+}
+
+// NewReminderData takes the data of a borrowing, with its book & person
+// loaded, as of now.
+func NewReminderData(item *dao.Borrowing, now time.Time) ReminderData {
+	data := ReminderData{
+		Fullname:    item.Person.Fullname,
+		Title:       item.Book.Title,
+		DueDate:     item.DueDate,
+		DaysOverdue: item.DaysOverdue(now),
+	}
+	if item.DueDate.After(now) {
+		data.DaysLeft = int(math.Ceil(item.DueDate.Sub(now).Hours() / 24))
+	}
+
+	return data
+}
+
+type messageTemplate struct {
+	subject *template.Template
+	body    *template.Template
+}
+
+type templateKey struct {
+	kind   domain.TypeNotification
+	locale domain.TypeLocale
+}
+
+var templates = map[templateKey]messageTemplate{
+	{domain.NotifyDueSoon, domain.LocaleID}: newTemplate(
+		"Pengingat: {{.Title}} jatuh tempo {{date .DueDate \"02-01-2006\"}}",
+		`Halo {{.Fullname}},
+
+Buku "{{.Title}}" yang Anda pinjam jatuh tempo pada {{date .DueDate "02-01-2006"}}, {{.DaysLeft}} hari lagi.
+Silakan kembalikan atau perpanjang sebelum tanggal tersebut agar tidak dikenai denda.`,
+	),
+	{domain.NotifyDueSoon, domain.LocaleEN}: newTemplate(
+		"Reminder: {{.Title}} is due on {{date .DueDate \"Jan 2, 2006\"}}",
+		`Hello {{.Fullname}},
+
+The book "{{.Title}}" you borrowed is due on {{date .DueDate "Jan 2, 2006"}}, in {{.DaysLeft}} day(s).
+Please return or renew it by then to avoid a fine.`,
+	),
+	{domain.NotifyLongOverdue, domain.LocaleID}: newTemplate(
+		"Terlambat: {{.Title}} belum dikembalikan",
+		`Halo {{.Fullname}},
+
+Buku "{{.Title}}" yang Anda pinjam sudah terlambat {{.DaysOverdue}} hari sejak jatuh tempo pada {{date .DueDate "02-01-2006"}}.
+Segera kembalikan buku tersebut ke perpustakaan. Denda keterlambatan terus bertambah hingga buku dikembalikan.`,
+	),
+	{domain.NotifyLongOverdue, domain.LocaleEN}: newTemplate(
+		"Overdue: {{.Title}} has not been returned",
+		`Hello {{.Fullname}},
+
+The book "{{.Title}}" you borrowed is {{.DaysOverdue}} day(s) overdue, it was due on {{date .DueDate "Jan 2, 2006"}}.
+Please return it to the library as soon as possible. The late fine keeps adding up until it is returned.`,
+	),
+}
+
+var templateFuncs = template.FuncMap{
+	"date": func(t time.Time, layout string) string {
+		return t.Format(layout)
+	},
+}
+
+func newTemplate(subject, body string) messageTemplate {
+	return messageTemplate{
+		subject: template.Must(template.New("subject").Funcs(templateFuncs).Parse(subject)),
+		body:    template.Must(template.New("body").Funcs(templateFuncs).Parse(body)),
+	}
+}
+
+// Render fills the subject & body of a message of the kind in the locale.
+func Render(kind domain.TypeNotification, locale domain.TypeLocale, data ReminderData) (Message, error) {
+	msg := Message{Kind: kind}
+
+	tmpl, ok := templates[templateKey{kind, locale}]
+	if !ok {
+		return msg, ErrNoTemplate
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.subject.Execute(&buf, data); err != nil {
+		return msg, err
+	}
+	msg.Subject = buf.String()
+
+	buf.Reset()
+	if err := tmpl.body.Execute(&buf, data); err != nil {
+		return msg, err
+	}
+	msg.Body = buf.String()
+
+	return msg, nil
+}
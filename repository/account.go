@@ -1,10 +1,13 @@
 package repository
 
 import (
+	"base-gin/domain"
 	"base-gin/domain/dao"
 	"base-gin/exception"
 	"base-gin/storage"
+	"context"
 	"errors"
+	"time"
 
 	"gorm.io/gorm"
 )
@@ -17,20 +20,24 @@ func NewAccountRepository(db *gorm.DB) *AccountRepository {
 	return &AccountRepository{db: db}
 }
 
-func (r *AccountRepository) Create(newItem *dao.Account) error {
-	ctx, cancelFunc := storage.NewDBContext()
+func (r *AccountRepository) Create(ctx context.Context, newItem *dao.Account) error {
+	ctx, cancelFunc := storage.NewDBContext(ctx)
 	defer cancelFunc()
 
 	tx := r.db.WithContext(ctx).Create(&newItem)
 	if tx.Error != nil {
+		if errors.Is(tx.Error, gorm.ErrDuplicatedKey) {
+			return exception.ErrUserConflict
+		}
+
 		return tx.Error
 	}
 
 	return nil
 }
 
-func (r *AccountRepository) GetByUsername(uname string) (dao.Account, error) {
-	ctx, cancelFunc := storage.NewDBContext()
+func (r *AccountRepository) GetByUsername(ctx context.Context, uname string) (dao.Account, error) {
+	ctx, cancelFunc := storage.NewDBContext(ctx)
 	defer cancelFunc()
 
 	var item dao.Account
@@ -46,3 +53,48 @@ func (r *AccountRepository) GetByUsername(uname string) (dao.Account, error) {
 
 	return item, nil
 }
+
+func (r *AccountRepository) GetByID(ctx context.Context, id uint) (dao.Account, error) {
+	ctx, cancelFunc := storage.NewDBContext(ctx)
+	defer cancelFunc()
+
+	var item dao.Account
+	tx := r.db.WithContext(ctx).First(&item, id)
+	if tx.Error != nil {
+		if errors.Is(tx.Error, gorm.ErrRecordNotFound) {
+			return item, exception.ErrUserNotFound
+		}
+
+		return item, tx.Error
+	}
+
+	return item, nil
+}
+
+func (r *AccountRepository) UpdateTokensRevokedAt(ctx context.Context, id uint, revokedAt time.Time) error {
+	ctx, cancelFunc := storage.NewDBContext(ctx)
+	defer cancelFunc()
+
+	tx := r.db.WithContext(ctx).Model(&dao.Account{}).
+		Where("id = ?", id).
+		Update("tokens_revoked_at", revokedAt)
+
+	return tx.Error
+}
+
+func (r *AccountRepository) UpdateRole(ctx context.Context, id uint, role domain.TypeRole) error {
+	ctx, cancelFunc := storage.NewDBContext(ctx)
+	defer cancelFunc()
+
+	tx := r.db.WithContext(ctx).Model(&dao.Account{}).
+		Where("id = ?", id).
+		Update("role", role)
+	if tx.Error != nil {
+		return tx.Error
+	}
+	if tx.RowsAffected == 0 {
+		return exception.ErrUserNotFound
+	}
+
+	return nil
+}
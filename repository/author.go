@@ -2,7 +2,10 @@ package repository
 
 import (
 	"base-gin/domain/dao"
+	"base-gin/domain/dto"
 	"base-gin/exception"
+	"base-gin/storage"
+	"context"
 	"errors"
 
 	"gorm.io/gorm"
@@ -16,22 +19,60 @@ func NewAuthorRepository(db *gorm.DB) *AuthorRepository {
 	return &AuthorRepository{db: db}
 }
 
-func (r *AuthorRepository) Create(author *dao.Author) error {
-	return r.db.Create(author).Error
+func (r *AuthorRepository) Create(ctx context.Context, author *dao.Author) error {
+	ctx, cancelFunc := storage.NewDBContext(ctx)
+	defer cancelFunc()
+
+	return r.db.WithContext(ctx).Create(author).Error
+}
+
+var authorList = listSpec{
+	keywordColumns: []string{"full_name"},
+	sorts: map[string]string{
+		"id":         "id",
+		"full_name":  "full_name",
+		"created_at": "created_at",
+	},
+	defaultSort: "id",
 }
 
-func (r *AuthorRepository) GetList() ([]dao.Author, error) {
-	var authors []dao.Author
-	err := r.db.Find(&authors).Error
-	if err != nil {
+func (r *AuthorRepository) GetList(ctx context.Context, params *dto.AuthorFilter) ([]dao.Author, dto.ListMeta, error) {
+	ctx, cancelFunc := storage.NewDBContext(ctx)
+	defer cancelFunc()
+
+	tx := r.db.WithContext(ctx)
+	if params.Gender != "" {
+		tx = tx.Where("gender = ?", params.Gender)
+	}
+
+	return findPage[dao.Author](tx, authorList, &params.Filter)
+}
+
+func (r *AuthorRepository) GetByID(ctx context.Context, id uint) (*dao.Author, error) {
+	ctx, cancelFunc := storage.NewDBContext(ctx)
+	defer cancelFunc()
+
+	var author dao.Author
+	err := r.db.WithContext(ctx).First(&author, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, exception.ErrDataNotFound
+	} else if err != nil {
 		return nil, err
 	}
-	return authors, nil
+	return &author, nil
 }
 
-func (r *AuthorRepository) GetByID(id uint) (*dao.Author, error) {
+// GetByName looks an author up by full name, ignoring case. The first one
+// registered wins when several share the name.
+func (r *AuthorRepository) GetByName(ctx context.Context, name string) (*dao.Author, error) {
+	ctx, cancelFunc := storage.NewDBContext(ctx)
+	defer cancelFunc()
+
 	var author dao.Author
-	err := r.db.First(&author, id).Error
+	err := r.db.WithContext(ctx).
+		Where("LOWER(full_name) = LOWER(?)", name).
+		Order("id").
+		First(&author).Error
 	if errors.Is(err, gorm.ErrRecordNotFound) {
 		return nil, exception.ErrDataNotFound
 	} else if err != nil {
@@ -40,16 +81,22 @@ func (r *AuthorRepository) GetByID(id uint) (*dao.Author, error) {
 	return &author, nil
 }
 
-func (r *AuthorRepository) Update(author *dao.Author) error {
-	result := r.db.Model(&dao.Author{}).Where("id = ?", author.ID).Updates(author)
+func (r *AuthorRepository) Update(ctx context.Context, author *dao.Author) error {
+	ctx, cancelFunc := storage.NewDBContext(ctx)
+	defer cancelFunc()
+
+	result := r.db.WithContext(ctx).Model(&dao.Author{}).Where("id = ?", author.ID).Updates(author)
 	if result.RowsAffected == 0 {
 		return exception.ErrDataNotFound
 	}
 	return result.Error
 }
 
-func (r *AuthorRepository) Delete(id uint) error {
-	result := r.db.Delete(&dao.Author{}, id)
+func (r *AuthorRepository) Delete(ctx context.Context, id uint) error {
+	ctx, cancelFunc := storage.NewDBContext(ctx)
+	defer cancelFunc()
+
+	result := r.db.WithContext(ctx).Delete(&dao.Author{}, id)
 	if result.RowsAffected == 0 {
 		return exception.ErrDataNotFound
 	}
@@ -1,10 +1,17 @@
 package repository
 
 import (
+	"base-gin/domain"
 	"base-gin/domain/dao"
-	"errors"	
+	"base-gin/domain/dto"
+	"base-gin/exception"
+	"base-gin/storage"
+	"context"
+	"errors"
 	"time"
+
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type BookRepository struct {
@@ -15,75 +22,343 @@ func NewBookRepository(db *gorm.DB) *BookRepository {
 	return &BookRepository{db: db}
 }
 
-func (r *BookRepository) Create(book *dao.Book) error {
-	return r.db.Create(&book).Error
+func (r *BookRepository) Create(ctx context.Context, book *dao.Book) error {
+	ctx, cancelFunc := storage.NewDBContext(ctx)
+	defer cancelFunc()
+
+	return r.db.WithContext(ctx).Create(&book).Error
+}
+
+var bookList = listSpec{
+	keywordColumns: []string{"title", "subtitle"},
+	sorts: map[string]string{
+		"id":         "id",
+		"title":      "title",
+		"item_type":  "item_type",
+		"created_at": "created_at",
+	},
+	defaultSort: "id",
+}
+
+func (r *BookRepository) GetList(ctx context.Context, params *dto.BookFilter) ([]dao.Book, dto.ListMeta, error) {
+	ctx, cancelFunc := storage.NewDBContext(ctx)
+	defer cancelFunc()
+
+	tx := r.db.WithContext(ctx).
+		Joins("BookPublisher").
+		Joins("BookAuthor")
+	if params.PublisherID > 0 {
+		tx = tx.Where("books.publisher_id = ?", params.PublisherID)
+	}
+	if params.AuthorID > 0 {
+		credited := r.db.Model(&dao.BookContributor{}).
+			Select("book_id").
+			Where("author_id = ?", params.AuthorID)
+		tx = tx.Where("books.author_id = ? OR books.id IN (?)", params.AuthorID, credited)
+	}
+	if params.ItemType != "" {
+		tx = tx.Where("books.item_type = ?", params.ItemType)
+	}
+
+	return findPage[dao.Book](tx, bookList, &params.Filter, withContributors, withCategories)
+}
+
+// GetAll returns every book.
+func (r *BookRepository) GetAll(ctx context.Context) ([]dao.Book, error) {
+	ctx, cancelFunc := storage.NewDBContext(ctx)
+	defer cancelFunc()
+
+	var books []dao.Book
+	err := r.db.WithContext(ctx).
+		Joins("BookPublisher").
+		Joins("BookAuthor").
+		Scopes(withContributors, withCategories).
+		Find(&books).Error
+	return books, err
+}
+
+func (r *BookRepository) GetByID(ctx context.Context, id uint) (dao.Book, error) {
+	ctx, cancelFunc := storage.NewDBContext(ctx)
+	defer cancelFunc()
+
+	var book dao.Book
+	err := r.db.WithContext(ctx).
+		Joins("BookPublisher").
+		Joins("BookAuthor").
+		Scopes(withContributors, withCategories).
+		First(&book, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return book, exception.ErrDataNotFound
+	}
+	return book, err
 }
 
-func (r *BookRepository) GetList() ([]dao.Book, error) {
+// GetListByIDs returns the books of the given IDs in no particular order.
+// Those not found are left out.
+func (r *BookRepository) GetListByIDs(ctx context.Context, ids []uint) ([]dao.Book, error) {
+	ctx, cancelFunc := storage.NewDBContext(ctx)
+	defer cancelFunc()
+
 	var books []dao.Book
-	err := r.db.
+	if len(ids) == 0 {
+		return books, nil
+	}
+	err := r.db.WithContext(ctx).
 		Joins("BookPublisher").
 		Joins("BookAuthor").
+		Scopes(withContributors, withCategories).
+		Where("books.id IN ?", ids).
 		Find(&books).Error
 	return books, err
 }
 
-func (r *BookRepository) GetByID(id uint) (dao.Book, error) {
-    var book dao.Book
-    err := r.db.
-        Joins("BookPublisher").
-        Joins("BookAuthor").
-        First(&book, id).Error
-    if errors.Is(err, gorm.ErrRecordNotFound) {
-        return book, errors.New("book not found")
-    }
-    return book, err
+// GetIDsByPublisherID returns the IDs of the books of the publisher.
+func (r *BookRepository) GetIDsByPublisherID(ctx context.Context, publisherID uint) ([]uint, error) {
+	ctx, cancelFunc := storage.NewDBContext(ctx)
+	defer cancelFunc()
+
+	var ids []uint
+	err := r.db.WithContext(ctx).
+		Model(&dao.Book{}).
+		Where("publisher_id = ?", publisherID).
+		Pluck("id", &ids).Error
+	return ids, err
+}
+
+// GetIDsByAuthorID returns the IDs of the books the author contributed to in
+// any role.
+func (r *BookRepository) GetIDsByAuthorID(ctx context.Context, authorID uint) ([]uint, error) {
+	ctx, cancelFunc := storage.NewDBContext(ctx)
+	defer cancelFunc()
+
+	credited := r.db.Model(&dao.BookContributor{}).
+		Select("book_id").
+		Where("author_id = ?", authorID)
+
+	var ids []uint
+	err := r.db.WithContext(ctx).
+		Model(&dao.Book{}).
+		Where("author_id = ? OR id IN (?)", authorID, credited).
+		Pluck("id", &ids).Error
+	return ids, err
+}
+
+// GetByISBN looks a book up by its normalised ISBN-13.
+func (r *BookRepository) GetByISBN(ctx context.Context, isbn13 string) (dao.Book, error) {
+	ctx, cancelFunc := storage.NewDBContext(ctx)
+	defer cancelFunc()
+
+	var book dao.Book
+	err := r.db.WithContext(ctx).
+		Joins("BookPublisher").
+		Joins("BookAuthor").
+		Scopes(withContributors, withCategories).
+		Where("books.isbn13 = ?", isbn13).
+		First(&book).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return book, exception.ErrDataNotFound
+	}
+	return book, err
+}
+
+// GetByISBNUnscoped is GetByISBN including deleted books, which still hold
+// their ISBN in the unique index.
+func (r *BookRepository) GetByISBNUnscoped(ctx context.Context, isbn13 string) (dao.Book, error) {
+	ctx, cancelFunc := storage.NewDBContext(ctx)
+	defer cancelFunc()
+
+	var book dao.Book
+	err := r.db.WithContext(ctx).
+		Unscoped().
+		Where("isbn13 = ?", isbn13).
+		First(&book).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return book, exception.ErrDataNotFound
+	}
+	return book, err
+}
+
+// LockByID reads a book with a row lock held until the surrounding transaction
+// ends, so only one transaction at a time can act on it.
+func (r *BookRepository) LockByID(ctx context.Context, id uint) (dao.Book, error) {
+	ctx, cancelFunc := storage.NewDBContext(ctx)
+	defer cancelFunc()
+
+	var book dao.Book
+	err := r.db.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		First(&book, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return book, exception.ErrDataNotFound
+	}
+	return book, err
 }
 
 // Tambahkan method baru khusus untuk verifikasi delete
-func (r *BookRepository) GetByIDUnscoped(id uint) (dao.Book, error) {
-    var book dao.Book
-    err := r.db.
-        Unscoped().
-        Joins("BookPublisher").
-        Joins("BookAuthor").
-        First(&book, id).Error
-    if errors.Is(err, gorm.ErrRecordNotFound) {
-        return book, errors.New("book not found")
-    }
-    return book, err
+func (r *BookRepository) GetByIDUnscoped(ctx context.Context, id uint) (dao.Book, error) {
+	ctx, cancelFunc := storage.NewDBContext(ctx)
+	defer cancelFunc()
+
+	var book dao.Book
+	err := r.db.WithContext(ctx).
+		Unscoped().
+		Joins("BookPublisher").
+		Joins("BookAuthor").
+		First(&book, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return book, exception.ErrDataNotFound
+	}
+	return book, err
 }
 
 // Di repository/book.go
-func (r *BookRepository) Update(book *dao.Book) error {
-    result := r.db.Model(&dao.Book{}).Where("id = ?", book.ID).Updates(map[string]interface{}{
-        "title":        book.Title,
-        "subtitle":     book.Subtitle,
-        "publisher_id": book.PublisherID,
-        "author_id":    book.AuthorID,
-        "updated_at":   time.Now(),
-    })
-    
-    if result.Error != nil {
-        return result.Error
-    }
-    
-    if result.RowsAffected == 0 {
-        return errors.New("book not found")
-    }
-    
-    return nil
-}
-
-func (r *BookRepository) Delete(id uint) error {
-    result := r.db.Delete(&dao.Book{}, id)
-    if result.Error != nil {
-        return result.Error
-    }
-    
-    if result.RowsAffected == 0 {
-        return errors.New("book not found")
-    }
-    
-    return nil
+func (r *BookRepository) Update(ctx context.Context, book *dao.Book) error {
+	ctx, cancelFunc := storage.NewDBContext(ctx)
+	defer cancelFunc()
+
+	values := map[string]interface{}{
+		"title":        book.Title,
+		"subtitle":     book.Subtitle,
+		"isbn10":       book.ISBN10,
+		"isbn13":       book.ISBN13,
+		"publisher_id": book.PublisherID,
+		"author_id":    book.AuthorID,
+		"updated_at":   time.Now(),
+	}
+	if book.ItemType != "" {
+		values["item_type"] = book.ItemType
+	}
+
+	result := r.db.WithContext(ctx).Model(&dao.Book{}).Where("id = ?", book.ID).Updates(values)
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return exception.ErrDataNotFound
+	}
+
+	return nil
+}
+
+// ReplaceContributors credits the book to the given contributors only.
+func (r *BookRepository) ReplaceContributors(ctx context.Context, bookID uint, items []dao.BookContributor) error {
+	ctx, cancelFunc := storage.NewDBContext(ctx)
+	defer cancelFunc()
+
+	err := r.db.WithContext(ctx).
+		Where("book_id = ?", bookID).
+		Delete(&dao.BookContributor{}).Error
+	if err != nil || len(items) == 0 {
+		return err
+	}
+
+	for i := range items {
+		items[i].BookID = bookID
+	}
+	return r.db.WithContext(ctx).Create(&items).Error
+}
+
+// ReplaceCategories files the book under the given categories only.
+func (r *BookRepository) ReplaceCategories(ctx context.Context, bookID uint, categoryIDs []uint) error {
+	ctx, cancelFunc := storage.NewDBContext(ctx)
+	defer cancelFunc()
+
+	err := r.db.WithContext(ctx).
+		Where("book_id = ?", bookID).
+		Delete(&dao.BookCategory{}).Error
+	if err != nil || len(categoryIDs) == 0 {
+		return err
+	}
+
+	items := make([]dao.BookCategory, len(categoryIDs))
+	for i, id := range categoryIDs {
+		items[i] = dao.BookCategory{BookID: bookID, CategoryID: id}
+	}
+	return r.db.WithContext(ctx).Create(&items).Error
+}
+
+// categoryBookList lists books like bookList, by title unless asked otherwise.
+var categoryBookList = listSpec{
+	keywordColumns: bookList.keywordColumns,
+	sorts:          bookList.sorts,
+	defaultSort:    "title",
+}
+
+// GetListByCategoryIDs lists the books filed under any of the categories.
+func (r *BookRepository) GetListByCategoryIDs(
+	ctx context.Context,
+	categoryIDs []uint,
+	params *dto.Filter,
+) ([]dao.Book, dto.ListMeta, error) {
+	ctx, cancelFunc := storage.NewDBContext(ctx)
+	defer cancelFunc()
+
+	filed := r.db.Model(&dao.BookCategory{}).
+		Select("book_id").
+		Where("category_id IN ?", categoryIDs)
+	tx := r.db.WithContext(ctx).
+		Joins("BookPublisher").
+		Joins("BookAuthor").
+		Where("books.id IN (?)", filed)
+
+	return findPage[dao.Book](tx, categoryBookList, params, withContributors, withCategories)
+}
+
+// GetContributions lists the books the author contributed to, in the given
+// role when not empty, by title.
+func (r *BookRepository) GetContributions(
+	ctx context.Context,
+	authorID uint,
+	role domain.TypeContributorRole,
+) ([]dao.BookContributor, error) {
+	ctx, cancelFunc := storage.NewDBContext(ctx)
+	defer cancelFunc()
+
+	tx := r.db.WithContext(ctx).
+		InnerJoins("Book").
+		Where("book_contributors.author_id = ?", authorID)
+	if role != "" {
+		tx = tx.Where("book_contributors.role = ?", role)
+	}
+
+	var items []dao.BookContributor
+	err := tx.
+		Order(clause.OrderByColumn{Column: clause.Column{Table: "Book", Name: "title"}}).
+		Order("book_contributors.book_id").
+		Find(&items).Error
+	return items, err
+}
+
+// withContributors loads the contributors of books in the order credited.
+func withContributors(db *gorm.DB) *gorm.DB {
+	return db.
+		Preload("Contributors", func(db *gorm.DB) *gorm.DB {
+			return db.Order("book_contributors.position")
+		}).
+		Preload("Contributors.Author")
+}
+
+// withCategories loads the categories books are filed under by name.
+func withCategories(db *gorm.DB) *gorm.DB {
+	return db.Preload("Categories", func(db *gorm.DB) *gorm.DB {
+		return db.Order("categories.name")
+	})
+}
+
+func (r *BookRepository) Delete(ctx context.Context, id uint) error {
+	ctx, cancelFunc := storage.NewDBContext(ctx)
+	defer cancelFunc()
+
+	result := r.db.WithContext(ctx).Delete(&dao.Book{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return exception.ErrDataNotFound
+	}
+
+	return nil
 }
@@ -0,0 +1,188 @@
+package repository
+
+import (
+	"base-gin/domain"
+	"base-gin/domain/dao"
+	"base-gin/exception"
+	"base-gin/storage"
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type BookCopyRepository struct {
+	db *gorm.DB
+}
+
+func NewBookCopyRepository(db *gorm.DB) *BookCopyRepository {
+	return &BookCopyRepository{db: db}
+}
+
+func (r *BookCopyRepository) Create(ctx context.Context, newItem *dao.BookCopy) error {
+	ctx, cancelFunc := storage.NewDBContext(ctx)
+	defer cancelFunc()
+
+	return r.db.WithContext(ctx).Create(newItem).Error
+}
+
+func (r *BookCopyRepository) GetByID(ctx context.Context, id uint) (dao.BookCopy, error) {
+	ctx, cancelFunc := storage.NewDBContext(ctx)
+	defer cancelFunc()
+
+	var item dao.BookCopy
+	err := r.db.WithContext(ctx).First(&item, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return item, exception.ErrDataNotFound
+	}
+	return item, err
+}
+
+func (r *BookCopyRepository) GetByBarcode(ctx context.Context, barcode string) (dao.BookCopy, error) {
+	ctx, cancelFunc := storage.NewDBContext(ctx)
+	defer cancelFunc()
+
+	var item dao.BookCopy
+	err := r.db.WithContext(ctx).Where(dao.BookCopy{Barcode: barcode}).First(&item).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return item, exception.ErrDataNotFound
+	}
+	return item, err
+}
+
+func (r *BookCopyRepository) GetListByBookID(ctx context.Context, bookID uint) ([]dao.BookCopy, error) {
+	ctx, cancelFunc := storage.NewDBContext(ctx)
+	defer cancelFunc()
+
+	var items []dao.BookCopy
+	err := r.db.WithContext(ctx).
+		Where(dao.BookCopy{BookID: bookID}).
+		Order("id").
+		Find(&items).Error
+	return items, err
+}
+
+// CountByBookIDs returns the number of copies of each given book. Books
+// without copies are left out.
+func (r *BookCopyRepository) CountByBookIDs(ctx context.Context, bookIDs []uint) (map[uint]dao.CopyCount, error) {
+	ctx, cancelFunc := storage.NewDBContext(ctx)
+	defer cancelFunc()
+
+	var rows []dao.CopyCount
+	err := r.db.WithContext(ctx).Model(&dao.BookCopy{}).
+		Select("book_id, COUNT(*) AS total, "+
+			"SUM(CASE WHEN status = ? THEN 1 ELSE 0 END) AS available", domain.CopyAvailable).
+		Where("book_id IN ?", bookIDs).
+		Group("book_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[uint]dao.CopyCount, len(rows))
+	for _, row := range rows {
+		result[row.BookID] = row
+	}
+
+	return result, nil
+}
+
+// LockByID reads a copy with a row lock held until the surrounding
+// transaction ends.
+func (r *BookCopyRepository) LockByID(ctx context.Context, id uint) (dao.BookCopy, error) {
+	ctx, cancelFunc := storage.NewDBContext(ctx)
+	defer cancelFunc()
+
+	var item dao.BookCopy
+	err := r.db.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		First(&item, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return item, exception.ErrDataNotFound
+	}
+	return item, err
+}
+
+// LockAvailableByBookID picks the oldest available copy of a book and locks
+// it. It returns ErrCopyUnavailable when every copy is out or unusable.
+func (r *BookCopyRepository) LockAvailableByBookID(ctx context.Context, bookID uint) (dao.BookCopy, error) {
+	ctx, cancelFunc := storage.NewDBContext(ctx)
+	defer cancelFunc()
+
+	var item dao.BookCopy
+	err := r.db.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where(dao.BookCopy{BookID: bookID, Status: domain.CopyAvailable}).
+		Order("id").
+		First(&item).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return item, exception.ErrCopyUnavailable
+	}
+	return item, err
+}
+
+func (r *BookCopyRepository) HasByBookID(ctx context.Context, bookID uint) (bool, error) {
+	ctx, cancelFunc := storage.NewDBContext(ctx)
+	defer cancelFunc()
+
+	var count int64
+	err := r.db.WithContext(ctx).Model(&dao.BookCopy{}).
+		Where(dao.BookCopy{BookID: bookID}).
+		Count(&count).Error
+	return count > 0, err
+}
+
+func (r *BookCopyRepository) Update(ctx context.Context, item *dao.BookCopy) error {
+	ctx, cancelFunc := storage.NewDBContext(ctx)
+	defer cancelFunc()
+
+	tx := r.db.WithContext(ctx).Model(&dao.BookCopy{}).
+		Where("id = ?", item.ID).
+		Updates(map[string]interface{}{
+			"barcode":        item.Barcode,
+			"acquired_at":    item.AcquiredAt,
+			"shelf_location": item.ShelfLocation,
+			"status":         item.Status,
+		})
+	if tx.Error != nil {
+		return tx.Error
+	}
+	if tx.RowsAffected == 0 {
+		return exception.ErrDataNotFound
+	}
+
+	return nil
+}
+
+func (r *BookCopyRepository) UpdateStatus(ctx context.Context, id uint, status domain.TypeCopyStatus) error {
+	ctx, cancelFunc := storage.NewDBContext(ctx)
+	defer cancelFunc()
+
+	tx := r.db.WithContext(ctx).Model(&dao.BookCopy{}).
+		Where("id = ?", id).
+		Update("status", status)
+	if tx.Error != nil {
+		return tx.Error
+	}
+	if tx.RowsAffected == 0 {
+		return exception.ErrDataNotFound
+	}
+
+	return nil
+}
+
+func (r *BookCopyRepository) Delete(ctx context.Context, id uint) error {
+	ctx, cancelFunc := storage.NewDBContext(ctx)
+	defer cancelFunc()
+
+	tx := r.db.WithContext(ctx).Delete(&dao.BookCopy{}, id)
+	if tx.Error != nil {
+		return tx.Error
+	}
+	if tx.RowsAffected == 0 {
+		return exception.ErrDataNotFound
+	}
+
+	return nil
+}
@@ -1,10 +1,18 @@
 package repository
 
 import (
+	"base-gin/constant"
+	"base-gin/domain"
 	"base-gin/domain/dao"
+	"base-gin/domain/dto"
+	"base-gin/exception"
+	"base-gin/storage"
+	"context"
 	"errors"
 	"time"
+
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type BorrowingRepository struct {
@@ -15,53 +23,283 @@ func NewBorrowingRepository(db *gorm.DB) *BorrowingRepository {
 	return &BorrowingRepository{db: db}
 }
 
-func (r *BorrowingRepository) Create(borrowing *dao.Borrowing) error {
-	return r.db.Create(&borrowing).Error
+func (r *BorrowingRepository) Create(ctx context.Context, borrowing *dao.Borrowing) error {
+	ctx, cancelFunc := storage.NewDBContext(ctx)
+	defer cancelFunc()
+
+	return r.db.WithContext(ctx).Create(&borrowing).Error
+}
+
+var borrowingList = listSpec{
+	keywordColumns: []string{"Book.title", "Person.fullname"},
+	sorts: map[string]string{
+		"id":          "id",
+		"borrow_date": "borrow_date",
+		"due_date":    "due_date",
+		"created_at":  "created_at",
+	},
+	defaultSort: "-borrow_date,-id",
+}
+
+// GetList lists the borrowings matching the filter, the most recent first
+// unless sorted otherwise. Statuses are told as of now.
+func (r *BorrowingRepository) GetList(
+	ctx context.Context,
+	params *dto.BorrowingFilter,
+	now time.Time,
+) ([]dao.Borrowing, dto.ListMeta, error) {
+	ctx, cancelFunc := storage.NewDBContext(ctx)
+	defer cancelFunc()
+
+	tx := r.db.WithContext(ctx).
+		Joins("Book").
+		Joins("Person")
+	if params.PersonID > 0 {
+		tx = tx.Where("borrowings.person_id = ?", params.PersonID)
+	}
+	if params.BookID > 0 {
+		tx = tx.Where("borrowings.book_id = ?", params.BookID)
+	}
+
+	switch domain.TypeBorrowingStatus(params.Status) {
+	case domain.BorrowingActive:
+		tx = tx.Where("borrowings.return_date IS NULL AND borrowings.due_date >= ?", now)
+	case domain.BorrowingOverdue:
+		tx = tx.Where("borrowings.return_date IS NULL AND borrowings.due_date < ?", now)
+	case domain.BorrowingReturned:
+		tx = tx.Where("borrowings.return_date IS NOT NULL")
+	}
+
+	if params.From != nil {
+		tx = tx.Where("borrowings.borrow_date >= ?", *params.From)
+	}
+	if params.To != nil {
+		// The whole day is included.
+		tx = tx.Where("borrowings.borrow_date < ?", params.To.AddDate(0, 0, 1))
+	}
+
+	return findPage[dao.Borrowing](tx, borrowingList, &params.Filter)
 }
 
-func (r *BorrowingRepository) GetList() ([]dao.Borrowing, error) {
+func (r *BorrowingRepository) GetListByPersonID(ctx context.Context, personID uint) ([]dao.Borrowing, error) {
+	ctx, cancelFunc := storage.NewDBContext(ctx)
+	defer cancelFunc()
+
 	var borrowings []dao.Borrowing
-	err := r.db.
+	err := r.db.WithContext(ctx).
 		Joins("Book").
 		Joins("Person").
+		Where(&dao.Borrowing{PersonID: personID}).
 		Find(&borrowings).Error
 	return borrowings, err
 }
 
-func (r *BorrowingRepository) GetByID(id uint) (dao.Borrowing, error) {
-    var borrowing dao.Borrowing
-    err := r.db.First(&borrowing, id).Error
-    if errors.Is(err, gorm.ErrRecordNotFound) {
-        return borrowing, errors.New("borrowing not found")
-    }
-    return borrowing, err
-}
-func (r *BorrowingRepository) Update(borrowing *dao.Borrowing) error {
-    result := r.db.Model(&dao.Borrowing{}).Where("id = ?", borrowing.ID).Updates(map[string]interface{}{
-        "return_date": borrowing.ReturnDate,
-        "updated_at":  time.Now(),
-    })
-    
-    if result.Error != nil {
-        return result.Error
-    }
-    
-    if result.RowsAffected == 0 {
-        return errors.New("borrowing not found")
-    }
-    
-    return nil
-}
-
-func (r *BorrowingRepository) Delete(id uint) error {
-    result := r.db.Delete(&dao.Borrowing{}, id)
-    if result.Error != nil {
-        return result.Error
-    }
-    
-    if result.RowsAffected == 0 {
-        return errors.New("borrowing not found")
-    }
-    
-    return nil
-}
\ No newline at end of file
+func (r *BorrowingRepository) GetByID(ctx context.Context, id uint) (dao.Borrowing, error) {
+	ctx, cancelFunc := storage.NewDBContext(ctx)
+	defer cancelFunc()
+
+	var borrowing dao.Borrowing
+	err := r.db.WithContext(ctx).First(&borrowing, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return borrowing, exception.ErrDataNotFound
+	}
+	return borrowing, err
+}
+
+// LockByID reads a borrowing with a row lock held until the surrounding
+// transaction ends, so only one transaction at a time can act on it.
+func (r *BorrowingRepository) LockByID(ctx context.Context, id uint) (dao.Borrowing, error) {
+	ctx, cancelFunc := storage.NewDBContext(ctx)
+	defer cancelFunc()
+
+	var borrowing dao.Borrowing
+	err := r.db.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		First(&borrowing, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return borrowing, exception.ErrDataNotFound
+	}
+	return borrowing, err
+}
+
+// LockLatest locks the borrowing matching the lookup, the one not returned
+// yet if there is one and the most recent otherwise.
+func (r *BorrowingRepository) LockLatest(ctx context.Context, params *dto.BorrowingLookup) (dao.Borrowing, error) {
+	ctx, cancelFunc := storage.NewDBContext(ctx)
+	defer cancelFunc()
+
+	tx := r.db.WithContext(ctx).Clauses(clause.Locking{Strength: "UPDATE"})
+	if params.PersonID > 0 {
+		tx = tx.Where("person_id = ?", params.PersonID)
+	}
+	if params.BookID > 0 {
+		tx = tx.Where("book_id = ?", params.BookID)
+	}
+	if params.BookCopyID > 0 {
+		tx = tx.Where("book_copy_id = ?", params.BookCopyID)
+	}
+
+	var borrowing dao.Borrowing
+	err := tx.
+		Order("CASE WHEN return_date IS NULL THEN 0 ELSE 1 END").
+		Order("borrow_date DESC, id DESC").
+		First(&borrowing).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return borrowing, exception.ErrDataNotFound
+	}
+	return borrowing, err
+}
+
+// GetOverdue lists the borrowings not returned yet that were due before
+// dueBefore, the earliest due first.
+func (r *BorrowingRepository) GetOverdue(ctx context.Context, dueBefore time.Time) ([]dao.Borrowing, error) {
+	ctx, cancelFunc := storage.NewDBContext(ctx)
+	defer cancelFunc()
+
+	var borrowings []dao.Borrowing
+	err := r.db.WithContext(ctx).
+		Joins("Book").
+		Joins("Person").
+		Where("borrowings.return_date IS NULL AND borrowings.due_date < ?", dueBefore).
+		Order("borrowings.due_date").
+		Find(&borrowings).Error
+	return borrowings, err
+}
+
+// GetDueBetween lists the borrowings not returned yet that are due from from
+// until before to, the earliest due first.
+func (r *BorrowingRepository) GetDueBetween(ctx context.Context, from, to time.Time) ([]dao.Borrowing, error) {
+	ctx, cancelFunc := storage.NewDBContext(ctx)
+	defer cancelFunc()
+
+	var borrowings []dao.Borrowing
+	err := r.db.WithContext(ctx).
+		Joins("Book").
+		Joins("Person").
+		Where("borrowings.return_date IS NULL AND borrowings.due_date >= ? AND borrowings.due_date < ?", from, to).
+		Order("borrowings.due_date").
+		Find(&borrowings).Error
+	return borrowings, err
+}
+
+// HasActiveByBookID tells whether the book is currently out, i.e. has a
+// borrowing without a return date.
+func (r *BorrowingRepository) HasActiveByBookID(ctx context.Context, bookID uint) (bool, error) {
+	ctx, cancelFunc := storage.NewDBContext(ctx)
+	defer cancelFunc()
+
+	var count int64
+	err := r.db.WithContext(ctx).Model(&dao.Borrowing{}).
+		Where("book_id = ? AND return_date IS NULL", bookID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// CountActiveByPersonID counts the borrowings the person has not returned
+// yet, only those of books of itemType unless it is the wildcard.
+func (r *BorrowingRepository) CountActiveByPersonID(ctx context.Context, personID uint, itemType string) (int64, error) {
+	ctx, cancelFunc := storage.NewDBContext(ctx)
+	defer cancelFunc()
+
+	tx := r.db.WithContext(ctx).Model(&dao.Borrowing{}).
+		Where("borrowings.person_id = ? AND borrowings.return_date IS NULL", personID)
+	if itemType != constant.RuleWildcard {
+		tx = tx.Joins("JOIN books ON books.id = borrowings.book_id").
+			Where("books.item_type = ?", itemType)
+	}
+
+	var count int64
+	err := tx.Count(&count).Error
+	return count, err
+}
+
+// HasByBookCopyID tells whether the copy was ever lent out, counting deleted
+// borrowings too.
+func (r *BorrowingRepository) HasByBookCopyID(ctx context.Context, bookCopyID uint) (bool, error) {
+	ctx, cancelFunc := storage.NewDBContext(ctx)
+	defer cancelFunc()
+
+	var count int64
+	err := r.db.WithContext(ctx).Unscoped().Model(&dao.Borrowing{}).
+		Where("book_copy_id = ?", bookCopyID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// Return sets the return date of a borrowing.
+func (r *BorrowingRepository) Return(ctx context.Context, id uint, returnDate time.Time) error {
+	ctx, cancelFunc := storage.NewDBContext(ctx)
+	defer cancelFunc()
+
+	result := r.db.WithContext(ctx).Model(&dao.Borrowing{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"return_date": returnDate,
+		"updated_at":  time.Now(),
+	})
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return exception.ErrDataNotFound
+	}
+
+	return nil
+}
+
+// Renew moves the due date of a borrowing and counts the renewal.
+func (r *BorrowingRepository) Renew(ctx context.Context, id uint, dueDate time.Time) error {
+	ctx, cancelFunc := storage.NewDBContext(ctx)
+	defer cancelFunc()
+
+	result := r.db.WithContext(ctx).Model(&dao.Borrowing{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"due_date":      dueDate,
+		"renewal_count": gorm.Expr("renewal_count + 1"),
+		"updated_at":    time.Now(),
+	})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return exception.ErrDataNotFound
+	}
+
+	return nil
+}
+
+func (r *BorrowingRepository) CreateRenewal(ctx context.Context, renewal *dao.BorrowingRenewal) error {
+	ctx, cancelFunc := storage.NewDBContext(ctx)
+	defer cancelFunc()
+
+	return r.db.WithContext(ctx).Create(renewal).Error
+}
+
+// GetRenewals lists the renewals of a borrowing, the oldest first.
+func (r *BorrowingRepository) GetRenewals(ctx context.Context, borrowingID uint) ([]dao.BorrowingRenewal, error) {
+	ctx, cancelFunc := storage.NewDBContext(ctx)
+	defer cancelFunc()
+
+	var items []dao.BorrowingRenewal
+	err := r.db.WithContext(ctx).
+		Where(&dao.BorrowingRenewal{BorrowingID: borrowingID}).
+		Order("created_at, id").
+		Find(&items).Error
+	return items, err
+}
+
+func (r *BorrowingRepository) Delete(ctx context.Context, id uint) error {
+	ctx, cancelFunc := storage.NewDBContext(ctx)
+	defer cancelFunc()
+
+	result := r.db.WithContext(ctx).Delete(&dao.Borrowing{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return exception.ErrDataNotFound
+	}
+
+	return nil
+}
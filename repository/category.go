@@ -0,0 +1,137 @@
+package repository
+
+import (
+	"base-gin/domain/dao"
+	"base-gin/exception"
+	"base-gin/storage"
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type CategoryRepository struct {
+	db *gorm.DB
+}
+
+func NewCategoryRepository(db *gorm.DB) *CategoryRepository {
+	return &CategoryRepository{db: db}
+}
+
+func (r *CategoryRepository) Create(ctx context.Context, newItem *dao.Category) error {
+	ctx, cancelFunc := storage.NewDBContext(ctx)
+	defer cancelFunc()
+
+	return r.db.WithContext(ctx).Create(newItem).Error
+}
+
+func (r *CategoryRepository) GetByID(ctx context.Context, id uint) (dao.Category, error) {
+	ctx, cancelFunc := storage.NewDBContext(ctx)
+	defer cancelFunc()
+
+	var item dao.Category
+	err := r.db.WithContext(ctx).First(&item, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return item, exception.ErrDataNotFound
+	}
+	return item, err
+}
+
+func (r *CategoryRepository) GetBySlug(ctx context.Context, slug string) (dao.Category, error) {
+	ctx, cancelFunc := storage.NewDBContext(ctx)
+	defer cancelFunc()
+
+	var item dao.Category
+	err := r.db.WithContext(ctx).Where("slug = ?", slug).First(&item).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return item, exception.ErrDataNotFound
+	}
+	return item, err
+}
+
+// GetList returns every category by name. The tree is small enough to be
+// walked in memory.
+func (r *CategoryRepository) GetList(ctx context.Context) ([]dao.Category, error) {
+	ctx, cancelFunc := storage.NewDBContext(ctx)
+	defer cancelFunc()
+
+	var items []dao.Category
+	err := r.db.WithContext(ctx).Order("name, id").Find(&items).Error
+	return items, err
+}
+
+// LockList returns every category like GetList, with the rows locked until the
+// surrounding transaction ends so that the tree does not change while it is
+// checked.
+func (r *CategoryRepository) LockList(ctx context.Context) ([]dao.Category, error) {
+	ctx, cancelFunc := storage.NewDBContext(ctx)
+	defer cancelFunc()
+
+	var items []dao.Category
+	err := r.db.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Order("name, id").
+		Find(&items).Error
+	return items, err
+}
+
+// GetBookLinks returns which books, deleted ones left out, are filed under
+// which categories.
+func (r *CategoryRepository) GetBookLinks(ctx context.Context) ([]dao.BookCategory, error) {
+	ctx, cancelFunc := storage.NewDBContext(ctx)
+	defer cancelFunc()
+
+	var items []dao.BookCategory
+	err := r.db.WithContext(ctx).
+		Joins("JOIN books ON books.id = book_categories.book_id AND books.deleted_at IS NULL").
+		Find(&items).Error
+	return items, err
+}
+
+func (r *CategoryRepository) Update(ctx context.Context, item *dao.Category) error {
+	ctx, cancelFunc := storage.NewDBContext(ctx)
+	defer cancelFunc()
+
+	result := r.db.WithContext(ctx).Model(&dao.Category{}).Where("id = ?", item.ID).Updates(map[string]interface{}{
+		"parent_id":   item.ParentID,
+		"name":        item.Name,
+		"slug":        item.Slug,
+		"description": item.Description,
+		"updated_at":  time.Now(),
+	})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return exception.ErrDataNotFound
+	}
+
+	return nil
+}
+
+// Delete removes the category along with the filing of books under it.
+func (r *CategoryRepository) Delete(ctx context.Context, id uint) error {
+	ctx, cancelFunc := storage.NewDBContext(ctx)
+	defer cancelFunc()
+
+	err := r.db.WithContext(ctx).
+		Where("category_id = ?", id).
+		Delete(&dao.BookCategory{}).Error
+	if err != nil {
+		return err
+	}
+
+	result := r.db.WithContext(ctx).Delete(&dao.Category{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return exception.ErrDataNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"base-gin/constant"
+	"base-gin/domain/dao"
+	"base-gin/exception"
+	"base-gin/storage"
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type CirculationRuleRepository struct {
+	db *gorm.DB
+}
+
+func NewCirculationRuleRepository(db *gorm.DB) *CirculationRuleRepository {
+	return &CirculationRuleRepository{db: db}
+}
+
+func (r *CirculationRuleRepository) Create(ctx context.Context, newItem *dao.CirculationRule) error {
+	ctx, cancelFunc := storage.NewDBContext(ctx)
+	defer cancelFunc()
+
+	err := r.db.WithContext(ctx).Create(newItem).Error
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return exception.ErrRuleConflict
+	}
+	return err
+}
+
+func (r *CirculationRuleRepository) GetByID(ctx context.Context, id uint) (dao.CirculationRule, error) {
+	ctx, cancelFunc := storage.NewDBContext(ctx)
+	defer cancelFunc()
+
+	var item dao.CirculationRule
+	err := r.db.WithContext(ctx).First(&item, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return item, exception.ErrDataNotFound
+	}
+	return item, err
+}
+
+// GetByScope returns the rule set for exactly the patron category and item
+// type, wildcards not expanded.
+func (r *CirculationRuleRepository) GetByScope(ctx context.Context, patronCategory, itemType string) (dao.CirculationRule, error) {
+	ctx, cancelFunc := storage.NewDBContext(ctx)
+	defer cancelFunc()
+
+	var item dao.CirculationRule
+	err := r.db.WithContext(ctx).
+		Where("patron_category = ? AND item_type = ?", patronCategory, itemType).
+		First(&item).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return item, exception.ErrDataNotFound
+	}
+	return item, err
+}
+
+func (r *CirculationRuleRepository) GetList(ctx context.Context) ([]dao.CirculationRule, error) {
+	ctx, cancelFunc := storage.NewDBContext(ctx)
+	defer cancelFunc()
+
+	var items []dao.CirculationRule
+	err := r.db.WithContext(ctx).
+		Order("patron_category, item_type").
+		Find(&items).Error
+	return items, err
+}
+
+// GetMatching returns every rule that applies to the patron category and item
+// type, including the wildcard ones.
+func (r *CirculationRuleRepository) GetMatching(ctx context.Context, patronCategory, itemType string) ([]dao.CirculationRule, error) {
+	ctx, cancelFunc := storage.NewDBContext(ctx)
+	defer cancelFunc()
+
+	var items []dao.CirculationRule
+	err := r.db.WithContext(ctx).
+		Where("patron_category IN ? AND item_type IN ?",
+			[]string{patronCategory, constant.RuleWildcard},
+			[]string{itemType, constant.RuleWildcard}).
+		Find(&items).Error
+	return items, err
+}
+
+func (r *CirculationRuleRepository) Update(ctx context.Context, item *dao.CirculationRule) error {
+	ctx, cancelFunc := storage.NewDBContext(ctx)
+	defer cancelFunc()
+
+	result := r.db.WithContext(ctx).Model(&dao.CirculationRule{}).Where("id = ?", item.ID).Updates(map[string]interface{}{
+		"patron_category":  item.PatronCategory,
+		"item_type":        item.ItemType,
+		"max_loans":        item.MaxLoans,
+		"loan_period_days": item.LoanPeriodDays,
+		"max_renewals":     item.MaxRenewals,
+		"updated_at":       time.Now(),
+	})
+	if errors.Is(result.Error, gorm.ErrDuplicatedKey) {
+		return exception.ErrRuleConflict
+	}
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return exception.ErrDataNotFound
+	}
+
+	return nil
+}
+
+func (r *CirculationRuleRepository) Delete(ctx context.Context, id uint) error {
+	ctx, cancelFunc := storage.NewDBContext(ctx)
+	defer cancelFunc()
+
+	result := r.db.WithContext(ctx).Delete(&dao.CirculationRule{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return exception.ErrDataNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"base-gin/domain"
+	"base-gin/domain/dao"
+	"base-gin/storage"
+	"context"
+
+	"gorm.io/gorm"
+)
+
+// fineBalanceExpr sums a ledger into its balance, charges counting positive
+// and payments & waivers negative.
+const fineBalanceExpr = "COALESCE(SUM(CASE WHEN fine_entries.type = ? THEN fine_entries.amount ELSE -fine_entries.amount END), 0)"
+
+type FineRepository struct {
+	db *gorm.DB
+}
+
+func NewFineRepository(db *gorm.DB) *FineRepository {
+	return &FineRepository{db: db}
+}
+
+func (r *FineRepository) Create(ctx context.Context, newItem *dao.FineEntry) error {
+	ctx, cancelFunc := storage.NewDBContext(ctx)
+	defer cancelFunc()
+
+	return r.db.WithContext(ctx).Create(newItem).Error
+}
+
+// GetListByPersonID returns the ledger of a person, oldest entry first.
+func (r *FineRepository) GetListByPersonID(ctx context.Context, personID uint) ([]dao.FineEntry, error) {
+	ctx, cancelFunc := storage.NewDBContext(ctx)
+	defer cancelFunc()
+
+	var items []dao.FineEntry
+	err := r.db.WithContext(ctx).
+		Where(&dao.FineEntry{PersonID: personID}).
+		Order("created_at, id").
+		Find(&items).Error
+	return items, err
+}
+
+func (r *FineRepository) GetBalance(ctx context.Context, personID uint) (int64, error) {
+	ctx, cancelFunc := storage.NewDBContext(ctx)
+	defer cancelFunc()
+
+	var balance int64
+	err := r.db.WithContext(ctx).Model(&dao.FineEntry{}).
+		Select(fineBalanceExpr, domain.FineCharge).
+		Where("person_id = ?", personID).
+		Scan(&balance).Error
+	return balance, err
+}
+
+// GetOutstanding lists the persons having unsettled fines, the largest
+// balance first.
+func (r *FineRepository) GetOutstanding(ctx context.Context) ([]dao.FineBalance, error) {
+	ctx, cancelFunc := storage.NewDBContext(ctx)
+	defer cancelFunc()
+
+	var items []dao.FineBalance
+	err := r.db.WithContext(ctx).Model(&dao.FineEntry{}).
+		Select("fine_entries.person_id, persons.fullname, "+fineBalanceExpr+" AS balance", domain.FineCharge).
+		Joins("JOIN persons ON persons.id = fine_entries.person_id").
+		Group("fine_entries.person_id, persons.fullname").
+		Having(fineBalanceExpr+" > 0", domain.FineCharge).
+		Order("balance DESC").
+		Scan(&items).Error
+	return items, err
+}
+
+// HasChargeByBorrowingID tells whether the borrowing was already charged.
+func (r *FineRepository) HasChargeByBorrowingID(ctx context.Context, borrowingID uint) (bool, error) {
+	ctx, cancelFunc := storage.NewDBContext(ctx)
+	defer cancelFunc()
+
+	var count int64
+	err := r.db.WithContext(ctx).Model(&dao.FineEntry{}).
+		Where("borrowing_id = ? AND type = ?", borrowingID, domain.FineCharge).
+		Count(&count).Error
+	return count > 0, err
+}
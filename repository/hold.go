@@ -0,0 +1,216 @@
+package repository
+
+import (
+	"base-gin/domain"
+	"base-gin/domain/dao"
+	"base-gin/domain/dto"
+	"base-gin/exception"
+	"base-gin/storage"
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var activeHoldStatuses = []domain.TypeHoldStatus{domain.HoldWaiting, domain.HoldReady}
+
+type HoldRepository struct {
+	db *gorm.DB
+}
+
+func NewHoldRepository(db *gorm.DB) *HoldRepository {
+	return &HoldRepository{db: db}
+}
+
+func (r *HoldRepository) Create(ctx context.Context, newItem *dao.Hold) error {
+	ctx, cancelFunc := storage.NewDBContext(ctx)
+	defer cancelFunc()
+
+	return r.db.WithContext(ctx).Create(newItem).Error
+}
+
+func (r *HoldRepository) GetByID(ctx context.Context, id uint) (dao.Hold, error) {
+	ctx, cancelFunc := storage.NewDBContext(ctx)
+	defer cancelFunc()
+
+	var item dao.Hold
+	err := r.db.WithContext(ctx).Joins("Book").First(&item, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return item, exception.ErrDataNotFound
+	}
+	return item, err
+}
+
+// LockByID reads a hold with a row lock held until the surrounding
+// transaction ends.
+func (r *HoldRepository) LockByID(ctx context.Context, id uint) (dao.Hold, error) {
+	ctx, cancelFunc := storage.NewDBContext(ctx)
+	defer cancelFunc()
+
+	var item dao.Hold
+	err := r.db.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		First(&item, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return item, exception.ErrDataNotFound
+	}
+	return item, err
+}
+
+var holdList = listSpec{
+	keywordColumns: []string{"Book.title"},
+	sorts: map[string]string{
+		"id":         "id",
+		"status":     "status",
+		"created_at": "created_at",
+	},
+	defaultSort: "created_at",
+}
+
+// GetList returns the holds matching params in queue order unless sorted
+// otherwise. Without a status only active holds are returned.
+func (r *HoldRepository) GetList(ctx context.Context, params *dto.HoldFilter) ([]dao.Hold, dto.ListMeta, error) {
+	ctx, cancelFunc := storage.NewDBContext(ctx)
+	defer cancelFunc()
+
+	tx := r.db.WithContext(ctx).Joins("Book")
+	if params.BookID > 0 {
+		tx = tx.Where("holds.book_id = ?", params.BookID)
+	}
+	if params.PersonID > 0 {
+		tx = tx.Where("holds.person_id = ?", params.PersonID)
+	}
+	if params.Status != "" {
+		tx = tx.Where("holds.status = ?", params.Status)
+	} else {
+		tx = tx.Where("holds.status IN ?", activeHoldStatuses)
+	}
+
+	return findPage[dao.Hold](tx, holdList, &params.Filter)
+}
+
+// GetWaitingByBookIDs returns the waiting holds of the given books in queue
+// order, from which queue positions are worked out.
+func (r *HoldRepository) GetWaitingByBookIDs(ctx context.Context, bookIDs []uint) ([]dao.Hold, error) {
+	ctx, cancelFunc := storage.NewDBContext(ctx)
+	defer cancelFunc()
+
+	var items []dao.Hold
+	err := r.db.WithContext(ctx).
+		Where("book_id IN ? AND status = ?", bookIDs, domain.HoldWaiting).
+		Order("created_at, id").
+		Find(&items).Error
+	return items, err
+}
+
+// HasActive tells whether the person is queued for, or has a pickup ready of,
+// the book.
+func (r *HoldRepository) HasActive(ctx context.Context, bookID, personID uint) (bool, error) {
+	ctx, cancelFunc := storage.NewDBContext(ctx)
+	defer cancelFunc()
+
+	var count int64
+	err := r.db.WithContext(ctx).Model(&dao.Hold{}).
+		Where("book_id = ? AND person_id = ? AND status IN ?", bookID, personID, activeHoldStatuses).
+		Count(&count).Error
+	return count > 0, err
+}
+
+func (r *HoldRepository) HasByBookIDAndStatus(ctx context.Context, bookID uint, status domain.TypeHoldStatus) (bool, error) {
+	ctx, cancelFunc := storage.NewDBContext(ctx)
+	defer cancelFunc()
+
+	var count int64
+	err := r.db.WithContext(ctx).Model(&dao.Hold{}).
+		Where(dao.Hold{BookID: bookID, Status: status}).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// LockNextWaiting picks the first waiting hold of a book and locks it. It
+// returns ErrDataNotFound when nobody is waiting.
+func (r *HoldRepository) LockNextWaiting(ctx context.Context, bookID uint) (dao.Hold, error) {
+	ctx, cancelFunc := storage.NewDBContext(ctx)
+	defer cancelFunc()
+
+	var item dao.Hold
+	err := r.db.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where(dao.Hold{BookID: bookID, Status: domain.HoldWaiting}).
+		Order("created_at, id").
+		First(&item).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return item, exception.ErrDataNotFound
+	}
+	return item, err
+}
+
+// LockReady locks the hold of the person ready for pickup of the book. It
+// returns ErrDataNotFound when there is none.
+func (r *HoldRepository) LockReady(ctx context.Context, bookID, personID uint) (dao.Hold, error) {
+	ctx, cancelFunc := storage.NewDBContext(ctx)
+	defer cancelFunc()
+
+	var item dao.Hold
+	err := r.db.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where(dao.Hold{BookID: bookID, PersonID: personID, Status: domain.HoldReady}).
+		First(&item).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return item, exception.ErrDataNotFound
+	}
+	return item, err
+}
+
+// LockExpired locks the holds that were not picked up before they expired.
+func (r *HoldRepository) LockExpired(ctx context.Context, now time.Time) ([]dao.Hold, error) {
+	ctx, cancelFunc := storage.NewDBContext(ctx)
+	defer cancelFunc()
+
+	var items []dao.Hold
+	err := r.db.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("status = ? AND expires_at < ?", domain.HoldReady, now).
+		Order("expires_at, id").
+		Find(&items).Error
+	return items, err
+}
+
+// LockExpiredByBookID locks the holds of the book that were not picked up
+// before they expired.
+func (r *HoldRepository) LockExpiredByBookID(ctx context.Context, bookID uint, now time.Time) ([]dao.Hold, error) {
+	ctx, cancelFunc := storage.NewDBContext(ctx)
+	defer cancelFunc()
+
+	var items []dao.Hold
+	err := r.db.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("book_id = ? AND status = ? AND expires_at < ?", bookID, domain.HoldReady, now).
+		Order("expires_at, id").
+		Find(&items).Error
+	return items, err
+}
+
+func (r *HoldRepository) Update(ctx context.Context, item *dao.Hold) error {
+	ctx, cancelFunc := storage.NewDBContext(ctx)
+	defer cancelFunc()
+
+	result := r.db.WithContext(ctx).Model(&dao.Hold{}).Where("id = ?", item.ID).Updates(map[string]interface{}{
+		"status":       item.Status,
+		"book_copy_id": item.BookCopyID,
+		"ready_at":     item.ReadyAt,
+		"expires_at":   item.ExpiresAt,
+		"updated_at":   time.Now(),
+	})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return exception.ErrDataNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"base-gin/domain"
+	"base-gin/domain/dao"
+	"base-gin/domain/dto"
+	"base-gin/exception"
+	"base-gin/storage"
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type JobRunRepository struct {
+	db *gorm.DB
+}
+
+func NewJobRunRepository(db *gorm.DB) *JobRunRepository {
+	return &JobRunRepository{db: db}
+}
+
+func (r *JobRunRepository) Create(ctx context.Context, newItem *dao.JobRun) error {
+	ctx, cancelFunc := storage.NewDBContext(ctx)
+	defer cancelFunc()
+
+	return r.db.WithContext(ctx).Create(newItem).Error
+}
+
+// Finish stores the outcome of a run.
+func (r *JobRunRepository) Finish(ctx context.Context, item *dao.JobRun) error {
+	ctx, cancelFunc := storage.NewDBContext(ctx)
+	defer cancelFunc()
+
+	return r.db.WithContext(ctx).Model(item).
+		Select("status", "message", "error", "finished_at", "duration_ms").
+		Updates(item).Error
+}
+
+// GetList lists the runs matching the filter, the most recent first.
+func (r *JobRunRepository) GetList(ctx context.Context, params *dto.JobRunFilter) ([]dao.JobRun, error) {
+	ctx, cancelFunc := storage.NewDBContext(ctx)
+	defer cancelFunc()
+
+	tx := r.db.WithContext(ctx)
+	if params.JobName != "" {
+		tx = tx.Where("job_name = ?", params.JobName)
+	}
+	if params.Status != "" {
+		tx = tx.Where("status = ?", params.Status)
+	}
+	if params.Start > 0 {
+		tx = tx.Offset(params.Start)
+	}
+	if params.Limit > 0 {
+		tx = tx.Limit(params.Limit)
+	}
+
+	var items []dao.JobRun
+	err := tx.Order("started_at DESC, id DESC").Find(&items).Error
+	return items, err
+}
+
+func (r *JobRunRepository) GetLatestByJobName(ctx context.Context, jobName string) (dao.JobRun, error) {
+	ctx, cancelFunc := storage.NewDBContext(ctx)
+	defer cancelFunc()
+
+	var item dao.JobRun
+	err := r.db.WithContext(ctx).
+		Where("job_name = ?", jobName).
+		Order("started_at DESC, id DESC").
+		First(&item).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return item, exception.ErrDataNotFound
+	}
+	return item, err
+}
+
+// FailRunning marks the runs of the instance still running as failed. Those
+// were cut short by a shutdown or crash and would otherwise stay running
+// forever. Runs recorded before instances had a name count as the instance's.
+func (r *JobRunRepository) FailRunning(ctx context.Context, instance, reason string) (int64, error) {
+	ctx, cancelFunc := storage.NewDBContext(ctx)
+	defer cancelFunc()
+
+	tx := r.db.WithContext(ctx).Model(&dao.JobRun{}).
+		Where("status = ? AND instance IN ?", domain.JobRunning, []string{instance, ""}).
+		Updates(map[string]interface{}{
+			"status":      domain.JobFailed,
+			"error":       reason,
+			"finished_at": time.Now(),
+		})
+
+	return tx.RowsAffected, tx.Error
+}
+
+// DeleteBefore purges the finished runs started before the given time.
+func (r *JobRunRepository) DeleteBefore(ctx context.Context, before time.Time) (int64, error) {
+	ctx, cancelFunc := storage.NewDBContext(ctx)
+	defer cancelFunc()
+
+	tx := r.db.WithContext(ctx).
+		Where("started_at < ? AND status <> ?", before, domain.JobRunning).
+		Delete(&dao.JobRun{})
+
+	return tx.RowsAffected, tx.Error
+}
@@ -0,0 +1,251 @@
+package repository
+
+import (
+	"base-gin/domain/dto"
+	"base-gin/exception"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// listSpec tells how a resource is listed: the columns the keyword is looked
+// for in, the fields it may be sorted by along with their column, and its
+// order when none is asked for. Keyword columns of joined tables are written
+// as "Table.column"; sort columns are of the resource's own table.
+type listSpec struct {
+	keywordColumns []string
+	sorts          map[string]string
+	defaultSort    string
+}
+
+// sortKey is a column a list is ordered by.
+type sortKey struct {
+	column clause.Column
+	field  string
+	desc   bool
+}
+
+// cursor marks the item a page starts after, or ends before when prev, by
+// its sort values. The sort is kept to reject cursors of another order.
+type cursor struct {
+	Sort   string            `json:"s"`
+	Values []json.RawMessage `json:"v"`
+	Prev   bool              `json:"p,omitempty"`
+}
+
+// findPage finds the items of tx matching the keyword of params, in the order
+// asked for, and pages them from the offset or from the cursor given. The
+// primary key breaks ties so pages never overlap. The scopes, e.g. preloads,
+// only apply to finding the items, not to counting them.
+func findPage[T any](
+	tx *gorm.DB,
+	spec listSpec,
+	params *dto.Filter,
+	scopes ...func(*gorm.DB) *gorm.DB,
+) ([]T, dto.ListMeta, error) {
+	meta := dto.ListMeta{Limit: params.Limit}
+
+	stmt := &gorm.Statement{DB: tx, Context: tx.Statement.Context}
+	if err := stmt.Parse(new(T)); err != nil {
+		return nil, meta, err
+	}
+	keys, err := spec.sortKeys(stmt.Schema.Table, params.Sort)
+	if err != nil {
+		return nil, meta, err
+	}
+	sortName := formatSort(keys)
+
+	if params.Keyword != "" && len(spec.keywordColumns) > 0 {
+		q := fmt.Sprintf("%%%s%%", params.Keyword)
+		likes := make([]clause.Expression, len(spec.keywordColumns))
+		for i, name := range spec.keywordColumns {
+			likes[i] = clause.Like{Column: qualify(stmt.Schema.Table, name), Value: q}
+		}
+		tx = tx.Where(clause.Or(likes...))
+	}
+	tx = tx.Session(&gorm.Session{})
+
+	if err := tx.Model(new(T)).Count(&meta.Total).Error; err != nil {
+		return nil, meta, err
+	}
+
+	var from *cursor
+	if params.Cursor != "" {
+		if from, err = decodeCursor(params.Cursor, sortName); err != nil {
+			return nil, meta, err
+		}
+		values, err := cursorValues(stmt, keys, from)
+		if err != nil {
+			return nil, meta, err
+		}
+		tx = tx.Where(keyset(keys, values, from.Prev))
+	} else if params.Start > 0 {
+		tx = tx.Offset(params.Start)
+	}
+
+	backward := from != nil && from.Prev
+	for _, key := range keys {
+		tx = tx.Order(clause.OrderByColumn{Column: key.column, Desc: key.desc != backward})
+	}
+	if params.Limit > 0 {
+		// One more tells whether there is another page.
+		tx = tx.Limit(params.Limit + 1)
+	}
+
+	var items []T
+	if err := tx.Scopes(scopes...).Find(&items).Error; err != nil {
+		return nil, meta, err
+	}
+
+	more := params.Limit > 0 && len(items) > params.Limit
+	if more {
+		items = items[:params.Limit]
+	}
+	if backward {
+		for i, j := 0, len(items)-1; i < j; i, j = i+1, j-1 {
+			items[i], items[j] = items[j], items[i]
+		}
+	}
+	if params.Limit == 0 || len(items) == 0 {
+		return items, meta, nil
+	}
+
+	hasNext := more || backward
+	hasPrev := (backward && more) || (!backward && (from != nil || params.Start > 0))
+	if hasNext {
+		if meta.NextCursor, err = encodeCursor(stmt, keys, sortName, &items[len(items)-1], false); err != nil {
+			return nil, meta, err
+		}
+	}
+	if hasPrev {
+		if meta.PrevCursor, err = encodeCursor(stmt, keys, sortName, &items[0], true); err != nil {
+			return nil, meta, err
+		}
+	}
+
+	return items, meta, nil
+}
+
+// sortKeys parses a sort such as "-borrow_date,title", falling back to the
+// default one, and ends it with the primary key.
+func (spec listSpec) sortKeys(table, sort string) ([]sortKey, error) {
+	if sort == "" {
+		sort = spec.defaultSort
+	}
+
+	var keys []sortKey
+	seen := make(map[string]bool)
+	for _, field := range strings.Split(sort, ",") {
+		field = strings.TrimSpace(field)
+		desc := strings.HasPrefix(field, "-")
+		field = strings.TrimPrefix(field, "-")
+
+		column, ok := spec.sorts[field]
+		if !ok || seen[field] {
+			return nil, exception.ErrSortInvalid
+		}
+		seen[field] = true
+		keys = append(keys, sortKey{column: qualify(table, column), field: field, desc: desc})
+	}
+	if !seen["id"] {
+		keys = append(keys, sortKey{column: qualify(table, "id"), field: "id"})
+	}
+
+	return keys, nil
+}
+
+func formatSort(keys []sortKey) string {
+	fields := make([]string, len(keys))
+	for i, key := range keys {
+		fields[i] = key.field
+		if key.desc {
+			fields[i] = "-" + key.field
+		}
+	}
+
+	return strings.Join(fields, ",")
+}
+
+func qualify(table, name string) clause.Column {
+	if i := strings.IndexByte(name, '.'); i >= 0 {
+		return clause.Column{Table: name[:i], Name: name[i+1:]}
+	}
+	return clause.Column{Table: table, Name: name}
+}
+
+// keyset matches the items after the sort values, or before them when
+// backward.
+func keyset(keys []sortKey, values []interface{}, backward bool) clause.Expression {
+	ors := make([]clause.Expression, len(keys))
+	for i, key := range keys {
+		ands := make([]clause.Expression, 0, i+1)
+		for j := 0; j < i; j++ {
+			ands = append(ands, clause.Eq{Column: keys[j].column, Value: values[j]})
+		}
+		if key.desc != backward {
+			ands = append(ands, clause.Lt{Column: key.column, Value: values[i]})
+		} else {
+			ands = append(ands, clause.Gt{Column: key.column, Value: values[i]})
+		}
+		ors[i] = clause.And(ands...)
+	}
+
+	return clause.Or(ors...)
+}
+
+func encodeCursor(stmt *gorm.Statement, keys []sortKey, sortName string, item interface{}, prev bool) (string, error) {
+	c := cursor{Sort: sortName, Prev: prev, Values: make([]json.RawMessage, len(keys))}
+	for i, key := range keys {
+		field := stmt.Schema.LookUpField(key.column.Name)
+		value, _ := field.ValueOf(stmt.Context, reflect.ValueOf(item).Elem())
+		raw, err := json.Marshal(value)
+		if err != nil {
+			return "", err
+		}
+		c.Values[i] = raw
+	}
+
+	data, err := json.Marshal(c)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+func decodeCursor(s, sortName string) (*cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, exception.ErrCursorInvalid
+	}
+
+	var c cursor
+	if err := json.Unmarshal(data, &c); err != nil || c.Sort != sortName {
+		return nil, exception.ErrCursorInvalid
+	}
+	return &c, nil
+}
+
+// cursorValues reads the sort values of the cursor as the types of their
+// fields.
+func cursorValues(stmt *gorm.Statement, keys []sortKey, c *cursor) ([]interface{}, error) {
+	if len(c.Values) != len(keys) {
+		return nil, exception.ErrCursorInvalid
+	}
+
+	values := make([]interface{}, len(keys))
+	for i, key := range keys {
+		field := stmt.Schema.LookUpField(key.column.Name)
+		value := reflect.New(field.FieldType)
+		if err := json.Unmarshal(c.Values[i], value.Interface()); err != nil {
+			return nil, exception.ErrCursorInvalid
+		}
+		values[i] = value.Elem().Interface()
+	}
+
+	return values, nil
+}
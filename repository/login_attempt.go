@@ -0,0 +1,160 @@
+package repository
+
+import (
+	"base-gin/domain/dao"
+	"base-gin/storage"
+	"context"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// LoginAttemptStore counts failed logins per key within a fixed window. The
+// window of a key starts on its first failure and lasts ttl.
+type LoginAttemptStore interface {
+	// Get returns the number of failures of key in its current window along
+	// with the time the window ends. A key without failures returns zero.
+	Get(ctx context.Context, key string) (int, time.Time, error)
+	// Hit records a failure of key, starting a new window when there is none.
+	Hit(ctx context.Context, key string, ttl time.Duration) (int, time.Time, error)
+	// Reset forgets every failure of key.
+	Reset(ctx context.Context, key string) error
+}
+
+// MemoryLoginAttemptStore keeps the counters in memory, so they are neither
+// shared between instances nor kept across restarts.
+type MemoryLoginAttemptStore struct {
+	mu    sync.Mutex
+	items map[string]dao.LoginAttempt
+}
+
+func NewMemoryLoginAttemptStore() *MemoryLoginAttemptStore {
+	return &MemoryLoginAttemptStore{items: make(map[string]dao.LoginAttempt)}
+}
+
+func (s *MemoryLoginAttemptStore) Get(_ context.Context, key string) (int, time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	item, ok := s.items[key]
+	if !ok || !time.Now().Before(item.ExpiresAt) {
+		return 0, time.Time{}, nil
+	}
+
+	return item.Attempts, item.ExpiresAt, nil
+}
+
+func (s *MemoryLoginAttemptStore) Hit(_ context.Context, key string, ttl time.Duration) (int, time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	item, ok := s.items[key]
+	if !ok || !now.Before(item.ExpiresAt) {
+		s.purgeExpired(now)
+		item = dao.LoginAttempt{ThrottleKey: key, ExpiresAt: now.Add(ttl)}
+	}
+	item.Attempts++
+	s.items[key] = item
+
+	return item.Attempts, item.ExpiresAt, nil
+}
+
+func (s *MemoryLoginAttemptStore) Reset(_ context.Context, key string) error {
+	s.mu.Lock()
+	delete(s.items, key)
+	s.mu.Unlock()
+
+	return nil
+}
+
+func (s *MemoryLoginAttemptStore) purgeExpired(now time.Time) {
+	for key, item := range s.items {
+		if !now.Before(item.ExpiresAt) {
+			delete(s.items, key)
+		}
+	}
+}
+
+// DBLoginAttemptStore keeps the counters in the login_attempts table so they
+// are shared by every instance using the same database.
+type DBLoginAttemptStore struct {
+	db *gorm.DB
+}
+
+func NewDBLoginAttemptStore(db *gorm.DB) *DBLoginAttemptStore {
+	return &DBLoginAttemptStore{db: db}
+}
+
+func (s *DBLoginAttemptStore) Get(ctx context.Context, key string) (int, time.Time, error) {
+	ctx, cancelFunc := storage.NewDBContext(ctx)
+	defer cancelFunc()
+
+	var items []dao.LoginAttempt
+	tx := s.db.WithContext(ctx).
+		Where("throttle_key = ? AND expires_at > ?", key, time.Now().UTC()).
+		Limit(1).
+		Find(&items)
+	if tx.Error != nil || len(items) == 0 {
+		return 0, time.Time{}, tx.Error
+	}
+
+	return items[0].Attempts, items[0].ExpiresAt, nil
+}
+
+func (s *DBLoginAttemptStore) Hit(ctx context.Context, key string, ttl time.Duration) (int, time.Time, error) {
+	ctx, cancelFunc := storage.NewDBContext(ctx)
+	defer cancelFunc()
+
+	now := time.Now().UTC()
+	tx := s.db.WithContext(ctx).
+		Model(&dao.LoginAttempt{}).
+		Where("throttle_key = ? AND expires_at > ?", key, now).
+		UpdateColumn("attempts", gorm.Expr("attempts + 1"))
+	if tx.Error != nil {
+		return 0, time.Time{}, tx.Error
+	}
+
+	if tx.RowsAffected == 0 {
+		// No window yet or it has ended: start a new one.
+		tx = s.db.WithContext(ctx).
+			Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "throttle_key"}},
+				DoUpdates: clause.AssignmentColumns([]string{"attempts", "expires_at"}),
+			}).
+			Create(&dao.LoginAttempt{ThrottleKey: key, Attempts: 1, ExpiresAt: now.Add(ttl)})
+		if tx.Error != nil {
+			return 0, time.Time{}, tx.Error
+		}
+	}
+
+	var item dao.LoginAttempt
+	if err := s.db.WithContext(ctx).Where("throttle_key = ?", key).First(&item).Error; err != nil {
+		return 0, time.Time{}, err
+	}
+
+	return item.Attempts, item.ExpiresAt, nil
+}
+
+func (s *DBLoginAttemptStore) Reset(ctx context.Context, key string) error {
+	ctx, cancelFunc := storage.NewDBContext(ctx)
+	defer cancelFunc()
+
+	return s.db.WithContext(ctx).
+		Where("throttle_key = ?", key).
+		Delete(&dao.LoginAttempt{}).Error
+}
+
+// DeleteExpired purges counters whose window has ended.
+func (s *DBLoginAttemptStore) DeleteExpired(ctx context.Context) (int64, error) {
+	ctx, cancelFunc := storage.NewDBContext(ctx)
+	defer cancelFunc()
+
+	tx := s.db.WithContext(ctx).
+		Where("expires_at <= ?", time.Now().UTC()).
+		Delete(&dao.LoginAttempt{})
+
+	return tx.RowsAffected, tx.Error
+}
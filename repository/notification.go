@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"base-gin/domain"
+	"base-gin/domain/dao"
+	"base-gin/exception"
+	"base-gin/storage"
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type NotificationRepository struct {
+	db *gorm.DB
+}
+
+func NewNotificationRepository(db *gorm.DB) *NotificationRepository {
+	return &NotificationRepository{db: db}
+}
+
+func (r *NotificationRepository) GetPreference(ctx context.Context, personID uint) (dao.NotificationPreference, error) {
+	ctx, cancelFunc := storage.NewDBContext(ctx)
+	defer cancelFunc()
+
+	var item dao.NotificationPreference
+	err := r.db.WithContext(ctx).First(&item, personID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return item, exception.ErrDataNotFound
+	}
+	return item, err
+}
+
+// SavePreference creates the preference of the person or replaces it.
+func (r *NotificationRepository) SavePreference(ctx context.Context, item *dao.NotificationPreference) error {
+	ctx, cancelFunc := storage.NewDBContext(ctx)
+	defer cancelFunc()
+
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "person_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{
+				"email", "phone", "locale",
+				"email_opt_out", "sms_opt_out", "webhook_opt_out", "updated_at",
+			}),
+		}).
+		Create(item).Error
+}
+
+func (r *NotificationRepository) CreateDelivery(ctx context.Context, item *dao.NotificationDelivery) error {
+	ctx, cancelFunc := storage.NewDBContext(ctx)
+	defer cancelFunc()
+
+	return r.db.WithContext(ctx).Create(item).Error
+}
+
+// GetDeliveriesByPersonID returns the delivery log of a person, the most
+// recent first.
+func (r *NotificationRepository) GetDeliveriesByPersonID(ctx context.Context, personID uint) ([]dao.NotificationDelivery, error) {
+	ctx, cancelFunc := storage.NewDBContext(ctx)
+	defer cancelFunc()
+
+	var items []dao.NotificationDelivery
+	err := r.db.WithContext(ctx).
+		Where(&dao.NotificationDelivery{PersonID: personID}).
+		Order("created_at DESC, id DESC").
+		Find(&items).Error
+	return items, err
+}
+
+// HasSent tells whether the notification of the kind about the borrowing due
+// on dueDate already went out through the channel. Failed tries do not count.
+func (r *NotificationRepository) HasSent(
+	ctx context.Context,
+	borrowingID uint,
+	kind domain.TypeNotification,
+	channel domain.TypeChannel,
+	dueDate time.Time,
+) (bool, error) {
+	ctx, cancelFunc := storage.NewDBContext(ctx)
+	defer cancelFunc()
+
+	var count int64
+	err := r.db.WithContext(ctx).Model(&dao.NotificationDelivery{}).
+		Where("borrowing_id = ? AND kind = ? AND channel = ? AND due_date = ? AND status = ?",
+			borrowingID, kind, channel, dueDate, domain.DeliverySent).
+		Count(&count).Error
+	return count > 0, err
+}
@@ -5,10 +5,11 @@ import (
 	"base-gin/domain/dto"
 	"base-gin/exception"
 	"base-gin/storage"
+	"context"
 	"errors"
-	"fmt"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type PersonRepository struct {
@@ -19,8 +20,8 @@ func NewPersonRepository(db *gorm.DB) *PersonRepository {
 	return &PersonRepository{db: db}
 }
 
-func (r *PersonRepository) Create(newItem *dao.Person) error {
-	ctx, cancelFunc := storage.NewDBContext()
+func (r *PersonRepository) Create(ctx context.Context, newItem *dao.Person) error {
+	ctx, cancelFunc := storage.NewDBContext(ctx)
 	defer cancelFunc()
 
 	tx := r.db.WithContext(ctx).Create(&newItem)
@@ -31,8 +32,8 @@ func (r *PersonRepository) Create(newItem *dao.Person) error {
 	return nil
 }
 
-func (r *PersonRepository) GetByAccountID(accountID uint) (dao.Person, error) {
-	ctx, cancelFunc := storage.NewDBContext()
+func (r *PersonRepository) GetByAccountID(ctx context.Context, accountID uint) (dao.Person, error) {
+	ctx, cancelFunc := storage.NewDBContext(ctx)
 	defer cancelFunc()
 
 	var item dao.Person
@@ -49,8 +50,8 @@ func (r *PersonRepository) GetByAccountID(accountID uint) (dao.Person, error) {
 	return item, nil
 }
 
-func (r *PersonRepository) GetByID(id uint) (*dao.Person, error) {
-	ctx, cancelFunc := storage.NewDBContext()
+func (r *PersonRepository) GetByID(ctx context.Context, id uint) (*dao.Person, error) {
+	ctx, cancelFunc := storage.NewDBContext(ctx)
 	defer cancelFunc()
 
 	var item dao.Person
@@ -66,34 +67,50 @@ func (r *PersonRepository) GetByID(id uint) (*dao.Person, error) {
 	return &item, nil
 }
 
-func (r *PersonRepository) GetList(params *dto.Filter) ([]dao.Person, error) {
-	ctx, cancelFunc := storage.NewDBContext()
+// LockByID reads a person with a row lock held until the surrounding
+// transaction ends, so only one transaction at a time can act on it.
+func (r *PersonRepository) LockByID(ctx context.Context, id uint) (dao.Person, error) {
+	ctx, cancelFunc := storage.NewDBContext(ctx)
 	defer cancelFunc()
 
-	var items []dao.Person
-	tx := r.db.WithContext(ctx)
-
-	if params.Keyword != "" {
-		q := fmt.Sprintf("%%%s%%", params.Keyword)
-		tx = tx.Where("fullname LIKE ?", q)
-	}
-	if params.Start >= 0 {
-		tx = tx.Offset(params.Start)
-	}
-	if params.Limit > 0 {
-		tx = tx.Limit(params.Limit)
+	var item dao.Person
+	err := r.db.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		First(&item, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return item, exception.ErrUserNotFound
 	}
+	return item, err
+}
 
-	tx = tx.Order("fullname ASC").Find(&items)
-	if tx.Error != nil && !errors.Is(tx.Error, gorm.ErrRecordNotFound) {
-		return nil, tx.Error
+var personList = listSpec{
+	keywordColumns: []string{"fullname"},
+	sorts: map[string]string{
+		"id":         "id",
+		"fullname":   "fullname",
+		"category":   "category",
+		"created_at": "created_at",
+	},
+	defaultSort: "fullname",
+}
+
+func (r *PersonRepository) GetList(ctx context.Context, params *dto.PersonFilter) ([]dao.Person, dto.ListMeta, error) {
+	ctx, cancelFunc := storage.NewDBContext(ctx)
+	defer cancelFunc()
+
+	tx := r.db.WithContext(ctx)
+	if params.Gender != "" {
+		tx = tx.Where("gender = ?", params.Gender)
+	}
+	if params.Category != "" {
+		tx = tx.Where("category = ?", params.Category)
 	}
 
-	return items, nil
+	return findPage[dao.Person](tx, personList, &params.Filter)
 }
 
-func (r *PersonRepository) Update(params *dto.PersonUpdateReq) error {
-	ctx, cancelFunc := storage.NewDBContext()
+func (r *PersonRepository) Update(ctx context.Context, params *dto.PersonUpdateReq) error {
+	ctx, cancelFunc := storage.NewDBContext(ctx)
 	defer cancelFunc()
 
 	tx := r.db.WithContext(ctx).Model(&dao.Person{}).
@@ -106,3 +123,20 @@ func (r *PersonRepository) Update(params *dto.PersonUpdateReq) error {
 
 	return tx.Error
 }
+
+func (r *PersonRepository) UpdateCategory(ctx context.Context, id uint, category string) error {
+	ctx, cancelFunc := storage.NewDBContext(ctx)
+	defer cancelFunc()
+
+	tx := r.db.WithContext(ctx).Model(&dao.Person{}).
+		Where("id = ?", id).
+		Update("category", category)
+	if tx.Error != nil {
+		return tx.Error
+	}
+	if tx.RowsAffected == 0 {
+		return exception.ErrUserNotFound
+	}
+
+	return nil
+}
@@ -5,8 +5,8 @@ import (
 	"base-gin/domain/dto"
 	"base-gin/exception"
 	"base-gin/storage"
+	"context"
 	"errors"
-	"fmt"
 
 	"gorm.io/gorm"
 )
@@ -19,8 +19,8 @@ func NewPublisherRepository(db *gorm.DB) *PublisherRepository {
 	return &PublisherRepository{db: db}
 }
 
-func (r *PublisherRepository) Create(newItem *dao.Publisher) error {
-	ctx, cancelFunc := storage.NewDBContext()
+func (r *PublisherRepository) Create(ctx context.Context, newItem *dao.Publisher) error {
+	ctx, cancelFunc := storage.NewDBContext(ctx)
 	defer cancelFunc()
 
 	tx := r.db.WithContext(ctx).Create(&newItem)
@@ -31,8 +31,8 @@ func (r *PublisherRepository) Create(newItem *dao.Publisher) error {
 	return nil
 }
 
-func (r *PublisherRepository) GetByID(id uint) (*dao.Publisher, error) {
-	ctx, cancelFunc := storage.NewDBContext()
+func (r *PublisherRepository) GetByID(ctx context.Context, id uint) (*dao.Publisher, error) {
+	ctx, cancelFunc := storage.NewDBContext(ctx)
 	defer cancelFunc()
 
 	var item dao.Publisher
@@ -48,34 +48,51 @@ func (r *PublisherRepository) GetByID(id uint) (*dao.Publisher, error) {
 	return &item, nil
 }
 
-func (r *PublisherRepository) GetList(params *dto.Filter) ([]dao.Publisher, error) {
-	ctx, cancelFunc := storage.NewDBContext()
+// GetByName looks a publisher up by its name, ignoring case.
+func (r *PublisherRepository) GetByName(ctx context.Context, name string) (*dao.Publisher, error) {
+	ctx, cancelFunc := storage.NewDBContext(ctx)
 	defer cancelFunc()
 
-	var items []dao.Publisher
-	tx := r.db.WithContext(ctx)
-
-	if params.Keyword != "" {
-		q := fmt.Sprintf("%%%s%%", params.Keyword)
-		tx = tx.Where("name LIKE ?", q)
-	}
-	if params.Start >= 0 {
-		tx = tx.Offset(params.Start)
+	var item dao.Publisher
+	err := r.db.WithContext(ctx).
+		Where("LOWER(name) = LOWER(?)", name).
+		Order("id").
+		First(&item).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, exception.ErrDataNotFound
 	}
-	if params.Limit > 0 {
-		tx = tx.Limit(params.Limit)
+	if err != nil {
+		return nil, err
 	}
 
-	tx = tx.Order("name ASC").Find(&items)
-	if tx.Error != nil && !errors.Is(tx.Error, gorm.ErrRecordNotFound) {
-		return nil, tx.Error
+	return &item, nil
+}
+
+var publisherList = listSpec{
+	keywordColumns: []string{"name", "city"},
+	sorts: map[string]string{
+		"id":         "id",
+		"name":       "name",
+		"city":       "city",
+		"created_at": "created_at",
+	},
+	defaultSort: "name",
+}
+
+func (r *PublisherRepository) GetList(ctx context.Context, params *dto.PublisherFilter) ([]dao.Publisher, dto.ListMeta, error) {
+	ctx, cancelFunc := storage.NewDBContext(ctx)
+	defer cancelFunc()
+
+	tx := r.db.WithContext(ctx)
+	if params.City != "" {
+		tx = tx.Where("city = ?", params.City)
 	}
 
-	return items, nil
+	return findPage[dao.Publisher](tx, publisherList, &params.Filter)
 }
 
-func (r *PublisherRepository) Update(params *dto.PublisherUpdateReq) error {
-	ctx, cancelFunc := storage.NewDBContext()
+func (r *PublisherRepository) Update(ctx context.Context, params *dto.PublisherUpdateReq) error {
+	ctx, cancelFunc := storage.NewDBContext(ctx)
 	defer cancelFunc()
 
 	tx := r.db.WithContext(ctx).Model(&dao.Publisher{}).
@@ -88,8 +105,8 @@ func (r *PublisherRepository) Update(params *dto.PublisherUpdateReq) error {
 	return tx.Error
 }
 
-func (r *PublisherRepository) Delete(id uint) error {
-	ctx, cancelFunc := storage.NewDBContext()
+func (r *PublisherRepository) Delete(ctx context.Context, id uint) error {
+	ctx, cancelFunc := storage.NewDBContext(ctx)
 	defer cancelFunc()
 
 	tx := r.db.WithContext(ctx).Delete(&dao.Publisher{}, id)
@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"base-gin/domain/dao"
+	"base-gin/exception"
+	"base-gin/storage"
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type RefreshTokenRepository struct {
+	db *gorm.DB
+}
+
+func NewRefreshTokenRepository(db *gorm.DB) *RefreshTokenRepository {
+	return &RefreshTokenRepository{db: db}
+}
+
+func (r *RefreshTokenRepository) Create(ctx context.Context, newItem *dao.RefreshToken) error {
+	ctx, cancelFunc := storage.NewDBContext(ctx)
+	defer cancelFunc()
+
+	return r.db.WithContext(ctx).Create(newItem).Error
+}
+
+func (r *RefreshTokenRepository) GetByTokenID(ctx context.Context, tokenID string) (dao.RefreshToken, error) {
+	ctx, cancelFunc := storage.NewDBContext(ctx)
+	defer cancelFunc()
+
+	var item dao.RefreshToken
+	tx := r.db.WithContext(ctx).Where(dao.RefreshToken{TokenID: tokenID}).
+		First(&item)
+	if tx.Error != nil {
+		if errors.Is(tx.Error, gorm.ErrRecordNotFound) {
+			return item, exception.ErrRefreshTokenRevoked
+		}
+
+		return item, tx.Error
+	}
+
+	return item, nil
+}
+
+// Revoke marks a still active token as revoked. It fails with
+// ErrRefreshTokenRevoked when the token was already revoked, so two concurrent
+// rotations of the same token cannot both succeed.
+func (r *RefreshTokenRepository) Revoke(ctx context.Context, tokenID string, replacedBy *string) error {
+	ctx, cancelFunc := storage.NewDBContext(ctx)
+	defer cancelFunc()
+
+	tx := r.db.WithContext(ctx).Model(&dao.RefreshToken{}).
+		Where("token_id = ? AND revoked_at IS NULL", tokenID).
+		Updates(map[string]interface{}{
+			"revoked_at":  time.Now().UTC(),
+			"replaced_by": replacedBy,
+		})
+	if tx.Error != nil {
+		return tx.Error
+	}
+	if tx.RowsAffected == 0 {
+		return exception.ErrRefreshTokenRevoked
+	}
+
+	return nil
+}
+
+func (r *RefreshTokenRepository) RevokeAllByAccountID(ctx context.Context, accountID uint) error {
+	ctx, cancelFunc := storage.NewDBContext(ctx)
+	defer cancelFunc()
+
+	tx := r.db.WithContext(ctx).Model(&dao.RefreshToken{}).
+		Where("account_id = ? AND revoked_at IS NULL", accountID).
+		Update("revoked_at", time.Now().UTC())
+
+	return tx.Error
+}
+
+// DeleteExpired purges tokens past their expiry, which can no longer be used
+// nor replayed.
+func (r *RefreshTokenRepository) DeleteExpired(ctx context.Context) (int64, error) {
+	ctx, cancelFunc := storage.NewDBContext(ctx)
+	defer cancelFunc()
+
+	tx := r.db.WithContext(ctx).
+		Where("expires_at <= ?", time.Now().UTC()).
+		Delete(&dao.RefreshToken{})
+
+	return tx.RowsAffected, tx.Error
+}
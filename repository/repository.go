@@ -1,6 +1,10 @@
 package repository
 
-import "base-gin/storage"
+import (
+	"base-gin/config"
+	"base-gin/storage"
+	"time"
+)
 
 var (
 	accountRepo   *AccountRepository
@@ -8,17 +12,50 @@ var (
 	publisherRepo *PublisherRepository
 	authorRepo    *AuthorRepository
 	bookRepo      *BookRepository
+	bookCopyRepo  *BookCopyRepository
 	borrowingRepo *BorrowingRepository
+	fineRepo      *FineRepository
+	holdRepo      *HoldRepository
+	ruleRepo      *CirculationRuleRepository
+	notifyRepo    *NotificationRepository
+	jobRunRepo    *JobRunRepository
+	categoryRepo  *CategoryRepository
+
+	refreshTokenRepo *RefreshTokenRepository
+	revokedTokenRepo *RevokedTokenRepository
+
+	loginAttemptStore LoginAttemptStore
+
+	txManager *TxManager
 )
 
-func SetupRepositories() {
+func SetupRepositories(cfg *config.Config) {
 	db := storage.GetDB()
 	accountRepo = NewAccountRepository(db)
 	personRepo = NewPersonRepository(db)
 	publisherRepo = NewPublisherRepository(db)
 	authorRepo = NewAuthorRepository(db)
 	bookRepo = NewBookRepository(db)
+	bookCopyRepo = NewBookCopyRepository(db)
 	borrowingRepo = NewBorrowingRepository(db)
+	fineRepo = NewFineRepository(db)
+	holdRepo = NewHoldRepository(db)
+	ruleRepo = NewCirculationRuleRepository(db)
+	notifyRepo = NewNotificationRepository(db)
+	jobRunRepo = NewJobRunRepository(db)
+	categoryRepo = NewCategoryRepository(db)
+
+	refreshTokenRepo = NewRefreshTokenRepository(db)
+	revokedTokenRepo = NewRevokedTokenRepository(
+		db, time.Duration(cfg.AuthN.JWTRevocationSync)*time.Second)
+
+	if cfg.AuthN.LoginThrottleStore == config.ThrottleStoreDB {
+		loginAttemptStore = NewDBLoginAttemptStore(db)
+	} else {
+		loginAttemptStore = NewMemoryLoginAttemptStore()
+	}
+
+	txManager = NewTxManager(db)
 }
 
 func GetAccountRepo() *AccountRepository {
@@ -41,6 +78,50 @@ func GetBookRepo() *BookRepository {
 	return bookRepo
 }
 
+func GetBookCopyRepo() *BookCopyRepository {
+	return bookCopyRepo
+}
+
 func GetBorrowingRepo() *BorrowingRepository {
 	return borrowingRepo
 }
+
+func GetFineRepo() *FineRepository {
+	return fineRepo
+}
+
+func GetHoldRepo() *HoldRepository {
+	return holdRepo
+}
+
+func GetCirculationRuleRepo() *CirculationRuleRepository {
+	return ruleRepo
+}
+
+func GetNotificationRepo() *NotificationRepository {
+	return notifyRepo
+}
+
+func GetCategoryRepo() *CategoryRepository {
+	return categoryRepo
+}
+
+func GetJobRunRepo() *JobRunRepository {
+	return jobRunRepo
+}
+
+func GetRefreshTokenRepo() *RefreshTokenRepository {
+	return refreshTokenRepo
+}
+
+func GetRevokedTokenRepo() *RevokedTokenRepository {
+	return revokedTokenRepo
+}
+
+func GetLoginAttemptStore() LoginAttemptStore {
+	return loginAttemptStore
+}
+
+func GetTxManager() *TxManager {
+	return txManager
+}
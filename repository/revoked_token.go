@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"base-gin/domain/dao"
+	"base-gin/storage"
+	"context"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// RevokedTokenRepository is the token denylist. Lookups are served from an
+// in-memory copy of the table that is refreshed every syncInterval, so
+// revocations made by other instances are seen within that interval while
+// revocations made by this instance apply immediately.
+type RevokedTokenRepository struct {
+	db           *gorm.DB
+	syncInterval time.Duration
+
+	mu       sync.RWMutex
+	tokens   map[string]time.Time // token ID -> expiry
+	syncedAt time.Time
+}
+
+func NewRevokedTokenRepository(db *gorm.DB, syncInterval time.Duration) *RevokedTokenRepository {
+	return &RevokedTokenRepository{
+		db:           db,
+		syncInterval: syncInterval,
+		tokens:       make(map[string]time.Time),
+	}
+}
+
+func (r *RevokedTokenRepository) Create(ctx context.Context, newItem *dao.RevokedToken) error {
+	ctx, cancelFunc := storage.NewDBContext(ctx)
+	defer cancelFunc()
+
+	tx := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(newItem)
+	if tx.Error != nil {
+		return tx.Error
+	}
+
+	r.mu.Lock()
+	r.tokens[newItem.TokenID] = newItem.ExpiresAt
+	r.mu.Unlock()
+
+	return nil
+}
+
+func (r *RevokedTokenRepository) IsRevoked(ctx context.Context, tokenID string) (bool, error) {
+	if err := r.syncIfStale(ctx); err != nil {
+		return false, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	expiresAt, ok := r.tokens[tokenID]
+	return ok && time.Now().Before(expiresAt), nil
+}
+
+// DeleteExpired purges entries of tokens that have expired anyway.
+func (r *RevokedTokenRepository) DeleteExpired(ctx context.Context) (int64, error) {
+	ctx, cancelFunc := storage.NewDBContext(ctx)
+	defer cancelFunc()
+
+	tx := r.db.WithContext(ctx).
+		Where("expires_at <= ?", time.Now().UTC()).
+		Delete(&dao.RevokedToken{})
+
+	return tx.RowsAffected, tx.Error
+}
+
+func (r *RevokedTokenRepository) syncIfStale(ctx context.Context) error {
+	r.mu.RLock()
+	fresh := time.Since(r.syncedAt) < r.syncInterval
+	r.mu.RUnlock()
+	if fresh {
+		return nil
+	}
+
+	ctx, cancelFunc := storage.NewDBContext(ctx)
+	defer cancelFunc()
+
+	var items []dao.RevokedToken
+	tx := r.db.WithContext(ctx).
+		Where("expires_at > ?", time.Now().UTC()).
+		Find(&items)
+	if tx.Error != nil {
+		return tx.Error
+	}
+
+	tokens := make(map[string]time.Time, len(items))
+	for _, item := range items {
+		tokens[item.TokenID] = item.ExpiresAt
+	}
+
+	r.mu.Lock()
+	// Revocations are never undone, so keep what was added while loading.
+	now := time.Now()
+	for tokenID, expiresAt := range r.tokens {
+		if now.Before(expiresAt) {
+			tokens[tokenID] = expiresAt
+		}
+	}
+	r.tokens = tokens
+	r.syncedAt = now
+	r.mu.Unlock()
+
+	return nil
+}
@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+// Repositories bundles every repository bound to the same *gorm.DB, which is
+// how a transaction hands its repositories to the caller.
+type Repositories struct {
+	Account   *AccountRepository
+	Person    *PersonRepository
+	Publisher *PublisherRepository
+	Author    *AuthorRepository
+	Book      *BookRepository
+	BookCopy  *BookCopyRepository
+	Borrowing *BorrowingRepository
+	Fine      *FineRepository
+	Hold      *HoldRepository
+	Rule      *CirculationRuleRepository
+	Category  *CategoryRepository
+
+	RefreshToken *RefreshTokenRepository
+
+	db *gorm.DB
+}
+
+func NewRepositories(db *gorm.DB) *Repositories {
+	return &Repositories{
+		Account:   NewAccountRepository(db),
+		Person:    NewPersonRepository(db),
+		Publisher: NewPublisherRepository(db),
+		Author:    NewAuthorRepository(db),
+		Book:      NewBookRepository(db),
+		BookCopy:  NewBookCopyRepository(db),
+		Borrowing: NewBorrowingRepository(db),
+		Fine:      NewFineRepository(db),
+		Hold:      NewHoldRepository(db),
+		Rule:      NewCirculationRuleRepository(db),
+		Category:  NewCategoryRepository(db),
+
+		RefreshToken: NewRefreshTokenRepository(db),
+
+		db: db,
+	}
+}
+
+// Savepoint runs fn with repositories bound to a savepoint of the
+// transaction the repositories are bound to. When fn returns an error only
+// its own writes are rolled back and the transaction goes on.
+func (r *Repositories) Savepoint(ctx context.Context, fn func(repos *Repositories) error) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(NewRepositories(tx))
+	})
+}
+
+type TxManager struct {
+	db *gorm.DB
+}
+
+func NewTxManager(db *gorm.DB) *TxManager {
+	return &TxManager{db: db}
+}
+
+// WithTx runs fn with repositories bound to a single transaction. The
+// transaction is committed when fn returns nil and rolled back when fn returns
+// an error or panics; the panic is re-raised after the rollback. Calling WithTx
+// from inside fn is not needed, use the given repositories instead.
+func (m *TxManager) WithTx(ctx context.Context, fn func(repos *Repositories) error) error {
+	return m.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(NewRepositories(tx))
+	})
+}
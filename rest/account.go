@@ -6,7 +6,9 @@ import (
 	"base-gin/server"
 	"base-gin/service"
 	"errors"
+	"math"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 )
@@ -29,6 +31,9 @@ func NewAccountHandler(
 func (h *AccountHandler) Route(app *gin.Engine) {
 	grp := app.Group(server.RootAccount)
 	grp.POST(server.PathLogin, h.login)
+	grp.POST(server.PathRegister, h.register)
+	grp.POST(server.PathRefresh, h.hr.AuthRefresh(), h.refresh)
+	grp.POST(server.PathLogout, h.hr.AuthRefresh(), h.logout)
 	grp.GET("", h.hr.AuthAccess(), h.getProfile)
 }
 
@@ -42,6 +47,7 @@ func (h *AccountHandler) Route(app *gin.Engine) {
 //	@Success 200 {object} dto.SuccessResponse[dto.AccountLoginResp]
 //	@Failure 400 {object} dto.ErrorResponse
 //	@Failure 422 {object} dto.ErrorResponse
+//	@Failure 429 {object} dto.ErrorResponse
 //	@Failure 500 {object} dto.ErrorResponse
 //	@Router /accounts/login [post]
 func (h *AccountHandler) login(c *gin.Context) {
@@ -51,9 +57,13 @@ func (h *AccountHandler) login(c *gin.Context) {
 		return
 	}
 
-	data, err := h.service.Login(req)
+	data, err := h.service.Login(c.Request.Context(), req, h.hr.ClientInfo(c))
 	if err != nil {
+		var throttled *exception.ThrottledError
 		switch {
+		case errors.As(err, &throttled):
+			c.Header("Retry-After", strconv.Itoa(int(math.Ceil(throttled.RetryAfter.Seconds()))))
+			c.JSON(http.StatusTooManyRequests, h.hr.ErrorResponse(err.Error()))
 		case errors.Is(err, exception.ErrUserNotFound),
 			errors.Is(err, exception.ErrUserLoginFailed):
 			c.JSON(http.StatusBadRequest, h.hr.ErrorResponse(exception.ErrUserLoginFailed.Error()))
@@ -70,6 +80,119 @@ func (h *AccountHandler) login(c *gin.Context) {
 	})
 }
 
+// register godoc
+//
+//	@Summary Account registration
+//	@Description Register an account along with its person profile. Set login to true to receive tokens right away.
+//	@Accept json
+//	@Produce json
+//	@Param detail body dto.AccountRegisterReq true "Account & profile detail"
+//	@Success 201 {object} dto.SuccessResponse[dto.AccountRegisterResp]
+//	@Failure 400 {object} dto.ErrorResponse
+//	@Failure 409 {object} dto.ErrorResponse
+//	@Failure 422 {object} dto.ErrorResponse
+//	@Failure 500 {object} dto.ErrorResponse
+//	@Router /accounts/register [post]
+func (h *AccountHandler) register(c *gin.Context) {
+	var req dto.AccountRegisterReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(h.hr.BindingError(err))
+		return
+	}
+
+	data, err := h.service.Register(c.Request.Context(), &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, exception.ErrUserConflict):
+			c.JSON(http.StatusConflict, h.hr.ErrorResponse(err.Error()))
+		case errors.Is(err, exception.ErrDateParsing):
+			c.JSON(http.StatusBadRequest, h.hr.ErrorResponse(err.Error()))
+		default:
+			h.hr.ErrorInternalServer(c, err)
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.SuccessResponse[dto.AccountRegisterResp]{
+		Success: true,
+		Message: "Registrasi berhasil",
+		Data:    data,
+	})
+}
+
+// refresh godoc
+//
+//	@Summary Refresh tokens
+//	@Description Exchange a refresh token for a new access & refresh token pair. The refresh token used can not be used again.
+//	@Produce json
+//	@Security BearerAuth
+//	@Success 200 {object} dto.SuccessResponse[dto.AccountLoginResp]
+//	@Failure 401 {object} dto.ErrorResponse
+//	@Failure 500 {object} dto.ErrorResponse
+//	@Router /accounts/refresh [post]
+func (h *AccountHandler) refresh(c *gin.Context) {
+	username, tokenID := refreshTokenClaims(c)
+
+	data, err := h.service.Refresh(c.Request.Context(), username, tokenID)
+	if err != nil {
+		switch {
+		case errors.Is(err, exception.ErrUserNotFound),
+			errors.Is(err, exception.ErrRefreshTokenRevoked):
+			c.JSON(http.StatusUnauthorized, h.hr.ErrorResponse(exception.ErrRefreshTokenRevoked.Error()))
+		default:
+			h.hr.ErrorInternalServer(c, err)
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse[dto.AccountLoginResp]{
+		Success: true,
+		Message: "Token berhasil diperbarui",
+		Data:    data,
+	})
+}
+
+// logout godoc
+//
+//	@Summary Account logout
+//	@Description Revoke the refresh token so it can not be used again.
+//	@Produce json
+//	@Security BearerAuth
+//	@Success 200 {object} dto.SuccessResponse[any]
+//	@Failure 401 {object} dto.ErrorResponse
+//	@Failure 500 {object} dto.ErrorResponse
+//	@Router /accounts/logout [post]
+func (h *AccountHandler) logout(c *gin.Context) {
+	username, tokenID := refreshTokenClaims(c)
+
+	err := h.service.Logout(c.Request.Context(), username, tokenID)
+	if err != nil {
+		switch {
+		case errors.Is(err, exception.ErrUserNotFound),
+			errors.Is(err, exception.ErrRefreshTokenRevoked):
+			c.JSON(http.StatusUnauthorized, h.hr.ErrorResponse(exception.ErrRefreshTokenRevoked.Error()))
+		default:
+			h.hr.ErrorInternalServer(c, err)
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse[any]{
+		Success: true,
+		Message: "Logout berhasil",
+	})
+}
+
+func refreshTokenClaims(c *gin.Context) (string, string) {
+	username, _ := c.Get(server.ParamTokenUsername)
+	tokenID, _ := c.Get(server.ParamTokenID)
+
+	usernameStr, _ := username.(string)
+	tokenIDStr, _ := tokenID.(string)
+
+	return usernameStr, tokenIDStr
+}
+
 // getProfile godoc
 //
 //	@Summary Get account's profile
@@ -85,7 +208,7 @@ func (h *AccountHandler) login(c *gin.Context) {
 func (h *AccountHandler) getProfile(c *gin.Context) {
 	accountID, _ := c.Get(server.ParamTokenUserID)
 
-	data, err := h.personService.GetAccountProfile((accountID).(uint))
+	data, err := h.personService.GetAccountProfile(c.Request.Context(), (accountID).(uint))
 	if err != nil {
 		switch {
 		case errors.Is(err, exception.ErrUserNotFound):
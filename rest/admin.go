@@ -0,0 +1,211 @@
+package rest
+
+import (
+	"base-gin/domain"
+	"base-gin/domain/dto"
+	"base-gin/exception"
+	"base-gin/server"
+	"base-gin/service"
+	"base-gin/util"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AdminHandler struct {
+	hr             *server.Handler
+	accountService *service.AccountService
+	personService  *service.PersonService
+}
+
+func NewAdminHandler(
+	hr *server.Handler,
+	accountService *service.AccountService,
+	personService *service.PersonService,
+) *AdminHandler {
+	return &AdminHandler{hr: hr, accountService: accountService, personService: personService}
+}
+
+func (h *AdminHandler) Route(app *gin.Engine) {
+	grp := app.Group(server.RootAdmin, h.hr.AuthAccess(), h.hr.RequireRole(domain.RoleAdmin))
+	grp.PUT(server.PathAdminAccountRole, h.setAccountRole)
+	grp.POST(server.PathAdminAccountRevoke, h.revokeAccountTokens)
+	grp.POST(server.PathAdminTokenRevoke, h.revokeToken)
+	grp.PUT(server.PathAdminPersonCategory, h.setPersonCategory)
+}
+
+// setAccountRole godoc
+//
+//	@Summary Set the role of an account
+//	@Description Grant an account the admin, librarian or member role.
+//	@Accept json
+//	@Produce json
+//	@Security BearerAuth
+//	@Param id path int true "Account's ID"
+//	@Param role body dto.AccountRoleReq true "New role"
+//	@Success 200 {object} dto.SuccessResponse[any]
+//	@Failure 400 {object} dto.ErrorResponse
+//	@Failure 401 {object} dto.ErrorResponse
+//	@Failure 403 {object} dto.ErrorResponse
+//	@Failure 404 {object} dto.ErrorResponse
+//	@Failure 422 {object} dto.ErrorResponse
+//	@Failure 500 {object} dto.ErrorResponse
+//	@Router /admin/accounts/{id}/role [put]
+func (h *AdminHandler) setAccountRole(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, h.hr.ErrorResponse("ID tidak valid"))
+		return
+	}
+
+	var req dto.AccountRoleReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(h.hr.BindingError(err))
+		return
+	}
+
+	err = h.accountService.SetRole(c.Request.Context(), uint(id), domain.TypeRole(req.Role))
+	if err != nil {
+		switch {
+		case errors.Is(err, exception.ErrUserNotFound):
+			c.JSON(http.StatusNotFound, h.hr.ErrorResponse(err.Error()))
+		default:
+			h.hr.ErrorInternalServer(c, err)
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse[any]{
+		Success: true,
+		Message: "Peran akun berhasil diubah",
+	})
+}
+
+// revokeAccountTokens godoc
+//
+//	@Summary Revoke all tokens of an account
+//	@Description Invalidate every access & refresh token issued to the account so far.
+//	@Produce json
+//	@Security BearerAuth
+//	@Param id path int true "Account's ID"
+//	@Success 200 {object} dto.SuccessResponse[any]
+//	@Failure 400 {object} dto.ErrorResponse
+//	@Failure 401 {object} dto.ErrorResponse
+//	@Failure 403 {object} dto.ErrorResponse
+//	@Failure 404 {object} dto.ErrorResponse
+//	@Failure 500 {object} dto.ErrorResponse
+//	@Router /admin/accounts/{id}/revoke-tokens [post]
+func (h *AdminHandler) revokeAccountTokens(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, h.hr.ErrorResponse("ID tidak valid"))
+		return
+	}
+
+	err = h.accountService.RevokeAllTokens(c.Request.Context(), uint(id))
+	if err != nil {
+		switch {
+		case errors.Is(err, exception.ErrUserNotFound):
+			c.JSON(http.StatusNotFound, h.hr.ErrorResponse(err.Error()))
+		default:
+			h.hr.ErrorInternalServer(c, err)
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse[any]{
+		Success: true,
+		Message: "Seluruh token akun berhasil dicabut",
+	})
+}
+
+// revokeToken godoc
+//
+//	@Summary Revoke a token
+//	@Description Put a single access or refresh token on the denylist.
+//	@Accept json
+//	@Produce json
+//	@Security BearerAuth
+//	@Param token body dto.TokenRevokeReq true "Token to revoke"
+//	@Success 200 {object} dto.SuccessResponse[any]
+//	@Failure 400 {object} dto.ErrorResponse
+//	@Failure 401 {object} dto.ErrorResponse
+//	@Failure 403 {object} dto.ErrorResponse
+//	@Failure 404 {object} dto.ErrorResponse
+//	@Failure 422 {object} dto.ErrorResponse
+//	@Failure 500 {object} dto.ErrorResponse
+//	@Router /admin/tokens/revoke [post]
+func (h *AdminHandler) revokeToken(c *gin.Context) {
+	var req dto.TokenRevokeReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(h.hr.BindingError(err))
+		return
+	}
+
+	err := h.accountService.RevokeToken(c.Request.Context(), &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, util.ErrTokenInvalid):
+			c.JSON(http.StatusBadRequest, h.hr.ErrorResponse(err.Error()))
+		case errors.Is(err, exception.ErrUserNotFound):
+			c.JSON(http.StatusNotFound, h.hr.ErrorResponse(err.Error()))
+		default:
+			h.hr.ErrorInternalServer(c, err)
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse[any]{
+		Success: true,
+		Message: "Token berhasil dicabut",
+	})
+}
+
+// setPersonCategory godoc
+//
+//	@Summary Set the patron category of a person
+//	@Description Put a person in a patron category, which picks the circulation rules applying to their loans.
+//	@Accept json
+//	@Produce json
+//	@Security BearerAuth
+//	@Param id path int true "Person's ID"
+//	@Param category body dto.PersonCategoryReq true "New category"
+//	@Success 200 {object} dto.SuccessResponse[any]
+//	@Failure 400 {object} dto.ErrorResponse
+//	@Failure 401 {object} dto.ErrorResponse
+//	@Failure 403 {object} dto.ErrorResponse
+//	@Failure 404 {object} dto.ErrorResponse
+//	@Failure 422 {object} dto.ErrorResponse
+//	@Failure 500 {object} dto.ErrorResponse
+//	@Router /admin/persons/{id}/category [put]
+func (h *AdminHandler) setPersonCategory(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, h.hr.ErrorResponse("ID tidak valid"))
+		return
+	}
+
+	var req dto.PersonCategoryReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(h.hr.BindingError(err))
+		return
+	}
+
+	err = h.personService.SetCategory(c.Request.Context(), uint(id), req.Category)
+	if err != nil {
+		switch {
+		case errors.Is(err, exception.ErrUserNotFound):
+			c.JSON(http.StatusNotFound, h.hr.ErrorResponse(err.Error()))
+		default:
+			h.hr.ErrorInternalServer(c, err)
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse[any]{
+		Success: true,
+		Message: "Kategori peminjam berhasil diubah",
+	})
+}
@@ -2,6 +2,7 @@
 package rest
 
 import (
+	"base-gin/domain"
 	"base-gin/domain/dto"
 	"base-gin/exception"
 	"base-gin/server"
@@ -14,21 +15,29 @@ import (
 )
 
 type AuthorHandler struct {
-	hr      *server.Handler
-	service *service.AuthorService
+	hr          *server.Handler
+	service     *service.AuthorService
+	bookService *service.BookService
 }
 
-func NewAuthorHandler(handler *server.Handler, authorService *service.AuthorService) *AuthorHandler {
-	return &AuthorHandler{hr: handler, service: authorService}
+func NewAuthorHandler(
+	handler *server.Handler,
+	authorService *service.AuthorService,
+	bookService *service.BookService,
+) *AuthorHandler {
+	return &AuthorHandler{hr: handler, service: authorService, bookService: bookService}
 }
 
 func (h *AuthorHandler) Route(app *gin.Engine) {
+	staffOnly := h.hr.RequireRole(domain.RoleAdmin, domain.RoleLibrarian)
+
 	grp := app.Group(server.RootAuthor)
-	grp.POST("", h.hr.AuthAccess(), h.create)
+	grp.POST("", h.hr.AuthAccess(), staffOnly, h.create)
 	grp.GET("", h.getList)
 	grp.GET("/:id", h.getByID)
-	grp.PUT("/:id", h.hr.AuthAccess(), h.update)
-	grp.DELETE("/:id", h.hr.AuthAccess(), h.delete)
+	grp.GET(server.PathAuthorBooks, h.getBooks)
+	grp.PUT("/:id", h.hr.AuthAccess(), staffOnly, h.update)
+	grp.DELETE("/:id", h.hr.AuthAccess(), staffOnly, h.delete)
 }
 
 // create godoc
@@ -52,7 +61,7 @@ func (h *AuthorHandler) create(c *gin.Context) {
 		return
 	}
 
-	err := h.service.Create(&req)
+	err := h.service.Create(c.Request.Context(), &req)
 	if err != nil {
 		h.hr.ErrorInternalServer(c, err)
 		return
@@ -69,16 +78,32 @@ func (h *AuthorHandler) create(c *gin.Context) {
 // @Summary Get a list of authors
 // @Description Get a list of all authors.
 // @Produce json
+// @Param q query string false "Author's name"
+// @Param gender query string false "Gender" Enums(m, f)
+// @Param s query int false "Data offset"
+// @Param l query int false "Data limit"
+// @Param sort query string false "Sort by id, full_name, created_at, comma separated, descending when prefixed with -"
+// @Param cursor query string false "Next or previous cursor of a page, in place of the offset"
 // @Success 200 {object} dto.SuccessResponse[[]dto.AuthorResp]
 // @Failure 404 {object} dto.ErrorResponse
+// @Failure 422 {object} dto.ErrorResponse
 // @Failure 500 {object} dto.ErrorResponse
 // @Router /authors [get]
 func (h *AuthorHandler) getList(c *gin.Context) {
-	data, err := h.service.GetList()
+	var req dto.AuthorFilter
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(h.hr.BindingError(err))
+		return
+	}
+
+	data, meta, err := h.service.GetList(c.Request.Context(), &req)
 	if err != nil {
 		switch {
 		case errors.Is(err, exception.ErrDataNotFound):
 			c.JSON(http.StatusNotFound, h.hr.ErrorResponse(err.Error()))
+		case errors.Is(err, exception.ErrSortInvalid),
+			errors.Is(err, exception.ErrCursorInvalid):
+			c.JSON(http.StatusUnprocessableEntity, h.hr.ErrorResponse(err.Error()))
 		default:
 			h.hr.ErrorInternalServer(c, err)
 		}
@@ -89,6 +114,7 @@ func (h *AuthorHandler) getList(c *gin.Context) {
 		Success: true,
 		Message: "List of authors",
 		Data:    data,
+		Meta:    &meta,
 	})
 }
 
@@ -111,7 +137,7 @@ func (h *AuthorHandler) getByID(c *gin.Context) {
 		return
 	}
 
-	data, err := h.service.GetByID(uint(id))
+	data, err := h.service.GetByID(c.Request.Context(), uint(id))
 	if err != nil {
 		switch {
 		case errors.Is(err, exception.ErrDataNotFound):
@@ -129,6 +155,50 @@ func (h *AuthorHandler) getByID(c *gin.Context) {
 	})
 }
 
+// getBooks godoc
+//
+// @Summary Get an author's works
+// @Description Get the books an author contributed to, grouped by role and by title within a role.
+// @Produce json
+// @Param id path int true "Author ID"
+// @Param role query string false "Contributor role" Enums(author, editor, translator, illustrator)
+// @Success 200 {object} dto.SuccessResponse[[]dto.AuthorWorkResp]
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 422 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /author/{id}/books [get]
+func (h *AuthorHandler) getBooks(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, h.hr.ErrorResponse("Invalid ID"))
+		return
+	}
+
+	var req dto.AuthorWorkFilter
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(h.hr.BindingError(err))
+		return
+	}
+
+	data, err := h.bookService.GetListByAuthorID(c.Request.Context(), uint(id), &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, exception.ErrDataNotFound):
+			c.JSON(http.StatusNotFound, h.hr.ErrorResponse(err.Error()))
+		default:
+			h.hr.ErrorInternalServer(c, err)
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse[[]dto.AuthorWorkResp]{
+		Success: true,
+		Message: "List of the author's books",
+		Data:    data,
+	})
+}
+
 // update godoc
 //
 // @Summary Update an author's detail
@@ -160,7 +230,7 @@ func (h *AuthorHandler) update(c *gin.Context) {
 	}
 	req.ID = uint(id)
 
-	err = h.service.Update(&req)
+	err = h.service.Update(c.Request.Context(), &req)
 	if err != nil {
 		switch {
 		case errors.Is(err, exception.ErrDataNotFound):
@@ -199,7 +269,7 @@ func (h *AuthorHandler) delete(c *gin.Context) {
 		return
 	}
 
-	err = h.service.Delete(uint(id))
+	err = h.service.Delete(c.Request.Context(), uint(id))
 	if err != nil {
 		h.hr.ErrorInternalServer(c, err)
 		return
@@ -1,10 +1,12 @@
 package rest
 
 import (
+	"base-gin/domain"
 	"base-gin/domain/dto"
 	"base-gin/exception"
 	"base-gin/server"
 	"base-gin/service"
+	"base-gin/util"
 	"errors"
 	"net/http"
 	"strconv"
@@ -22,12 +24,15 @@ func NewBookHandler(handler *server.Handler, bookService *service.BookService) *
 }
 
 func (h *BookHandler) Route(app *gin.Engine) {
+	staffOnly := h.hr.RequireRole(domain.RoleAdmin, domain.RoleLibrarian)
+
 	grp := app.Group(server.RootBook)
-	grp.POST("", h.hr.AuthAccess(), h.create)
+	grp.POST("", h.hr.AuthAccess(), staffOnly, h.create)
 	grp.GET("", h.getList)
 	grp.GET("/:id", h.getByID)
-	grp.PUT("/:id", h.hr.AuthAccess(), h.update)
-	grp.DELETE("/:id", h.hr.AuthAccess(), h.delete)
+	grp.GET(server.PathBookISBN, h.getByISBN)
+	grp.PUT("/:id", h.hr.AuthAccess(), staffOnly, h.update)
+	grp.DELETE("/:id", h.hr.AuthAccess(), staffOnly, h.delete)
 }
 
 // create godoc
@@ -41,19 +46,28 @@ func (h *BookHandler) Route(app *gin.Engine) {
 //	@Success 201 {object} dto.SuccessResponse[any]
 //	@Failure 401 {object} dto.ErrorResponse
 //	@Failure 403 {object} dto.ErrorResponse
+//	@Failure 409 {object} dto.ErrorResponse
 //	@Failure 422 {object} dto.ErrorResponse
 //	@Failure 500 {object} dto.ErrorResponse
 //	@Router /books [post]
 func (h *BookHandler) create(c *gin.Context) {
 	var req dto.BookDTO
 	if err := c.ShouldBindJSON(&req); err != nil {
-		h.hr.BindingError(err)
+		c.JSON(h.hr.BindingError(err))
 		return
 	}
 
-	err := h.service.Create(&req)
+	err := h.service.Create(c.Request.Context(), &req)
 	if err != nil {
-		h.hr.ErrorInternalServer(c, err)
+		switch {
+		case errors.Is(err, exception.ErrISBNConflict):
+			c.JSON(http.StatusConflict, h.hr.ErrorResponse(err.Error()))
+		case errors.Is(err, exception.ErrContributorNotFound),
+			errors.Is(err, exception.ErrCategoryNotFound):
+			c.JSON(http.StatusUnprocessableEntity, h.hr.ErrorResponse(err.Error()))
+		default:
+			h.hr.ErrorInternalServer(c, err)
+		}
 		return
 	}
 
@@ -68,37 +82,45 @@ func (h *BookHandler) create(c *gin.Context) {
 //	@Summary Get a list of books
 //	@Description Get a list of all books.
 //	@Produce json
-//	@Success 200 {object} dto.SuccessResponse[[]dto.BookDTO]
+//	@Param q query string false "Title or subtitle"
+//	@Param publisher_id query int false "Publisher ID"
+//	@Param author_id query int false "Author ID, in any contributor role"
+//	@Param item_type query string false "Item type"
+//	@Param s query int false "Data offset"
+//	@Param l query int false "Data limit"
+//	@Param sort query string false "Sort by id, title, item_type, created_at, comma separated, descending when prefixed with -"
+//	@Param cursor query string false "Next or previous cursor of a page, in place of the offset"
+//	@Success 200 {object} dto.SuccessResponse[[]dto.BookResp]
 //	@Failure 404 {object} dto.ErrorResponse
+//	@Failure 422 {object} dto.ErrorResponse
 //	@Failure 500 {object} dto.ErrorResponse
 //	@Router /books [get]
 func (h *BookHandler) getList(c *gin.Context) {
-	filter := &dto.Filter{}
-	data, err := h.service.GetList(filter)
+	var req dto.BookFilter
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(h.hr.BindingError(err))
+		return
+	}
+
+	data, meta, err := h.service.GetList(c.Request.Context(), &req)
 	if err != nil {
 		switch {
 		case errors.Is(err, exception.ErrDataNotFound):
 			c.JSON(http.StatusNotFound, h.hr.ErrorResponse(err.Error()))
+		case errors.Is(err, exception.ErrSortInvalid),
+			errors.Is(err, exception.ErrCursorInvalid):
+			c.JSON(http.StatusUnprocessableEntity, h.hr.ErrorResponse(err.Error()))
 		default:
 			h.hr.ErrorInternalServer(c, err)
 		}
 		return
 	}
 
-	var bookDTOs []dto.BookDTO
-	for _, item := range data {
-		var bookDTO dto.BookDTO
-		bookDTO.Title = item.Title
-		bookDTO.Subtitle = item.Subtitle
-		bookDTO.PublisherID = item.PublisherID
-		bookDTO.AuthorID = item.AuthorID
-		bookDTOs = append(bookDTOs, bookDTO)
-	}
-
-	c.JSON(http.StatusOK, dto.SuccessResponse[[]dto.BookDTO]{
+	c.JSON(http.StatusOK, dto.SuccessResponse[[]dto.BookResp]{
 		Success: true,
 		Message: "List of books",
-		Data:    bookDTOs,
+		Data:    data,
+		Meta:    &meta,
 	})
 }
 
@@ -108,7 +130,7 @@ func (h *BookHandler) getList(c *gin.Context) {
 //	@Description Get details of a specific book by ID.
 //	@Produce json
 //	@Param id path int true "Book ID"
-//	@Success 200 {object} dto.SuccessResponse[dto.BookDTO]
+//	@Success 200 {object} dto.SuccessResponse[dto.BookResp]
 //	@Failure 400 {object} dto.ErrorResponse
 //	@Failure 404 {object} dto.ErrorResponse
 //	@Failure 500 {object} dto.ErrorResponse
@@ -121,7 +143,7 @@ func (h *BookHandler) getByID(c *gin.Context) {
 		return
 	}
 
-	data, err := h.service.GetByID(uint(id))
+	data, err := h.service.GetByID(c.Request.Context(), uint(id))
 	if err != nil {
 		switch {
 		case errors.Is(err, exception.ErrDataNotFound):
@@ -132,16 +154,42 @@ func (h *BookHandler) getByID(c *gin.Context) {
 		return
 	}
 
-	var bookDTO dto.BookDTO
-	bookDTO.Title = data.Title
-	bookDTO.Subtitle = data.Subtitle
-	bookDTO.PublisherID = data.PublisherID
-	bookDTO.AuthorID = data.AuthorID
+	c.JSON(http.StatusOK, dto.SuccessResponse[dto.BookResp]{
+		Success: true,
+		Message: "Book details",
+		Data:    data,
+	})
+}
+
+// getByISBN godoc
+//
+//	@Summary Get a book by its ISBN
+//	@Description Get a book by its ISBN-10 or ISBN-13, with or without hyphens.
+//	@Produce json
+//	@Param isbn path string true "ISBN-10 or ISBN-13"
+//	@Success 200 {object} dto.SuccessResponse[dto.BookResp]
+//	@Failure 400 {object} dto.ErrorResponse
+//	@Failure 404 {object} dto.ErrorResponse
+//	@Failure 500 {object} dto.ErrorResponse
+//	@Router /books/isbn/{isbn} [get]
+func (h *BookHandler) getByISBN(c *gin.Context) {
+	data, err := h.service.GetByISBN(c.Request.Context(), c.Param("isbn"))
+	if err != nil {
+		switch {
+		case errors.Is(err, util.ErrISBNInvalid):
+			c.JSON(http.StatusBadRequest, h.hr.ErrorResponse(err.Error()))
+		case errors.Is(err, exception.ErrDataNotFound):
+			c.JSON(http.StatusNotFound, h.hr.ErrorResponse(err.Error()))
+		default:
+			h.hr.ErrorInternalServer(c, err)
+		}
+		return
+	}
 
-	c.JSON(http.StatusOK, dto.SuccessResponse[dto.BookDTO]{
+	c.JSON(http.StatusOK, dto.SuccessResponse[dto.BookResp]{
 		Success: true,
 		Message: "Book details",
-		Data:    bookDTO,
+		Data:    data,
 	})
 }
 
@@ -159,38 +207,51 @@ func (h *BookHandler) getByID(c *gin.Context) {
 //	@Failure 401 {object} dto.ErrorResponse
 //	@Failure 403 {object} dto.ErrorResponse
 //	@Failure 404 {object} dto.ErrorResponse
+//	@Failure 409 {object} dto.ErrorResponse
+//	@Failure 422 {object} dto.ErrorResponse
 //	@Failure 500 {object} dto.ErrorResponse
 //	@Router /books/{id} [put]
+//
 // Di rest/book_handler.go
 // Di rest/book_handler.go
 func (h *BookHandler) update(c *gin.Context) {
-    idStr := c.Param("id")
-    id, err := strconv.ParseUint(idStr, 10, 64)
-    if err != nil {
-        c.JSON(http.StatusBadRequest, h.hr.ErrorResponse("Invalid ID"))
-        return
-    }
-
-    var input dto.BookUpdate
-    if err := c.ShouldBindJSON(&input); err != nil {
-        c.JSON(http.StatusBadRequest, h.hr.ErrorResponse(err.Error()))
-        return
-    }
-
-    // Set ID dari parameter ke input
-    input.ID = uint(id)
-
-    // Gunakan service untuk update
-    err = h.service.Update(&input)
-    if err != nil {
-        h.hr.ErrorInternalServer(c, err)
-        return
-    }
-
-    c.JSON(http.StatusOK, dto.SuccessResponse[any]{
-        Success: true,
-        Message: "Book updated successfully",
-    })
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, h.hr.ErrorResponse("Invalid ID"))
+		return
+	}
+
+	var input dto.BookUpdate
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, h.hr.ErrorResponse(err.Error()))
+		return
+	}
+
+	// Set ID dari parameter ke input
+	input.ID = uint(id)
+
+	// Gunakan service untuk update
+	err = h.service.Update(c.Request.Context(), &input)
+	if err != nil {
+		switch {
+		case errors.Is(err, exception.ErrDataNotFound):
+			c.JSON(http.StatusNotFound, h.hr.ErrorResponse(err.Error()))
+		case errors.Is(err, exception.ErrISBNConflict):
+			c.JSON(http.StatusConflict, h.hr.ErrorResponse(err.Error()))
+		case errors.Is(err, exception.ErrContributorNotFound),
+			errors.Is(err, exception.ErrCategoryNotFound):
+			c.JSON(http.StatusUnprocessableEntity, h.hr.ErrorResponse(err.Error()))
+		default:
+			h.hr.ErrorInternalServer(c, err)
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse[any]{
+		Success: true,
+		Message: "Book updated successfully",
+	})
 }
 
 // delete godoc
@@ -208,25 +269,25 @@ func (h *BookHandler) update(c *gin.Context) {
 //	@Failure 500 {object} dto.ErrorResponse
 //	@Router /books/{id} [delete]
 func (h *BookHandler) delete(c *gin.Context) {
-    idStr := c.Param("id")
-    id, err := strconv.ParseUint(idStr, 10, 64)
-    if err != nil {
-        c.JSON(http.StatusBadRequest, h.hr.ErrorResponse("Invalid ID"))
-        return
-    }
-
-    err = h.service.Delete(uint(id))
-    if err != nil {
-        if err.Error() == "book not found" {
-            c.JSON(http.StatusNotFound, h.hr.ErrorResponse(err.Error()))
-            return
-        }
-        h.hr.ErrorInternalServer(c, err)
-        return
-    }
-
-    c.JSON(http.StatusOK, dto.SuccessResponse[any]{
-        Success: true,
-        Message: "Book deleted successfully",
-    })
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, h.hr.ErrorResponse("Invalid ID"))
+		return
+	}
+
+	err = h.service.Delete(c.Request.Context(), uint(id))
+	if err != nil {
+		if errors.Is(err, exception.ErrDataNotFound) {
+			c.JSON(http.StatusNotFound, h.hr.ErrorResponse(err.Error()))
+			return
+		}
+		h.hr.ErrorInternalServer(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse[any]{
+		Success: true,
+		Message: "Book deleted successfully",
+	})
 }
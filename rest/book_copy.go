@@ -0,0 +1,253 @@
+package rest
+
+import (
+	"base-gin/domain"
+	"base-gin/domain/dto"
+	"base-gin/exception"
+	"base-gin/server"
+	"base-gin/service"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type BookCopyHandler struct {
+	hr      *server.Handler
+	service *service.BookCopyService
+}
+
+func NewBookCopyHandler(handler *server.Handler, bookCopyService *service.BookCopyService) *BookCopyHandler {
+	return &BookCopyHandler{hr: handler, service: bookCopyService}
+}
+
+func (h *BookCopyHandler) Route(app *gin.Engine) {
+	staffOnly := h.hr.RequireRole(domain.RoleAdmin, domain.RoleLibrarian)
+
+	grp := app.Group(server.RootBook)
+	grp.POST(server.PathBookCopies, h.hr.AuthAccess(), staffOnly, h.create)
+	grp.GET(server.PathBookCopies, h.getList)
+	grp.GET(server.PathBookCopy, h.getByID)
+	grp.PUT(server.PathBookCopy, h.hr.AuthAccess(), staffOnly, h.update)
+	grp.DELETE(server.PathBookCopy, h.hr.AuthAccess(), staffOnly, h.delete)
+}
+
+// parseIDs reads the book & copy IDs from the path. The copy ID is zero when
+// the route has none.
+func (h *BookCopyHandler) parseIDs(c *gin.Context) (uint, uint, bool) {
+	bookID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, h.hr.ErrorResponse("ID tidak valid"))
+		return 0, 0, false
+	}
+
+	var copyID uint64
+	if s := c.Param("copyId"); s != "" {
+		copyID, err = strconv.ParseUint(s, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, h.hr.ErrorResponse("ID tidak valid"))
+			return 0, 0, false
+		}
+	}
+
+	return uint(bookID), uint(copyID), true
+}
+
+// create godoc
+//
+//	@Summary Add a copy of a book
+//	@Description Register a physical copy of a book.
+//	@Accept json
+//	@Produce json
+//	@Security BearerAuth
+//	@Param id path int true "Book ID"
+//	@Param detail body dto.BookCopyReq true "Copy's detail"
+//	@Success 201 {object} dto.SuccessResponse[dto.BookCopyResp]
+//	@Failure 400 {object} dto.ErrorResponse
+//	@Failure 401 {object} dto.ErrorResponse
+//	@Failure 403 {object} dto.ErrorResponse
+//	@Failure 404 {object} dto.ErrorResponse
+//	@Failure 409 {object} dto.ErrorResponse
+//	@Failure 422 {object} dto.ErrorResponse
+//	@Failure 500 {object} dto.ErrorResponse
+//	@Router /books/{id}/copies [post]
+func (h *BookCopyHandler) create(c *gin.Context) {
+	bookID, _, ok := h.parseIDs(c)
+	if !ok {
+		return
+	}
+
+	var req dto.BookCopyReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(h.hr.BindingError(err))
+		return
+	}
+	req.BookID = bookID
+
+	data, err := h.service.Create(c.Request.Context(), &req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.SuccessResponse[dto.BookCopyResp]{
+		Success: true,
+		Message: "Eksemplar berhasil ditambahkan",
+		Data:    data,
+	})
+}
+
+// getList godoc
+//
+//	@Summary Get the copies of a book
+//	@Description Get every physical copy of a book.
+//	@Produce json
+//	@Param id path int true "Book ID"
+//	@Success 200 {object} dto.SuccessResponse[[]dto.BookCopyResp]
+//	@Failure 400 {object} dto.ErrorResponse
+//	@Failure 404 {object} dto.ErrorResponse
+//	@Failure 500 {object} dto.ErrorResponse
+//	@Router /books/{id}/copies [get]
+func (h *BookCopyHandler) getList(c *gin.Context) {
+	bookID, _, ok := h.parseIDs(c)
+	if !ok {
+		return
+	}
+
+	data, err := h.service.GetList(c.Request.Context(), bookID)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse[[]dto.BookCopyResp]{
+		Success: true,
+		Message: "Daftar eksemplar",
+		Data:    data,
+	})
+}
+
+// getByID godoc
+//
+//	@Summary Get a copy's detail
+//	@Description Get the detail of a physical copy of a book.
+//	@Produce json
+//	@Param id path int true "Book ID"
+//	@Param copyId path int true "Copy ID"
+//	@Success 200 {object} dto.SuccessResponse[dto.BookCopyResp]
+//	@Failure 400 {object} dto.ErrorResponse
+//	@Failure 404 {object} dto.ErrorResponse
+//	@Failure 500 {object} dto.ErrorResponse
+//	@Router /books/{id}/copies/{copyId} [get]
+func (h *BookCopyHandler) getByID(c *gin.Context) {
+	bookID, copyID, ok := h.parseIDs(c)
+	if !ok {
+		return
+	}
+
+	data, err := h.service.GetByID(c.Request.Context(), bookID, copyID)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse[dto.BookCopyResp]{
+		Success: true,
+		Message: "Detail eksemplar",
+		Data:    data,
+	})
+}
+
+// update godoc
+//
+//	@Summary Update a copy's detail
+//	@Description Update a copy's detail. The status of a copy on loan can not be changed.
+//	@Accept json
+//	@Produce json
+//	@Security BearerAuth
+//	@Param id path int true "Book ID"
+//	@Param copyId path int true "Copy ID"
+//	@Param detail body dto.BookCopyReq true "Copy's detail"
+//	@Success 200 {object} dto.SuccessResponse[any]
+//	@Failure 400 {object} dto.ErrorResponse
+//	@Failure 401 {object} dto.ErrorResponse
+//	@Failure 403 {object} dto.ErrorResponse
+//	@Failure 404 {object} dto.ErrorResponse
+//	@Failure 409 {object} dto.ErrorResponse
+//	@Failure 422 {object} dto.ErrorResponse
+//	@Failure 500 {object} dto.ErrorResponse
+//	@Router /books/{id}/copies/{copyId} [put]
+func (h *BookCopyHandler) update(c *gin.Context) {
+	bookID, copyID, ok := h.parseIDs(c)
+	if !ok {
+		return
+	}
+
+	var req dto.BookCopyReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(h.hr.BindingError(err))
+		return
+	}
+	req.ID = copyID
+	req.BookID = bookID
+
+	if err := h.service.Update(c.Request.Context(), &req); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse[any]{
+		Success: true,
+		Message: "Data berhasil disimpan",
+	})
+}
+
+// delete godoc
+//
+//	@Summary Delete a copy
+//	@Description Delete a copy that was never lent out. Withdraw it instead when it has a borrowing history.
+//	@Produce json
+//	@Security BearerAuth
+//	@Param id path int true "Book ID"
+//	@Param copyId path int true "Copy ID"
+//	@Success 200 {object} dto.SuccessResponse[any]
+//	@Failure 400 {object} dto.ErrorResponse
+//	@Failure 401 {object} dto.ErrorResponse
+//	@Failure 403 {object} dto.ErrorResponse
+//	@Failure 404 {object} dto.ErrorResponse
+//	@Failure 409 {object} dto.ErrorResponse
+//	@Failure 500 {object} dto.ErrorResponse
+//	@Router /books/{id}/copies/{copyId} [delete]
+func (h *BookCopyHandler) delete(c *gin.Context) {
+	bookID, copyID, ok := h.parseIDs(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.Delete(c.Request.Context(), bookID, copyID); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse[any]{
+		Success: true,
+		Message: "Data berhasil dihapus",
+	})
+}
+
+func (h *BookCopyHandler) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, exception.ErrDataNotFound):
+		c.JSON(http.StatusNotFound, h.hr.ErrorResponse(err.Error()))
+	case errors.Is(err, exception.ErrDateParsing):
+		c.JSON(http.StatusBadRequest, h.hr.ErrorResponse(err.Error()))
+	case errors.Is(err, exception.ErrBarcodeConflict),
+		errors.Is(err, exception.ErrCopyOnLoan),
+		errors.Is(err, exception.ErrCopyOnHold),
+		errors.Is(err, exception.ErrCopyHasHistory):
+		c.JSON(http.StatusConflict, h.hr.ErrorResponse(err.Error()))
+	default:
+		h.hr.ErrorInternalServer(c, err)
+	}
+}
@@ -1,6 +1,7 @@
 package rest
 
 import (
+	"base-gin/domain"
 	"base-gin/domain/dto"
 	"base-gin/exception"
 	"base-gin/server"
@@ -22,58 +23,93 @@ func NewBorrowingHandler(handler *server.Handler, borrowingService *service.Borr
 }
 
 func (h *BorrowingHandler) Route(app *gin.Engine) {
-	grp := app.Group(server.RootBorrowing)
-	grp.POST("", h.hr.AuthAccess(), h.create)
+	staffOnly := h.hr.RequireRole(domain.RoleAdmin, domain.RoleLibrarian)
+
+	grp := app.Group(server.RootBorrowing, h.hr.AuthAccess())
 	grp.GET("", h.getList)
+	grp.GET(server.PathOverdue, staffOnly, h.getOverdue)
 	grp.GET("/:id", h.getByID)
-	grp.PUT("/:id", h.hr.AuthAccess(), h.update)
-	grp.DELETE("/:id", h.hr.AuthAccess(), h.delete)
+	grp.POST(server.PathRenew, h.renew)
+	grp.GET(server.PathRenewals, h.getRenewals)
+	grp.DELETE("/:id", staffOnly, h.delete)
+
+	app.GET(server.RootAccount+server.PathMyBorrowings, h.hr.AuthAccess(), h.getOwn)
+	app.GET(server.RootPerson+server.PathBorrowings, h.hr.AuthAccess(), h.getByPerson)
+	app.GET(server.RootBook+server.PathBorrowings, h.hr.AuthAccess(), staffOnly, h.getByBook)
 }
 
-// create godoc
+// getList godoc
 //
-// @Summary Create a borrowing
-// @Description Create a new borrowing record.
-// @Accept json
+// @Summary Get a list of borrowings
+// @Description Get a list of borrowing records along with the book & person, the most recent first. Members only get their own.
 // @Produce json
 // @Security BearerAuth
-// @Param detail body dto.BorrowingDTO true "Borrowing's detail"
-// @Success 201 {object} dto.SuccessResponse[any]
+// @Param status query string false "Status" Enums(active, returned, overdue)
+// @Param from query string false "Borrowed on or after (YYYY-MM-DD)"
+// @Param to query string false "Borrowed on or before (YYYY-MM-DD)"
+// @Param s query int false "Data offset"
+// @Param l query int false "Data limit"
+// @Param q query string false "Book title or person's name"
+// @Param sort query string false "Sort by id, borrow_date, due_date, created_at, comma separated, descending when prefixed with -"
+// @Param cursor query string false "Next or previous cursor of a page, in place of the offset"
+// @Success 200 {object} dto.SuccessResponse[[]dto.BorrowingResp]
+// @Failure 400 {object} dto.ErrorResponse
 // @Failure 401 {object} dto.ErrorResponse
 // @Failure 422 {object} dto.ErrorResponse
 // @Failure 500 {object} dto.ErrorResponse
-// @Router /borrowings [post]
-func (h *BorrowingHandler) create(c *gin.Context) {
-	var req dto.BorrowingDTO
-	if err := c.ShouldBindJSON(&req); err != nil {
-		h.hr.BindingError(err)
+// @Router /borrowings [get]
+func (h *BorrowingHandler) getList(c *gin.Context) {
+	var req dto.BorrowingFilter
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(h.hr.BindingError(err))
 		return
 	}
 
-	err := h.service.Create(&req)
+	data, meta, err := h.service.GetList(c.Request.Context(), &req, memberAccountID(c))
 	if err != nil {
-		h.hr.ErrorInternalServer(c, err)
+		h.handleListError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusCreated, dto.SuccessResponse[any]{
+	c.JSON(http.StatusOK, dto.SuccessResponse[[]dto.BorrowingResp]{
 		Success: true,
-		Message: "Borrowing created successfully",
+		Message: "List of borrowings",
+		Data:    data,
+		Meta:    &meta,
 	})
 }
 
-// getList godoc
+// getOwn godoc
 //
-// @Summary Get a list of borrowings
-// @Description Get a list of all borrowing records.
+// @Summary Get the logged in account's borrowings
+// @Description Get the borrowing history of the logged in account's person, the most recent first.
 // @Produce json
+// @Security BearerAuth
+// @Param status query string false "Status" Enums(active, returned, overdue)
+// @Param from query string false "Borrowed on or after (YYYY-MM-DD)"
+// @Param to query string false "Borrowed on or before (YYYY-MM-DD)"
+// @Param s query int false "Data offset"
+// @Param l query int false "Data limit"
+// @Param q query string false "Book title or person's name"
+// @Param sort query string false "Sort by id, borrow_date, due_date, created_at, comma separated, descending when prefixed with -"
+// @Param cursor query string false "Next or previous cursor of a page, in place of the offset"
 // @Success 200 {object} dto.SuccessResponse[[]dto.BorrowingResp]
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 422 {object} dto.ErrorResponse
 // @Failure 500 {object} dto.ErrorResponse
-// @Router /borrowings [get]
-func (h *BorrowingHandler) getList(c *gin.Context) {
-	data, err := h.service.GetList()
+// @Router /accounts/borrowings [get]
+func (h *BorrowingHandler) getOwn(c *gin.Context) {
+	var req dto.BorrowingFilter
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(h.hr.BindingError(err))
+		return
+	}
+
+	accountID := c.GetUint(server.ParamTokenUserID)
+	data, meta, err := h.service.GetList(c.Request.Context(), &req, &accountID)
 	if err != nil {
-		h.hr.ErrorInternalServer(c, err)
+		h.handleListError(c, err)
 		return
 	}
 
@@ -81,17 +117,164 @@ func (h *BorrowingHandler) getList(c *gin.Context) {
 		Success: true,
 		Message: "List of borrowings",
 		Data:    data,
+		Meta:    &meta,
+	})
+}
+
+// getByPerson godoc
+//
+// @Summary Get the borrowings of a person
+// @Description Get the borrowing history of a person, the most recent first. Members may only get their own.
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Person ID"
+// @Param status query string false "Status" Enums(active, returned, overdue)
+// @Param from query string false "Borrowed on or after (YYYY-MM-DD)"
+// @Param to query string false "Borrowed on or before (YYYY-MM-DD)"
+// @Param s query int false "Data offset"
+// @Param l query int false "Data limit"
+// @Param q query string false "Book title or person's name"
+// @Param sort query string false "Sort by id, borrow_date, due_date, created_at, comma separated, descending when prefixed with -"
+// @Param cursor query string false "Next or previous cursor of a page, in place of the offset"
+// @Success 200 {object} dto.SuccessResponse[[]dto.BorrowingResp]
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 422 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /persons/{id}/borrowings [get]
+func (h *BorrowingHandler) getByPerson(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, h.hr.ErrorResponse("Invalid ID"))
+		return
+	}
+
+	var req dto.BorrowingFilter
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(h.hr.BindingError(err))
+		return
+	}
+
+	data, meta, err := h.service.GetListByPersonID(c.Request.Context(), uint(id), &req, memberAccountID(c))
+	if err != nil {
+		switch {
+		case errors.Is(err, exception.ErrUserNotFound):
+			c.JSON(http.StatusNotFound, h.hr.ErrorResponse(exception.ErrDataNotFound.Error()))
+		case errors.Is(err, exception.ErrForbidden):
+			c.JSON(http.StatusForbidden, h.hr.ErrorResponse(err.Error()))
+		default:
+			h.handleListError(c, err)
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse[[]dto.BorrowingResp]{
+		Success: true,
+		Message: "List of borrowings",
+		Data:    data,
+		Meta:    &meta,
+	})
+}
+
+// getByBook godoc
+//
+// @Summary Get the borrowings of a book
+// @Description Get the borrowing history of a book, the most recent first.
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Book ID"
+// @Param status query string false "Status" Enums(active, returned, overdue)
+// @Param from query string false "Borrowed on or after (YYYY-MM-DD)"
+// @Param to query string false "Borrowed on or before (YYYY-MM-DD)"
+// @Param s query int false "Data offset"
+// @Param l query int false "Data limit"
+// @Param q query string false "Book title or person's name"
+// @Param sort query string false "Sort by id, borrow_date, due_date, created_at, comma separated, descending when prefixed with -"
+// @Param cursor query string false "Next or previous cursor of a page, in place of the offset"
+// @Success 200 {object} dto.SuccessResponse[[]dto.BorrowingResp]
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 422 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /book/{id}/borrowings [get]
+func (h *BorrowingHandler) getByBook(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, h.hr.ErrorResponse("Invalid ID"))
+		return
+	}
+
+	var req dto.BorrowingFilter
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(h.hr.BindingError(err))
+		return
+	}
+
+	data, meta, err := h.service.GetListByBookID(c.Request.Context(), uint(id), &req)
+	if err != nil {
+		if errors.Is(err, exception.ErrDataNotFound) {
+			c.JSON(http.StatusNotFound, h.hr.ErrorResponse(err.Error()))
+		} else {
+			h.handleListError(c, err)
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse[[]dto.BorrowingResp]{
+		Success: true,
+		Message: "List of borrowings",
+		Data:    data,
+		Meta:    &meta,
+	})
+}
+
+// getOverdue godoc
+//
+// @Summary Get a list of overdue borrowings
+// @Description Get the borrowings not returned past their due date along with the book & person, the longest overdue first.
+// @Produce json
+// @Security BearerAuth
+// @Param min_days query int false "Minimum days overdue"
+// @Success 200 {object} dto.SuccessResponse[[]dto.BorrowingResp]
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 422 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /borrowings/overdue [get]
+func (h *BorrowingHandler) getOverdue(c *gin.Context) {
+	var req dto.OverdueFilter
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(h.hr.BindingError(err))
+		return
+	}
+
+	data, err := h.service.GetOverdue(c.Request.Context(), &req)
+	if err != nil {
+		h.hr.ErrorInternalServer(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse[[]dto.BorrowingResp]{
+		Success: true,
+		Message: "List of overdue borrowings",
+		Data:    data,
 	})
 }
 
 // getByID godoc
 //
 // @Summary Get a borrowing's detail
-// @Description Get details of a specific borrowing by ID.
+// @Description Get details of a specific borrowing by ID. Members only get their own.
 // @Produce json
+// @Security BearerAuth
 // @Param id path int true "Borrowing ID"
 // @Success 200 {object} dto.SuccessResponse[dto.BorrowingResp]
 // @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
 // @Failure 404 {object} dto.ErrorResponse
 // @Failure 500 {object} dto.ErrorResponse
 // @Router /borrowings/{id} [get]
@@ -103,7 +286,7 @@ func (h *BorrowingHandler) getByID(c *gin.Context) {
 		return
 	}
 
-	data, err := h.service.GetByID(uint(id))
+	data, err := h.service.GetByID(c.Request.Context(), uint(id), memberAccountID(c))
 	if err != nil {
 		if errors.Is(err, exception.ErrDataNotFound) {
 			c.JSON(http.StatusNotFound, h.hr.ErrorResponse(err.Error()))
@@ -120,21 +303,22 @@ func (h *BorrowingHandler) getByID(c *gin.Context) {
 	})
 }
 
-// update godoc
+// renew godoc
 //
-// @Summary Update a borrowing's detail
-// @Description Update details of a specific borrowing by ID.
-// @Accept json
+// @Summary Renew a borrowing
+// @Description Extend the due date of a borrowing by the loan period of its circulation rule, up to the renewals it allows. Members may only renew their own.
 // @Produce json
 // @Security BearerAuth
 // @Param id path int true "Borrowing ID"
-// @Param detail body dto.BorrowingUpdate true "Borrowing's updated detail"
-// @Success 200 {object} dto.SuccessResponse[any]
+// @Success 200 {object} dto.SuccessResponse[dto.BorrowingResp]
 // @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
 // @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 422 {object} dto.ErrorResponse
 // @Failure 500 {object} dto.ErrorResponse
-// @Router /borrowings/{id} [put]
-func (h *BorrowingHandler) update(c *gin.Context) {
+// @Router /borrowings/{id}/renew [post]
+func (h *BorrowingHandler) renew(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 64)
 	if err != nil {
@@ -142,14 +326,56 @@ func (h *BorrowingHandler) update(c *gin.Context) {
 		return
 	}
 
-	var req dto.BorrowingUpdate
-	if err := c.ShouldBindJSON(&req); err != nil {
-		h.hr.BindingError(err)
+	accountID := c.GetUint(server.ParamTokenUserID)
+	req := dto.BorrowingRenewReq{ID: uint(id), RenewedByID: &accountID}
+
+	data, err := h.service.Renew(c.Request.Context(), &req, memberAccountID(c))
+	if err != nil {
+		var violation *exception.PolicyViolation
+		switch {
+		case errors.As(err, &violation):
+			c.JSON(h.hr.PolicyViolation(violation))
+		case errors.Is(err, exception.ErrDataNotFound),
+			errors.Is(err, exception.ErrUserNotFound):
+			c.JSON(http.StatusNotFound, h.hr.ErrorResponse(err.Error()))
+		case errors.Is(err, exception.ErrBorrowingReturned),
+			errors.Is(err, exception.ErrHoldQueued):
+			c.JSON(http.StatusConflict, h.hr.ErrorResponse(err.Error()))
+		default:
+			h.hr.ErrorInternalServer(c, err)
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse[dto.BorrowingResp]{
+		Success: true,
+		Message: "Borrowing renewed successfully",
+		Data:    data,
+	})
+}
+
+// getRenewals godoc
+//
+// @Summary Get the renewals of a borrowing
+// @Description Get the renewal history of a borrowing, the oldest first. Members only get their own.
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Borrowing ID"
+// @Success 200 {object} dto.SuccessResponse[[]dto.BorrowingRenewalResp]
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /borrowings/{id}/renewals [get]
+func (h *BorrowingHandler) getRenewals(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, h.hr.ErrorResponse("Invalid ID"))
 		return
 	}
-	req.ID = uint(id)
 
-	err = h.service.Update(&req)
+	data, err := h.service.GetRenewals(c.Request.Context(), uint(id), memberAccountID(c))
 	if err != nil {
 		if errors.Is(err, exception.ErrDataNotFound) {
 			c.JSON(http.StatusNotFound, h.hr.ErrorResponse(err.Error()))
@@ -159,9 +385,10 @@ func (h *BorrowingHandler) update(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, dto.SuccessResponse[any]{
+	c.JSON(http.StatusOK, dto.SuccessResponse[[]dto.BorrowingRenewalResp]{
 		Success: true,
-		Message: "Borrowing updated successfully",
+		Message: "List of renewals",
+		Data:    data,
 	})
 }
 
@@ -174,6 +401,8 @@ func (h *BorrowingHandler) update(c *gin.Context) {
 // @Param id path int true "Borrowing ID"
 // @Success 200 {object} dto.SuccessResponse[any]
 // @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
 // @Failure 404 {object} dto.ErrorResponse
 // @Failure 500 {object} dto.ErrorResponse
 // @Router /borrowings/{id} [delete]
@@ -185,7 +414,7 @@ func (h *BorrowingHandler) delete(c *gin.Context) {
 		return
 	}
 
-	err = h.service.Delete(uint(id))
+	err = h.service.Delete(c.Request.Context(), uint(id))
 	if err != nil {
 		if errors.Is(err, exception.ErrDataNotFound) {
 			c.JSON(http.StatusNotFound, h.hr.ErrorResponse(err.Error()))
@@ -200,3 +429,14 @@ func (h *BorrowingHandler) delete(c *gin.Context) {
 		Message: "Borrowing deleted successfully",
 	})
 }
+
+// handleListError reports a failed borrowing history lookup.
+func (h *BorrowingHandler) handleListError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, exception.ErrSortInvalid),
+		errors.Is(err, exception.ErrCursorInvalid):
+		c.JSON(http.StatusUnprocessableEntity, h.hr.ErrorResponse(err.Error()))
+	default:
+		h.hr.ErrorInternalServer(c, err)
+	}
+}
@@ -0,0 +1,274 @@
+package rest
+
+import (
+	"base-gin/domain"
+	"base-gin/domain/dto"
+	"base-gin/exception"
+	"base-gin/server"
+	"base-gin/service"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CategoryHandler struct {
+	hr          *server.Handler
+	service     *service.CategoryService
+	bookService *service.BookService
+}
+
+func NewCategoryHandler(
+	handler *server.Handler,
+	categoryService *service.CategoryService,
+	bookService *service.BookService,
+) *CategoryHandler {
+	return &CategoryHandler{hr: handler, service: categoryService, bookService: bookService}
+}
+
+func (h *CategoryHandler) Route(app *gin.Engine) {
+	staffOnly := h.hr.RequireRole(domain.RoleAdmin, domain.RoleLibrarian)
+
+	grp := app.Group(server.RootCategory)
+	grp.POST("", h.hr.AuthAccess(), staffOnly, h.create)
+	grp.GET("", h.getTree)
+	grp.GET("/:id", h.getByID)
+	grp.GET(server.PathCategoryBooks, h.getBooks)
+	grp.PUT("/:id", h.hr.AuthAccess(), staffOnly, h.update)
+	grp.DELETE("/:id", h.hr.AuthAccess(), staffOnly, h.delete)
+}
+
+// create godoc
+//
+//	@Summary Create a category
+//	@Description Create a category, at the top or under a parent. The slug is made from the name when not given.
+//	@Accept json
+//	@Produce json
+//	@Security BearerAuth
+//	@Param detail body dto.CategoryReq true "Category's detail"
+//	@Success 201 {object} dto.SuccessResponse[dto.CategoryResp]
+//	@Failure 401 {object} dto.ErrorResponse
+//	@Failure 403 {object} dto.ErrorResponse
+//	@Failure 409 {object} dto.ErrorResponse
+//	@Failure 422 {object} dto.ErrorResponse
+//	@Failure 500 {object} dto.ErrorResponse
+//	@Router /categories [post]
+func (h *CategoryHandler) create(c *gin.Context) {
+	var req dto.CategoryReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(h.hr.BindingError(err))
+		return
+	}
+
+	data, err := h.service.Create(c.Request.Context(), &req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.SuccessResponse[dto.CategoryResp]{
+		Success: true,
+		Message: "Data berhasil disimpan",
+		Data:    data,
+	})
+}
+
+// getTree godoc
+//
+//	@Summary Get the category tree
+//	@Description Get the top categories with their subcategories nested, each with the number of books filed under it and under its subtree.
+//	@Produce json
+//	@Success 200 {object} dto.SuccessResponse[[]dto.CategoryResp]
+//	@Failure 500 {object} dto.ErrorResponse
+//	@Router /categories [get]
+func (h *CategoryHandler) getTree(c *gin.Context) {
+	data, err := h.service.GetTree(c.Request.Context())
+	if err != nil {
+		h.hr.ErrorInternalServer(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse[[]dto.CategoryResp]{
+		Success: true,
+		Message: "Daftar kategori",
+		Data:    data,
+	})
+}
+
+// getByID godoc
+//
+//	@Summary Get a category's detail
+//	@Description Get a category with its path from the top and its direct subcategories.
+//	@Produce json
+//	@Param id path int true "Category's ID"
+//	@Success 200 {object} dto.SuccessResponse[dto.CategoryResp]
+//	@Failure 400 {object} dto.ErrorResponse
+//	@Failure 404 {object} dto.ErrorResponse
+//	@Failure 500 {object} dto.ErrorResponse
+//	@Router /categories/{id} [get]
+func (h *CategoryHandler) getByID(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, h.hr.ErrorResponse("ID tidak valid"))
+		return
+	}
+
+	data, err := h.service.GetByID(c.Request.Context(), uint(id))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse[dto.CategoryResp]{
+		Success: true,
+		Message: "Detail kategori",
+		Data:    data,
+	})
+}
+
+// getBooks godoc
+//
+//	@Summary Get the books of a category
+//	@Description Get the books filed under a category or any of its subcategories, by title unless sorted otherwise.
+//	@Produce json
+//	@Param id path int true "Category ID"
+//	@Param q query string false "Title or subtitle"
+//	@Param s query int false "Data offset"
+//	@Param l query int false "Data limit"
+//	@Param sort query string false "Sort by id, title, item_type, created_at, comma separated, descending when prefixed with -"
+//	@Param cursor query string false "Next or previous cursor of a page, in place of the offset"
+//	@Success 200 {object} dto.SuccessResponse[[]dto.BookResp]
+//	@Failure 400 {object} dto.ErrorResponse
+//	@Failure 404 {object} dto.ErrorResponse
+//	@Failure 422 {object} dto.ErrorResponse
+//	@Failure 500 {object} dto.ErrorResponse
+//	@Router /categories/{id}/books [get]
+func (h *CategoryHandler) getBooks(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, h.hr.ErrorResponse("Invalid ID"))
+		return
+	}
+
+	var req dto.Filter
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(h.hr.BindingError(err))
+		return
+	}
+
+	data, meta, err := h.bookService.GetListByCategoryID(c.Request.Context(), uint(id), &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, exception.ErrDataNotFound):
+			c.JSON(http.StatusNotFound, h.hr.ErrorResponse(err.Error()))
+		case errors.Is(err, exception.ErrSortInvalid),
+			errors.Is(err, exception.ErrCursorInvalid):
+			c.JSON(http.StatusUnprocessableEntity, h.hr.ErrorResponse(err.Error()))
+		default:
+			h.hr.ErrorInternalServer(c, err)
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse[[]dto.BookResp]{
+		Success: true,
+		Message: "List of the category's books",
+		Data:    data,
+		Meta:    &meta,
+	})
+}
+
+// update godoc
+//
+//	@Summary Update a category's detail
+//	@Description Update a category's detail. A category cannot be moved under itself or its subcategories.
+//	@Accept json
+//	@Produce json
+//	@Security BearerAuth
+//	@Param id path int true "Category's ID"
+//	@Param detail body dto.CategoryReq true "Category's detail"
+//	@Success 200 {object} dto.SuccessResponse[dto.CategoryResp]
+//	@Failure 400 {object} dto.ErrorResponse
+//	@Failure 401 {object} dto.ErrorResponse
+//	@Failure 403 {object} dto.ErrorResponse
+//	@Failure 404 {object} dto.ErrorResponse
+//	@Failure 409 {object} dto.ErrorResponse
+//	@Failure 422 {object} dto.ErrorResponse
+//	@Failure 500 {object} dto.ErrorResponse
+//	@Router /categories/{id} [put]
+func (h *CategoryHandler) update(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, h.hr.ErrorResponse("ID tidak valid"))
+		return
+	}
+
+	var req dto.CategoryReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(h.hr.BindingError(err))
+		return
+	}
+	req.ID = uint(id)
+
+	data, err := h.service.Update(c.Request.Context(), &req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse[dto.CategoryResp]{
+		Success: true,
+		Message: "Data berhasil disimpan",
+		Data:    data,
+	})
+}
+
+// delete godoc
+//
+//	@Summary Delete a category
+//	@Description Delete a category without subcategories. Its books are kept but no longer filed under it.
+//	@Produce json
+//	@Security BearerAuth
+//	@Param id path int true "Category's ID"
+//	@Success 200 {object} dto.SuccessResponse[any]
+//	@Failure 400 {object} dto.ErrorResponse
+//	@Failure 401 {object} dto.ErrorResponse
+//	@Failure 403 {object} dto.ErrorResponse
+//	@Failure 404 {object} dto.ErrorResponse
+//	@Failure 409 {object} dto.ErrorResponse
+//	@Failure 500 {object} dto.ErrorResponse
+//	@Router /categories/{id} [delete]
+func (h *CategoryHandler) delete(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, h.hr.ErrorResponse("ID tidak valid"))
+		return
+	}
+
+	if err := h.service.Delete(c.Request.Context(), uint(id)); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse[any]{
+		Success: true,
+		Message: "Data berhasil dihapus",
+	})
+}
+
+func (h *CategoryHandler) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, exception.ErrDataNotFound):
+		c.JSON(http.StatusNotFound, h.hr.ErrorResponse(err.Error()))
+	case errors.Is(err, exception.ErrCategoryConflict),
+		errors.Is(err, exception.ErrCategoryHasChildren):
+		c.JSON(http.StatusConflict, h.hr.ErrorResponse(err.Error()))
+	case errors.Is(err, exception.ErrCategoryNotFound),
+		errors.Is(err, exception.ErrCategoryCycle),
+		errors.Is(err, exception.ErrSlugInvalid):
+		c.JSON(http.StatusUnprocessableEntity, h.hr.ErrorResponse(err.Error()))
+	default:
+		h.hr.ErrorInternalServer(c, err)
+	}
+}
@@ -0,0 +1,121 @@
+package rest
+
+import (
+	"base-gin/domain"
+	"base-gin/domain/dto"
+	"base-gin/exception"
+	"base-gin/server"
+	"base-gin/service"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CirculationHandler struct {
+	hr      *server.Handler
+	service *service.BorrowingService
+}
+
+func NewCirculationHandler(handler *server.Handler, borrowingService *service.BorrowingService) *CirculationHandler {
+	return &CirculationHandler{hr: handler, service: borrowingService}
+}
+
+func (h *CirculationHandler) Route(app *gin.Engine) {
+	grp := app.Group(server.RootCirculation,
+		h.hr.AuthAccess(), h.hr.RequireRole(domain.RoleAdmin, domain.RoleLibrarian))
+	grp.POST(server.PathCheckout, h.checkout)
+	grp.POST(server.PathCheckin, h.checkin)
+}
+
+// checkout godoc
+//
+//	@Summary Check a book out
+//	@Description Lend a book out to a person as of now. With a barcode that copy is lent out, otherwise the first available copy of the book. The due date follows the circulation rule; checkouts over its loan limit are refused with 422.
+//	@Accept json
+//	@Produce json
+//	@Security BearerAuth
+//	@Param detail body dto.CheckoutReq true "Checkout's detail"
+//	@Success 201 {object} dto.SuccessResponse[dto.BorrowingResp]
+//	@Failure 401 {object} dto.ErrorResponse
+//	@Failure 403 {object} dto.ErrorResponse
+//	@Failure 404 {object} dto.ErrorResponse
+//	@Failure 409 {object} dto.ErrorResponse
+//	@Failure 422 {object} dto.ErrorResponse
+//	@Failure 500 {object} dto.ErrorResponse
+//	@Router /circulation/checkout [post]
+func (h *CirculationHandler) checkout(c *gin.Context) {
+	var req dto.CheckoutReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(h.hr.BindingError(err))
+		return
+	}
+
+	data, err := h.service.Checkout(c.Request.Context(), &req)
+	if err != nil {
+		var violation *exception.PolicyViolation
+		switch {
+		case errors.As(err, &violation):
+			c.JSON(h.hr.PolicyViolation(violation))
+		case errors.Is(err, exception.ErrDataNotFound),
+			errors.Is(err, exception.ErrUserNotFound):
+			c.JSON(http.StatusNotFound, h.hr.ErrorResponse(err.Error()))
+		case errors.Is(err, exception.ErrBookBorrowed),
+			errors.Is(err, exception.ErrBookOnHold),
+			errors.Is(err, exception.ErrCopyUnavailable):
+			c.JSON(http.StatusConflict, h.hr.ErrorResponse(err.Error()))
+		default:
+			h.hr.ErrorInternalServer(c, err)
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.SuccessResponse[dto.BorrowingResp]{
+		Success: true,
+		Message: "Book checked out successfully",
+		Data:    data,
+	})
+}
+
+// checkin godoc
+//
+//	@Summary Check a book in
+//	@Description Return, as of now, the loan of the copy with the barcode or of the book by the person. Late returns are charged to the person.
+//	@Accept json
+//	@Produce json
+//	@Security BearerAuth
+//	@Param detail body dto.CheckinReq true "Checkin's detail"
+//	@Success 200 {object} dto.SuccessResponse[dto.BorrowingResp]
+//	@Failure 401 {object} dto.ErrorResponse
+//	@Failure 403 {object} dto.ErrorResponse
+//	@Failure 404 {object} dto.ErrorResponse
+//	@Failure 409 {object} dto.ErrorResponse
+//	@Failure 422 {object} dto.ErrorResponse
+//	@Failure 500 {object} dto.ErrorResponse
+//	@Router /circulation/checkin [post]
+func (h *CirculationHandler) checkin(c *gin.Context) {
+	var req dto.CheckinReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(h.hr.BindingError(err))
+		return
+	}
+
+	data, err := h.service.Checkin(c.Request.Context(), &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, exception.ErrDataNotFound):
+			c.JSON(http.StatusNotFound, h.hr.ErrorResponse(err.Error()))
+		case errors.Is(err, exception.ErrBorrowingReturned):
+			c.JSON(http.StatusConflict, h.hr.ErrorResponse(err.Error()))
+		default:
+			h.hr.ErrorInternalServer(c, err)
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse[dto.BorrowingResp]{
+		Success: true,
+		Message: "Book checked in successfully",
+		Data:    data,
+	})
+}
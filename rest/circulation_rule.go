@@ -0,0 +1,221 @@
+package rest
+
+import (
+	"base-gin/domain"
+	"base-gin/domain/dto"
+	"base-gin/exception"
+	"base-gin/server"
+	"base-gin/service"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CirculationRuleHandler struct {
+	hr      *server.Handler
+	service *service.CirculationRuleService
+}
+
+func NewCirculationRuleHandler(
+	handler *server.Handler,
+	ruleService *service.CirculationRuleService,
+) *CirculationRuleHandler {
+	return &CirculationRuleHandler{hr: handler, service: ruleService}
+}
+
+func (h *CirculationRuleHandler) Route(app *gin.Engine) {
+	grp := app.Group(server.RootAdmin, h.hr.AuthAccess(), h.hr.RequireRole(domain.RoleAdmin))
+	grp.POST(server.PathAdminRules, h.create)
+	grp.GET(server.PathAdminRules, h.getList)
+	grp.GET(server.PathAdminRule, h.getByID)
+	grp.PUT(server.PathAdminRule, h.update)
+	grp.DELETE(server.PathAdminRule, h.delete)
+}
+
+func (h *CirculationRuleHandler) parseID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, h.hr.ErrorResponse("ID tidak valid"))
+		return 0, false
+	}
+
+	return uint(id), true
+}
+
+// create godoc
+//
+//	@Summary Add a circulation rule
+//	@Description Set the loan limit, loan period & renewals for a patron category and item type, either of which may be "*" to match any.
+//	@Accept json
+//	@Produce json
+//	@Security BearerAuth
+//	@Param detail body dto.CirculationRuleReq true "Rule's detail"
+//	@Success 201 {object} dto.SuccessResponse[dto.CirculationRuleResp]
+//	@Failure 401 {object} dto.ErrorResponse
+//	@Failure 403 {object} dto.ErrorResponse
+//	@Failure 409 {object} dto.ErrorResponse
+//	@Failure 422 {object} dto.ErrorResponse
+//	@Failure 500 {object} dto.ErrorResponse
+//	@Router /admin/circulation-rules [post]
+func (h *CirculationRuleHandler) create(c *gin.Context) {
+	var req dto.CirculationRuleReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(h.hr.BindingError(err))
+		return
+	}
+
+	data, err := h.service.Create(c.Request.Context(), &req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.SuccessResponse[dto.CirculationRuleResp]{
+		Success: true,
+		Message: "Aturan peminjaman berhasil ditambahkan",
+		Data:    data,
+	})
+}
+
+// getList godoc
+//
+//	@Summary Get the circulation rules
+//	@Description Get every circulation rule. The most specific rule matching a checkout applies; without any the configured defaults do.
+//	@Produce json
+//	@Security BearerAuth
+//	@Success 200 {object} dto.SuccessResponse[[]dto.CirculationRuleResp]
+//	@Failure 401 {object} dto.ErrorResponse
+//	@Failure 403 {object} dto.ErrorResponse
+//	@Failure 500 {object} dto.ErrorResponse
+//	@Router /admin/circulation-rules [get]
+func (h *CirculationRuleHandler) getList(c *gin.Context) {
+	data, err := h.service.GetList(c.Request.Context())
+	if err != nil {
+		h.hr.ErrorInternalServer(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse[[]dto.CirculationRuleResp]{
+		Success: true,
+		Message: "Daftar aturan peminjaman",
+		Data:    data,
+	})
+}
+
+// getByID godoc
+//
+//	@Summary Get a circulation rule
+//	@Produce json
+//	@Security BearerAuth
+//	@Param id path int true "Rule ID"
+//	@Success 200 {object} dto.SuccessResponse[dto.CirculationRuleResp]
+//	@Failure 400 {object} dto.ErrorResponse
+//	@Failure 401 {object} dto.ErrorResponse
+//	@Failure 403 {object} dto.ErrorResponse
+//	@Failure 404 {object} dto.ErrorResponse
+//	@Failure 500 {object} dto.ErrorResponse
+//	@Router /admin/circulation-rules/{id} [get]
+func (h *CirculationRuleHandler) getByID(c *gin.Context) {
+	id, ok := h.parseID(c)
+	if !ok {
+		return
+	}
+
+	data, err := h.service.GetByID(c.Request.Context(), id)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse[dto.CirculationRuleResp]{
+		Success: true,
+		Message: "Detail aturan peminjaman",
+		Data:    data,
+	})
+}
+
+// update godoc
+//
+//	@Summary Update a circulation rule
+//	@Accept json
+//	@Produce json
+//	@Security BearerAuth
+//	@Param id path int true "Rule ID"
+//	@Param detail body dto.CirculationRuleReq true "Rule's detail"
+//	@Success 200 {object} dto.SuccessResponse[dto.CirculationRuleResp]
+//	@Failure 400 {object} dto.ErrorResponse
+//	@Failure 401 {object} dto.ErrorResponse
+//	@Failure 403 {object} dto.ErrorResponse
+//	@Failure 404 {object} dto.ErrorResponse
+//	@Failure 409 {object} dto.ErrorResponse
+//	@Failure 422 {object} dto.ErrorResponse
+//	@Failure 500 {object} dto.ErrorResponse
+//	@Router /admin/circulation-rules/{id} [put]
+func (h *CirculationRuleHandler) update(c *gin.Context) {
+	id, ok := h.parseID(c)
+	if !ok {
+		return
+	}
+
+	var req dto.CirculationRuleReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(h.hr.BindingError(err))
+		return
+	}
+	req.ID = id
+
+	data, err := h.service.Update(c.Request.Context(), &req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse[dto.CirculationRuleResp]{
+		Success: true,
+		Message: "Aturan peminjaman berhasil diubah",
+		Data:    data,
+	})
+}
+
+// delete godoc
+//
+//	@Summary Delete a circulation rule
+//	@Produce json
+//	@Security BearerAuth
+//	@Param id path int true "Rule ID"
+//	@Success 200 {object} dto.SuccessResponse[any]
+//	@Failure 400 {object} dto.ErrorResponse
+//	@Failure 401 {object} dto.ErrorResponse
+//	@Failure 403 {object} dto.ErrorResponse
+//	@Failure 404 {object} dto.ErrorResponse
+//	@Failure 500 {object} dto.ErrorResponse
+//	@Router /admin/circulation-rules/{id} [delete]
+func (h *CirculationRuleHandler) delete(c *gin.Context) {
+	id, ok := h.parseID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.Delete(c.Request.Context(), id); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse[any]{
+		Success: true,
+		Message: "Data berhasil dihapus",
+	})
+}
+
+func (h *CirculationRuleHandler) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, exception.ErrDataNotFound):
+		c.JSON(http.StatusNotFound, h.hr.ErrorResponse(err.Error()))
+	case errors.Is(err, exception.ErrRuleConflict):
+		c.JSON(http.StatusConflict, h.hr.ErrorResponse(err.Error()))
+	default:
+		h.hr.ErrorInternalServer(c, err)
+	}
+}
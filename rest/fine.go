@@ -0,0 +1,201 @@
+package rest
+
+import (
+	"base-gin/domain"
+	"base-gin/domain/dto"
+	"base-gin/exception"
+	"base-gin/server"
+	"base-gin/service"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type FineHandler struct {
+	hr      *server.Handler
+	service *service.FineService
+}
+
+func NewFineHandler(handler *server.Handler, fineService *service.FineService) *FineHandler {
+	return &FineHandler{hr: handler, service: fineService}
+}
+
+func (h *FineHandler) Route(app *gin.Engine) {
+	staffOnly := h.hr.RequireRole(domain.RoleAdmin, domain.RoleLibrarian)
+
+	grp := app.Group(server.RootFine, h.hr.AuthAccess())
+	grp.GET("", staffOnly, h.getOutstanding)
+	grp.GET(server.PathFinePerson, h.getLedger)
+	grp.POST(server.PathFinePayments, staffOnly, h.recordPayment)
+	grp.POST(server.PathFineWaivers, staffOnly, h.waive)
+}
+
+func (h *FineHandler) parsePersonID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, h.hr.ErrorResponse("ID tidak valid"))
+		return 0, false
+	}
+
+	return uint(id), true
+}
+
+// getOutstanding godoc
+//
+//	@Summary Get outstanding fines
+//	@Description Get the persons having unsettled fines along with their balance, the largest first.
+//	@Produce json
+//	@Security BearerAuth
+//	@Success 200 {object} dto.SuccessResponse[[]dto.FineBalanceResp]
+//	@Failure 401 {object} dto.ErrorResponse
+//	@Failure 403 {object} dto.ErrorResponse
+//	@Failure 500 {object} dto.ErrorResponse
+//	@Router /fines [get]
+func (h *FineHandler) getOutstanding(c *gin.Context) {
+	data, err := h.service.GetOutstanding(c.Request.Context())
+	if err != nil {
+		h.hr.ErrorInternalServer(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse[[]dto.FineBalanceResp]{
+		Success: true,
+		Message: "Daftar denda belum lunas",
+		Data:    data,
+	})
+}
+
+// getLedger godoc
+//
+//	@Summary Get a person's fines
+//	@Description Get the fines balance & ledger of a person. Members only get their own.
+//	@Produce json
+//	@Security BearerAuth
+//	@Param id path int true "Person ID"
+//	@Success 200 {object} dto.SuccessResponse[dto.FineLedgerResp]
+//	@Failure 400 {object} dto.ErrorResponse
+//	@Failure 401 {object} dto.ErrorResponse
+//	@Failure 403 {object} dto.ErrorResponse
+//	@Failure 404 {object} dto.ErrorResponse
+//	@Failure 500 {object} dto.ErrorResponse
+//	@Router /fines/persons/{id} [get]
+func (h *FineHandler) getLedger(c *gin.Context) {
+	personID, ok := h.parsePersonID(c)
+	if !ok {
+		return
+	}
+
+	data, err := h.service.GetLedger(c.Request.Context(), personID, memberAccountID(c))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse[dto.FineLedgerResp]{
+		Success: true,
+		Message: "Rincian denda",
+		Data:    data,
+	})
+}
+
+// recordPayment godoc
+//
+//	@Summary Record a fine payment
+//	@Description Record a payment of a person's fines. The amount may not exceed the balance.
+//	@Accept json
+//	@Produce json
+//	@Security BearerAuth
+//	@Param id path int true "Person ID"
+//	@Param detail body dto.FinePaymentReq true "Payment's detail"
+//	@Success 201 {object} dto.SuccessResponse[any]
+//	@Failure 400 {object} dto.ErrorResponse
+//	@Failure 401 {object} dto.ErrorResponse
+//	@Failure 403 {object} dto.ErrorResponse
+//	@Failure 404 {object} dto.ErrorResponse
+//	@Failure 409 {object} dto.ErrorResponse
+//	@Failure 422 {object} dto.ErrorResponse
+//	@Failure 500 {object} dto.ErrorResponse
+//	@Router /fines/persons/{id}/payments [post]
+func (h *FineHandler) recordPayment(c *gin.Context) {
+	personID, ok := h.parsePersonID(c)
+	if !ok {
+		return
+	}
+
+	var req dto.FinePaymentReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(h.hr.BindingError(err))
+		return
+	}
+	req.PersonID = personID
+	req.CreatedByID = c.GetUint(server.ParamTokenUserID)
+
+	if err := h.service.RecordPayment(c.Request.Context(), &req); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.SuccessResponse[any]{
+		Success: true,
+		Message: "Pembayaran denda berhasil dicatat",
+	})
+}
+
+// waive godoc
+//
+//	@Summary Waive fines
+//	@Description Waive part of a person's fines, stating the reason. The amount may not exceed the balance.
+//	@Accept json
+//	@Produce json
+//	@Security BearerAuth
+//	@Param id path int true "Person ID"
+//	@Param detail body dto.FineWaiverReq true "Waiver's detail"
+//	@Success 201 {object} dto.SuccessResponse[any]
+//	@Failure 400 {object} dto.ErrorResponse
+//	@Failure 401 {object} dto.ErrorResponse
+//	@Failure 403 {object} dto.ErrorResponse
+//	@Failure 404 {object} dto.ErrorResponse
+//	@Failure 409 {object} dto.ErrorResponse
+//	@Failure 422 {object} dto.ErrorResponse
+//	@Failure 500 {object} dto.ErrorResponse
+//	@Router /fines/persons/{id}/waivers [post]
+func (h *FineHandler) waive(c *gin.Context) {
+	personID, ok := h.parsePersonID(c)
+	if !ok {
+		return
+	}
+
+	var req dto.FineWaiverReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(h.hr.BindingError(err))
+		return
+	}
+	req.PersonID = personID
+	req.CreatedByID = c.GetUint(server.ParamTokenUserID)
+
+	if err := h.service.Waive(c.Request.Context(), &req); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.SuccessResponse[any]{
+		Success: true,
+		Message: "Denda berhasil dihapuskan",
+	})
+}
+
+func (h *FineHandler) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, exception.ErrUserNotFound),
+		errors.Is(err, exception.ErrDataNotFound):
+		c.JSON(http.StatusNotFound, h.hr.ErrorResponse(err.Error()))
+	case errors.Is(err, exception.ErrForbidden):
+		c.JSON(http.StatusForbidden, h.hr.ErrorResponse(err.Error()))
+	case errors.Is(err, exception.ErrFineExceedsBalance):
+		c.JSON(http.StatusConflict, h.hr.ErrorResponse(err.Error()))
+	default:
+		h.hr.ErrorInternalServer(c, err)
+	}
+}
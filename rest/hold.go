@@ -0,0 +1,157 @@
+package rest
+
+import (
+	"base-gin/domain/dto"
+	"base-gin/exception"
+	"base-gin/server"
+	"base-gin/service"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type HoldHandler struct {
+	hr      *server.Handler
+	service *service.HoldService
+}
+
+func NewHoldHandler(handler *server.Handler, holdService *service.HoldService) *HoldHandler {
+	return &HoldHandler{hr: handler, service: holdService}
+}
+
+func (h *HoldHandler) Route(app *gin.Engine) {
+	grp := app.Group(server.RootHold, h.hr.AuthAccess())
+	grp.POST("", h.place)
+	grp.GET("", h.getList)
+	grp.DELETE("/:id", h.cancel)
+}
+
+// place godoc
+//
+//	@Summary Place a hold
+//	@Description Queue for a book that is out on loan. Members queue themselves; staff queue the given person.
+//	@Accept json
+//	@Produce json
+//	@Security BearerAuth
+//	@Param detail body dto.HoldReq true "Hold's detail"
+//	@Success 201 {object} dto.SuccessResponse[dto.HoldResp]
+//	@Failure 401 {object} dto.ErrorResponse
+//	@Failure 403 {object} dto.ErrorResponse
+//	@Failure 404 {object} dto.ErrorResponse
+//	@Failure 409 {object} dto.ErrorResponse
+//	@Failure 422 {object} dto.ErrorResponse
+//	@Failure 500 {object} dto.ErrorResponse
+//	@Router /holds [post]
+func (h *HoldHandler) place(c *gin.Context) {
+	var req dto.HoldReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(h.hr.BindingError(err))
+		return
+	}
+	req.AccountID = c.GetUint(server.ParamTokenUserID)
+
+	data, err := h.service.Place(c.Request.Context(), &req, memberAccountID(c))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.SuccessResponse[dto.HoldResp]{
+		Success: true,
+		Message: "Berhasil masuk antrean",
+		Data:    data,
+	})
+}
+
+// getList godoc
+//
+//	@Summary Get a list of holds
+//	@Description Get the holds of a book and/or person in queue order, only active ones unless a status is given. Members only get their own.
+//	@Produce json
+//	@Security BearerAuth
+//	@Param book_id query int false "Book ID"
+//	@Param person_id query int false "Person ID"
+//	@Param status query string false "Status" Enums(waiting, ready, fulfilled, cancelled, expired)
+//	@Param q query string false "Book title"
+//	@Param s query int false "Data offset"
+//	@Param l query int false "Data limit"
+//	@Param sort query string false "Sort by id, status, created_at, comma separated, descending when prefixed with -"
+//	@Param cursor query string false "Next or previous cursor of a page, in place of the offset"
+//	@Success 200 {object} dto.SuccessResponse[[]dto.HoldResp]
+//	@Failure 401 {object} dto.ErrorResponse
+//	@Failure 422 {object} dto.ErrorResponse
+//	@Failure 500 {object} dto.ErrorResponse
+//	@Router /holds [get]
+func (h *HoldHandler) getList(c *gin.Context) {
+	var req dto.HoldFilter
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(h.hr.BindingError(err))
+		return
+	}
+
+	data, meta, err := h.service.GetList(c.Request.Context(), &req, memberAccountID(c))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse[[]dto.HoldResp]{
+		Success: true,
+		Message: "Daftar antrean",
+		Data:    data,
+		Meta:    &meta,
+	})
+}
+
+// cancel godoc
+//
+//	@Summary Cancel a hold
+//	@Description Leave the queue of a book. A copy set aside for the hold goes to the next in the queue. Members may only cancel their own.
+//	@Produce json
+//	@Security BearerAuth
+//	@Param id path int true "Hold ID"
+//	@Success 200 {object} dto.SuccessResponse[any]
+//	@Failure 400 {object} dto.ErrorResponse
+//	@Failure 401 {object} dto.ErrorResponse
+//	@Failure 404 {object} dto.ErrorResponse
+//	@Failure 409 {object} dto.ErrorResponse
+//	@Failure 500 {object} dto.ErrorResponse
+//	@Router /holds/{id} [delete]
+func (h *HoldHandler) cancel(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, h.hr.ErrorResponse("ID tidak valid"))
+		return
+	}
+
+	if err := h.service.Cancel(c.Request.Context(), uint(id), memberAccountID(c)); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse[any]{
+		Success: true,
+		Message: "Antrean berhasil dibatalkan",
+	})
+}
+
+func (h *HoldHandler) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, exception.ErrDataNotFound),
+		errors.Is(err, exception.ErrUserNotFound):
+		c.JSON(http.StatusNotFound, h.hr.ErrorResponse(err.Error()))
+	case errors.Is(err, exception.ErrForbidden):
+		c.JSON(http.StatusForbidden, h.hr.ErrorResponse(err.Error()))
+	case errors.Is(err, exception.ErrBookAvailable),
+		errors.Is(err, exception.ErrHoldConflict),
+		errors.Is(err, exception.ErrHoldInactive):
+		c.JSON(http.StatusConflict, h.hr.ErrorResponse(err.Error()))
+	case errors.Is(err, exception.ErrSortInvalid),
+		errors.Is(err, exception.ErrCursorInvalid):
+		c.JSON(http.StatusUnprocessableEntity, h.hr.ErrorResponse(err.Error()))
+	default:
+		h.hr.ErrorInternalServer(c, err)
+	}
+}
@@ -0,0 +1,112 @@
+package rest
+
+import (
+	"base-gin/constant"
+	"base-gin/domain"
+	"base-gin/domain/dto"
+	"base-gin/exception"
+	"base-gin/server"
+	"base-gin/service"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ImportHandler struct {
+	hr      *server.Handler
+	service *service.ImportService
+}
+
+func NewImportHandler(handler *server.Handler, importService *service.ImportService) *ImportHandler {
+	return &ImportHandler{hr: handler, service: importService}
+}
+
+func (h *ImportHandler) Route(app *gin.Engine) {
+	staffOnly := h.hr.RequireRole(domain.RoleAdmin, domain.RoleLibrarian)
+
+	grp := app.Group(server.RootImport, h.hr.AuthAccess(), staffOnly)
+	grp.POST(server.PathImportCatalog, h.hr.MaxPostSizeMb(constant.ImportMaxSizeMb), h.importCatalog)
+}
+
+// importCatalog godoc
+//
+//	@Summary Import the catalog from CSV
+//	@Description Create a book for each row of a CSV sent as the body (text/csv) or as the "file" of a form. The header names the columns: title, subtitle, isbn, item_type, publisher, publisher_city & authors, the latter separated by semicolons with the role in parentheses when not the author, e.g. "Pramoedya Ananta Toer; Max Lane (translator)". Authors & publishers are created when no one has their name, a new publisher needing its city. Invalid rows are skipped and reported by line, the valid ones saved together. A dry run saves nothing.
+//	@Accept text/csv,multipart/form-data
+//	@Produce json
+//	@Security BearerAuth
+//	@Param dry_run query bool false "Only validate"
+//	@Param file formData file false "Catalog CSV"
+//	@Success 200 {object} dto.SuccessResponse[dto.CatalogImportResp]
+//	@Failure 401 {object} dto.ErrorResponse
+//	@Failure 403 {object} dto.ErrorResponse
+//	@Failure 413 {object} dto.ErrorResponse
+//	@Failure 422 {object} dto.ErrorResponse
+//	@Failure 500 {object} dto.ErrorResponse
+//	@Router /import/catalog [post]
+func (h *ImportHandler) importCatalog(c *gin.Context) {
+	var req dto.CatalogImportQuery
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(h.hr.BindingError(err))
+		return
+	}
+
+	body, err := csvBody(c)
+	if err != nil {
+		c.JSON(http.StatusUnprocessableEntity, h.hr.ErrorResponse(exception.ErrImportInvalid.Error()))
+		return
+	}
+	defer body.Close()
+
+	data, err := h.service.ImportCatalog(c.Request.Context(), body, req.DryRun)
+	if err != nil {
+		switch {
+		case errors.Is(err, exception.ErrImportHeader),
+			errors.Is(err, exception.ErrImportInvalid):
+			c.JSON(http.StatusUnprocessableEntity, h.hr.ErrorResponse(err.Error()))
+		default:
+			h.hr.ErrorInternalServer(c, err)
+		}
+		return
+	}
+
+	for i := range data.Errors {
+		row := &data.Errors[i]
+		if messages := h.hr.ValidationMessages(row.Err); messages != nil {
+			for j := range messages {
+				if row.Field != "" {
+					messages[j].Field = row.Field + "." + messages[j].Field
+				}
+			}
+			row.Errors = messages
+		} else {
+			row.Errors = []server.BindingErrorMessage{{Field: row.Field, Message: row.Err.Error()}}
+		}
+	}
+
+	message := "Katalog berhasil diimpor"
+	if req.DryRun {
+		message = "Hasil validasi katalog"
+	}
+	c.JSON(http.StatusOK, dto.SuccessResponse[dto.CatalogImportResp]{
+		Success: true,
+		Message: message,
+		Data:    data,
+	})
+}
+
+// csvBody returns the uploaded file of a form, or else the request body.
+func csvBody(c *gin.Context) (io.ReadCloser, error) {
+	if !strings.HasPrefix(c.ContentType(), "multipart/") {
+		return c.Request.Body, nil
+	}
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		return nil, err
+	}
+	return file.Open()
+}
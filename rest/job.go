@@ -0,0 +1,128 @@
+package rest
+
+import (
+	"base-gin/domain"
+	"base-gin/domain/dto"
+	"base-gin/exception"
+	"base-gin/server"
+	"base-gin/service"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type JobHandler struct {
+	hr      *server.Handler
+	service *service.JobService
+}
+
+func NewJobHandler(handler *server.Handler, jobService *service.JobService) *JobHandler {
+	return &JobHandler{hr: handler, service: jobService}
+}
+
+func (h *JobHandler) Route(app *gin.Engine) {
+	grp := app.Group(server.RootAdmin, h.hr.AuthAccess(), h.hr.RequireRole(domain.RoleAdmin))
+	grp.GET(server.PathAdminJobs, h.getList)
+	grp.GET(server.PathAdminJobRuns, h.getRuns)
+	grp.POST(server.PathAdminJobRun, h.trigger)
+}
+
+// getList godoc
+//
+//	@Summary Get the scheduled jobs
+//	@Description Get every job along with its cron spec, next scheduled run and last run.
+//	@Produce json
+//	@Security BearerAuth
+//	@Success 200 {object} dto.SuccessResponse[[]dto.JobResp]
+//	@Failure 401 {object} dto.ErrorResponse
+//	@Failure 403 {object} dto.ErrorResponse
+//	@Failure 500 {object} dto.ErrorResponse
+//	@Router /admin/jobs [get]
+func (h *JobHandler) getList(c *gin.Context) {
+	data, err := h.service.GetList(c.Request.Context())
+	if err != nil {
+		h.hr.ErrorInternalServer(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse[[]dto.JobResp]{
+		Success: true,
+		Message: "Daftar job",
+		Data:    data,
+	})
+}
+
+// getRuns godoc
+//
+//	@Summary Get the job runs
+//	@Description Get the runs of the jobs, the most recent first, with their status & duration.
+//	@Produce json
+//	@Security BearerAuth
+//	@Param job query string false "Job name"
+//	@Param status query string false "Run status" Enums(running, succeeded, failed)
+//	@Param s query int false "Data offset"
+//	@Param l query int false "Data limit"
+//	@Success 200 {object} dto.SuccessResponse[[]dto.JobRunResp]
+//	@Failure 401 {object} dto.ErrorResponse
+//	@Failure 403 {object} dto.ErrorResponse
+//	@Failure 422 {object} dto.ErrorResponse
+//	@Failure 500 {object} dto.ErrorResponse
+//	@Router /admin/jobs/runs [get]
+func (h *JobHandler) getRuns(c *gin.Context) {
+	var req dto.JobRunFilter
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(h.hr.BindingError(err))
+		return
+	}
+
+	data, err := h.service.GetRuns(c.Request.Context(), &req)
+	if err != nil {
+		h.hr.ErrorInternalServer(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse[[]dto.JobRunResp]{
+		Success: true,
+		Message: "Riwayat job",
+		Data:    data,
+	})
+}
+
+// trigger godoc
+//
+//	@Summary Run a job now
+//	@Description Start a run of the job right away. The run goes on in the background; poll the job runs for its outcome.
+//	@Produce json
+//	@Security BearerAuth
+//	@Param name path string true "Job name"
+//	@Success 202 {object} dto.SuccessResponse[dto.JobRunResp]
+//	@Failure 401 {object} dto.ErrorResponse
+//	@Failure 403 {object} dto.ErrorResponse
+//	@Failure 404 {object} dto.ErrorResponse
+//	@Failure 409 {object} dto.ErrorResponse
+//	@Failure 500 {object} dto.ErrorResponse
+//	@Failure 503 {object} dto.ErrorResponse
+//	@Router /admin/jobs/{name}/run [post]
+func (h *JobHandler) trigger(c *gin.Context) {
+	data, err := h.service.Trigger(c.Request.Context(), c.Param("name"))
+	if err != nil {
+		switch {
+		case errors.Is(err, exception.ErrJobNotFound):
+			c.JSON(http.StatusNotFound, h.hr.ErrorResponse(err.Error()))
+		case errors.Is(err, exception.ErrJobRunning):
+			c.JSON(http.StatusConflict, h.hr.ErrorResponse(err.Error()))
+		case errors.Is(err, exception.ErrSchedulerStopped):
+			c.JSON(http.StatusServiceUnavailable, h.hr.ErrorResponse(err.Error()))
+		default:
+			h.hr.ErrorInternalServer(c, err)
+		}
+		return
+	}
+
+	c.JSON(http.StatusAccepted, dto.SuccessResponse[dto.JobRunResp]{
+		Success: true,
+		Message: "Job dijalankan",
+		Data:    data,
+	})
+}
@@ -0,0 +1,189 @@
+package rest
+
+import (
+	"base-gin/domain"
+	"base-gin/domain/dto"
+	"base-gin/exception"
+	"base-gin/server"
+	"base-gin/service"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type NotificationHandler struct {
+	hr      *server.Handler
+	service *service.NotificationService
+}
+
+func NewNotificationHandler(handler *server.Handler, notificationService *service.NotificationService) *NotificationHandler {
+	return &NotificationHandler{hr: handler, service: notificationService}
+}
+
+func (h *NotificationHandler) Route(app *gin.Engine) {
+	staffOnly := h.hr.RequireRole(domain.RoleAdmin, domain.RoleLibrarian)
+
+	grp := app.Group(server.RootNotification, h.hr.AuthAccess())
+	grp.GET(server.PathNotifyPreference, h.getPreference)
+	grp.PUT(server.PathNotifyPreference, h.updatePreference)
+	grp.GET(server.PathNotifyDeliveries, h.getDeliveries)
+	grp.POST(server.PathNotifyReminders, staffOnly, h.sendReminders)
+}
+
+func (h *NotificationHandler) parsePersonID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, h.hr.ErrorResponse("ID tidak valid"))
+		return 0, false
+	}
+
+	return uint(id), true
+}
+
+// getPreference godoc
+//
+//	@Summary Get a person's notification preference
+//	@Description Get how a person is reached and which channels they opted out of. Members only get their own.
+//	@Produce json
+//	@Security BearerAuth
+//	@Param id path int true "Person ID"
+//	@Success 200 {object} dto.SuccessResponse[dto.NotificationPreferenceResp]
+//	@Failure 400 {object} dto.ErrorResponse
+//	@Failure 401 {object} dto.ErrorResponse
+//	@Failure 403 {object} dto.ErrorResponse
+//	@Failure 404 {object} dto.ErrorResponse
+//	@Failure 500 {object} dto.ErrorResponse
+//	@Router /notifications/persons/{id}/preference [get]
+func (h *NotificationHandler) getPreference(c *gin.Context) {
+	personID, ok := h.parsePersonID(c)
+	if !ok {
+		return
+	}
+
+	data, err := h.service.GetPreference(c.Request.Context(), personID, memberAccountID(c))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse[dto.NotificationPreferenceResp]{
+		Success: true,
+		Message: "Preferensi notifikasi",
+		Data:    data,
+	})
+}
+
+// updatePreference godoc
+//
+//	@Summary Update a person's notification preference
+//	@Description Set the email, phone & language notifications are sent with and the channels opted out of. Members may only update their own.
+//	@Accept json
+//	@Produce json
+//	@Security BearerAuth
+//	@Param id path int true "Person ID"
+//	@Param detail body dto.NotificationPreferenceReq true "Preference's detail"
+//	@Success 200 {object} dto.SuccessResponse[dto.NotificationPreferenceResp]
+//	@Failure 400 {object} dto.ErrorResponse
+//	@Failure 401 {object} dto.ErrorResponse
+//	@Failure 403 {object} dto.ErrorResponse
+//	@Failure 404 {object} dto.ErrorResponse
+//	@Failure 422 {object} dto.ErrorResponse
+//	@Failure 500 {object} dto.ErrorResponse
+//	@Router /notifications/persons/{id}/preference [put]
+func (h *NotificationHandler) updatePreference(c *gin.Context) {
+	personID, ok := h.parsePersonID(c)
+	if !ok {
+		return
+	}
+
+	var req dto.NotificationPreferenceReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(h.hr.BindingError(err))
+		return
+	}
+	req.PersonID = personID
+
+	data, err := h.service.UpdatePreference(c.Request.Context(), &req, memberAccountID(c))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse[dto.NotificationPreferenceResp]{
+		Success: true,
+		Message: "Preferensi notifikasi berhasil disimpan",
+		Data:    data,
+	})
+}
+
+// getDeliveries godoc
+//
+//	@Summary Get a person's notifications
+//	@Description Get the log of notifications sent, or tried to be sent, to a person, the most recent first. Members only get their own.
+//	@Produce json
+//	@Security BearerAuth
+//	@Param id path int true "Person ID"
+//	@Success 200 {object} dto.SuccessResponse[[]dto.NotificationDeliveryResp]
+//	@Failure 400 {object} dto.ErrorResponse
+//	@Failure 401 {object} dto.ErrorResponse
+//	@Failure 403 {object} dto.ErrorResponse
+//	@Failure 404 {object} dto.ErrorResponse
+//	@Failure 500 {object} dto.ErrorResponse
+//	@Router /notifications/persons/{id}/deliveries [get]
+func (h *NotificationHandler) getDeliveries(c *gin.Context) {
+	personID, ok := h.parsePersonID(c)
+	if !ok {
+		return
+	}
+
+	data, err := h.service.GetDeliveries(c.Request.Context(), personID, memberAccountID(c))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse[[]dto.NotificationDeliveryResp]{
+		Success: true,
+		Message: "Riwayat notifikasi",
+		Data:    data,
+	})
+}
+
+// sendReminders godoc
+//
+//	@Summary Send loan reminders
+//	@Description Send the due-soon & long-overdue reminders not sent yet right away.
+//	@Produce json
+//	@Security BearerAuth
+//	@Success 200 {object} dto.SuccessResponse[dto.NotificationRunResp]
+//	@Failure 401 {object} dto.ErrorResponse
+//	@Failure 403 {object} dto.ErrorResponse
+//	@Failure 500 {object} dto.ErrorResponse
+//	@Router /notifications/reminders [post]
+func (h *NotificationHandler) sendReminders(c *gin.Context) {
+	data, err := h.service.SendReminders(c.Request.Context(), time.Now())
+	if err != nil {
+		h.hr.ErrorInternalServer(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse[dto.NotificationRunResp]{
+		Success: true,
+		Message: "Pengingat berhasil dikirim",
+		Data:    data,
+	})
+}
+
+func (h *NotificationHandler) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, exception.ErrUserNotFound):
+		c.JSON(http.StatusNotFound, h.hr.ErrorResponse(err.Error()))
+	case errors.Is(err, exception.ErrForbidden):
+		c.JSON(http.StatusForbidden, h.hr.ErrorResponse(err.Error()))
+	default:
+		h.hr.ErrorInternalServer(c, err)
+	}
+}
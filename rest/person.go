@@ -37,26 +37,29 @@ func (h *PersonHandler) Route(app *gin.Engine) {
 //	@Description Get a list of person.
 //	@Produce json
 //	@Param q query string false "Person's name"
+//	@Param gender query string false "Gender" Enums(m, f)
+//	@Param category query string false "Category"
 //	@Param s query int false "Data offset"
 //	@Param l query int false "Data limit"
+//	@Param sort query string false "Sort by id, fullname, category, created_at, comma separated, descending when prefixed with -"
+//	@Param cursor query string false "Next or previous cursor of a page, in place of the offset"
 //	@Success 200 {object} dto.SuccessResponse[[]dto.PersonDetailResp]
 //	@Failure 422 {object} dto.ErrorResponse
-//	@Failure 404 {object} dto.ErrorResponse
-//	@Failure 422 {object} dto.ErrorResponse
 //	@Failure 500 {object} dto.ErrorResponse
 //	@Router /persons [get]
 func (h *PersonHandler) getList(c *gin.Context) {
-	var req dto.Filter
+	var req dto.PersonFilter
 	if err := c.ShouldBindQuery(&req); err != nil {
 		c.JSON(h.hr.BindingError(err))
 		return
 	}
 
-	data, err := h.service.GetList(&req)
+	data, meta, err := h.service.GetList(c.Request.Context(), &req)
 	if err != nil {
 		switch {
-		case errors.Is(err, exception.ErrUserNotFound):
-			c.JSON(http.StatusNotFound, h.hr.ErrorResponse(exception.ErrDataNotFound.Error()))
+		case errors.Is(err, exception.ErrSortInvalid),
+			errors.Is(err, exception.ErrCursorInvalid):
+			c.JSON(http.StatusUnprocessableEntity, h.hr.ErrorResponse(err.Error()))
 		default:
 			h.hr.ErrorInternalServer(c, err)
 		}
@@ -68,6 +71,7 @@ func (h *PersonHandler) getList(c *gin.Context) {
 		Success: true,
 		Message: "Daftar anggota",
 		Data:    data,
+		Meta:    &meta,
 	})
 }
 
@@ -91,7 +95,7 @@ func (h *PersonHandler) getByID(c *gin.Context) {
 		return
 	}
 
-	data, err := h.service.GetByID(uint(id))
+	data, err := h.service.GetByID(c.Request.Context(), uint(id))
 	if err != nil {
 		switch {
 		case errors.Is(err, exception.ErrUserNotFound):
@@ -113,7 +117,7 @@ func (h *PersonHandler) getByID(c *gin.Context) {
 // update godoc
 //
 //	@Summary Update a person's detail
-//	@Description Update a person's detail.
+//	@Description Update a person's detail. Members may only update their own.
 //	@Accept json
 //	@Produce json
 //	@Security BearerAuth
@@ -142,9 +146,11 @@ func (h *PersonHandler) update(c *gin.Context) {
 	}
 	req.ID = uint(id)
 
-	err = h.service.Update(&req)
+	err = h.service.Update(c.Request.Context(), &req, memberAccountID(c))
 	if err != nil {
 		switch {
+		case errors.Is(err, exception.ErrForbidden):
+			c.JSON(http.StatusForbidden, h.hr.ErrorResponse(err.Error()))
 		case errors.Is(err, exception.ErrDateParsing):
 			c.JSON(http.StatusBadRequest, h.hr.ErrorResponse(err.Error()))
 		case errors.Is(err, exception.ErrUserNotFound):
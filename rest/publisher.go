@@ -1,6 +1,7 @@
 package rest
 
 import (
+	"base-gin/domain"
 	"base-gin/domain/dto"
 	"base-gin/exception"
 	"base-gin/server"
@@ -22,12 +23,14 @@ func NewPublisherHandler(handler *server.Handler, publisherService *service.Publ
 }
 
 func (h *PublisherHandler) Route(app *gin.Engine) {
+	staffOnly := h.hr.RequireRole(domain.RoleAdmin, domain.RoleLibrarian)
+
 	grp := app.Group(server.RootPublisher)
-	grp.POST("", h.hr.AuthAccess(), h.create)
+	grp.POST("", h.hr.AuthAccess(), staffOnly, h.create)
 	grp.GET("", h.getList)
 	grp.GET("/:id", h.getByID)
-	grp.PUT("/:id", h.hr.AuthAccess(), h.update)
-	grp.DELETE("/:id", h.hr.AuthAccess(), h.delete)
+	grp.PUT("/:id", h.hr.AuthAccess(), staffOnly, h.update)
+	grp.DELETE("/:id", h.hr.AuthAccess(), staffOnly, h.delete)
 }
 
 // create godoc
@@ -51,7 +54,7 @@ func (h *PublisherHandler) create(c *gin.Context) {
 		return
 	}
 
-	err := h.service.Create(&req)
+	err := h.service.Create(c.Request.Context(), &req)
 	if err != nil {
 		h.hr.ErrorInternalServer(c, err)
 		return
@@ -69,26 +72,28 @@ func (h *PublisherHandler) create(c *gin.Context) {
 //	@Description Get a list of publishers.
 //	@Produce json
 //	@Param q query string false "Publisher's name"
+//	@Param city query string false "City"
 //	@Param s query int false "Data offset"
 //	@Param l query int false "Data limit"
+//	@Param sort query string false "Sort by id, name, city, created_at, comma separated, descending when prefixed with -"
+//	@Param cursor query string false "Next or previous cursor of a page, in place of the offset"
 //	@Success 200 {object} dto.SuccessResponse[[]dto.PublisherResp]
 //	@Failure 422 {object} dto.ErrorResponse
-//	@Failure 404 {object} dto.ErrorResponse
-//	@Failure 422 {object} dto.ErrorResponse
 //	@Failure 500 {object} dto.ErrorResponse
 //	@Router /publishers [get]
 func (h *PublisherHandler) getList(c *gin.Context) {
-	var req dto.Filter
+	var req dto.PublisherFilter
 	if err := c.ShouldBindQuery(&req); err != nil {
 		c.JSON(h.hr.BindingError(err))
 		return
 	}
 
-	data, err := h.service.GetList(&req)
+	data, meta, err := h.service.GetList(c.Request.Context(), &req)
 	if err != nil {
 		switch {
-		case errors.Is(err, exception.ErrDataNotFound):
-			c.JSON(http.StatusNotFound, h.hr.ErrorResponse(err.Error()))
+		case errors.Is(err, exception.ErrSortInvalid),
+			errors.Is(err, exception.ErrCursorInvalid):
+			c.JSON(http.StatusUnprocessableEntity, h.hr.ErrorResponse(err.Error()))
 		default:
 			h.hr.ErrorInternalServer(c, err)
 		}
@@ -100,6 +105,7 @@ func (h *PublisherHandler) getList(c *gin.Context) {
 		Success: true,
 		Message: "Daftar penerbit",
 		Data:    data,
+		Meta:    &meta,
 	})
 }
 
@@ -123,7 +129,7 @@ func (h *PublisherHandler) getByID(c *gin.Context) {
 		return
 	}
 
-	data, err := h.service.GetByID(uint(id))
+	data, err := h.service.GetByID(c.Request.Context(), uint(id))
 	if err != nil {
 		switch {
 		case errors.Is(err, exception.ErrDataNotFound):
@@ -175,7 +181,7 @@ func (h *PublisherHandler) update(c *gin.Context) {
 	}
 	req.ID = uint(id)
 
-	err = h.service.Update(&req)
+	err = h.service.Update(c.Request.Context(), &req)
 	if err != nil {
 		switch {
 		case errors.Is(err, exception.ErrDataNotFound):
@@ -216,7 +222,7 @@ func (h *PublisherHandler) delete(c *gin.Context) {
 		return
 	}
 
-	err = h.service.Delete(uint(id))
+	err = h.service.Delete(c.Request.Context(), uint(id))
 	if err != nil {
 		h.hr.ErrorInternalServer(c, err)
 		return
@@ -1,6 +1,7 @@
 package rest
 
 import (
+	"base-gin/domain"
 	"base-gin/server"
 	"base-gin/service"
 
@@ -13,7 +14,18 @@ var (
 	publisherHandler *PublisherHandler
 	authorHandler    *AuthorHandler
 	bookHandler      *BookHandler
+	categoryHandler  *CategoryHandler
+	bookCopyHandler  *BookCopyHandler
 	BorrowHandler    *BorrowingHandler
+	circHandler      *CirculationHandler
+	fineHandler      *FineHandler
+	holdHandler      *HoldHandler
+	ruleHandler      *CirculationRuleHandler
+	notifyHandler    *NotificationHandler
+	adminHandler     *AdminHandler
+	jobHandler       *JobHandler
+	searchHandler    *SearchHandler
+	importHandler    *ImportHandler
 )
 
 func SetupRestHandlers(app *gin.Engine) {
@@ -23,9 +35,23 @@ func SetupRestHandlers(app *gin.Engine) {
 		handler, service.GetAccountService(), service.GetPersonService())
 	personHandler = NewPersonHandler(handler, service.GetPersonService())
 	publisherHandler = NewPublisherHandler(handler, service.GetPublisherService())
-	authorHandler = NewAuthorHandler(handler, service.GetAuthorService())
+	authorHandler = NewAuthorHandler(
+		handler, service.GetAuthorService(), service.GetBookService())
 	bookHandler = NewBookHandler(handler, service.GetBookService())
+	categoryHandler = NewCategoryHandler(
+		handler, service.GetCategoryService(), service.GetBookService())
+	bookCopyHandler = NewBookCopyHandler(handler, service.GetBookCopyService())
 	BorrowHandler = NewBorrowingHandler(handler, service.GetBorrowingService())
+	circHandler = NewCirculationHandler(handler, service.GetBorrowingService())
+	fineHandler = NewFineHandler(handler, service.GetFineService())
+	holdHandler = NewHoldHandler(handler, service.GetHoldService())
+	ruleHandler = NewCirculationRuleHandler(handler, service.GetCirculationRuleService())
+	notifyHandler = NewNotificationHandler(handler, service.GetNotificationService())
+	adminHandler = NewAdminHandler(
+		handler, service.GetAccountService(), service.GetPersonService())
+	jobHandler = NewJobHandler(handler, service.GetJobService())
+	searchHandler = NewSearchHandler(handler, service.GetSearchService())
+	importHandler = NewImportHandler(handler, service.GetImportService())
 
 	setupRoutes(app)
 }
@@ -36,5 +62,27 @@ func setupRoutes(app *gin.Engine) {
 	publisherHandler.Route(app)
 	authorHandler.Route(app)
 	bookHandler.Route(app)
+	categoryHandler.Route(app)
+	bookCopyHandler.Route(app)
 	BorrowHandler.Route(app)
+	circHandler.Route(app)
+	fineHandler.Route(app)
+	holdHandler.Route(app)
+	ruleHandler.Route(app)
+	notifyHandler.Route(app)
+	adminHandler.Route(app)
+	jobHandler.Route(app)
+	searchHandler.Route(app)
+	importHandler.Route(app)
+}
+
+// memberAccountID returns the ID of the authenticated account when it is a
+// member, whose access is limited to its own records, and nil otherwise.
+func memberAccountID(c *gin.Context) *uint {
+	if server.TokenRole(c) != domain.RoleMember {
+		return nil
+	}
+
+	accountID := c.GetUint(server.ParamTokenUserID)
+	return &accountID
 }
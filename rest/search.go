@@ -0,0 +1,57 @@
+package rest
+
+import (
+	"base-gin/domain/dto"
+	"base-gin/server"
+	"base-gin/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SearchHandler struct {
+	hr      *server.Handler
+	service *service.SearchService
+}
+
+func NewSearchHandler(handler *server.Handler, searchService *service.SearchService) *SearchHandler {
+	return &SearchHandler{hr: handler, service: searchService}
+}
+
+func (h *SearchHandler) Route(app *gin.Engine) {
+	app.GET(server.RootSearch, h.search)
+}
+
+// search godoc
+//
+//	@Summary Search the catalog
+//	@Description Search the books by title, subtitle, contributor and publisher, the most relevant first. Every word must match, allowing for typos and for the last word being incomplete. The highlights are HTML escaped, with the matched words wrapped in <mark> tags.
+//	@Produce json
+//	@Param q query string true "Search words"
+//	@Param publisher_id query int false "Publisher ID"
+//	@Param author_id query int false "Author ID"
+//	@Param s query int false "Data offset"
+//	@Param l query int false "Data limit, 20 by default"
+//	@Success 200 {object} dto.SuccessResponse[dto.SearchResp]
+//	@Failure 422 {object} dto.ErrorResponse
+//	@Failure 500 {object} dto.ErrorResponse
+//	@Router /search [get]
+func (h *SearchHandler) search(c *gin.Context) {
+	var req dto.SearchFilter
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(h.hr.BindingError(err))
+		return
+	}
+
+	data, err := h.service.Search(c.Request.Context(), &req)
+	if err != nil {
+		h.hr.ErrorInternalServer(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse[dto.SearchResp]{
+		Success: true,
+		Message: "Hasil pencarian",
+		Data:    data,
+	})
+}
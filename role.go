@@ -0,0 +1,46 @@
+package main
+
+import (
+	"base-gin/domain"
+	"base-gin/repository"
+	"base-gin/storage"
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/rs/zerolog/log"
+)
+
+const roleUsage = `Usage:
+  role <username> <admin|librarian|member>  set the role of an account`
+
+// runRole sets the role of an account, which is how the first admin is
+// created since only admins may change roles through the API.
+func runRole(args []string) {
+	if len(args) != 2 {
+		fmt.Fprintln(os.Stderr, roleUsage)
+		os.Exit(2)
+	}
+
+	role := domain.TypeRole(args[1])
+	switch role {
+	case domain.RoleAdmin, domain.RoleLibrarian, domain.RoleMember:
+	default:
+		fmt.Fprintln(os.Stderr, roleUsage)
+		os.Exit(2)
+	}
+
+	ctx := context.Background()
+	repo := repository.NewAccountRepository(storage.GetDB())
+
+	account, err := repo.GetByUsername(ctx, args[0])
+	if err != nil {
+		log.Fatal().Err(err).Str("username", args[0]).Msg("Set role failed")
+	}
+
+	if err := repo.UpdateRole(ctx, account.ID, role); err != nil {
+		log.Fatal().Err(err).Str("username", args[0]).Msg("Set role failed")
+	}
+
+	log.Info().Str("username", account.Username).Str("role", string(role)).Msg("Role set")
+}
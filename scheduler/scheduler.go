@@ -0,0 +1,271 @@
+package scheduler
+
+import (
+	"base-gin/domain"
+	"base-gin/domain/dao"
+	"base-gin/exception"
+	"base-gin/repository"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// maxTextLen is the size of the message and error columns of a job run.
+const maxTextLen = 255
+
+// Job is a named task run on its cron spec. Spec takes the standard five
+// fields, or is empty for a job that only runs when triggered. Run returns a
+// short summary of what it did.
+type Job struct {
+	Name string
+	Spec string
+	Run  func(ctx context.Context) (string, error)
+}
+
+// Info describes a registered job. NextRun is nil while the scheduler is not
+// started and for jobs without a spec.
+type Info struct {
+	Name    string
+	Spec    string
+	Running bool
+	NextRun *time.Time
+}
+
+type entry struct {
+	job      Job
+	schedule cron.Schedule
+	running  bool
+}
+
+// Scheduler runs registered jobs on their spec or on demand and records every
+// run. A job never runs twice at the same time; a scheduled run is skipped
+// while the previous one is still going.
+//
+// Instances sharing a database are told apart by the name given to New, so
+// each only fails the runs it left behind.
+type Scheduler struct {
+	repo     *repository.JobRunRepository
+	timeout  time.Duration
+	instance string
+	cron     *cron.Cron
+
+	mu      sync.Mutex
+	entries map[string]*entry
+	names   []string
+	started bool
+	stopped bool
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// New returns a scheduler recording runs in repo under the instance name. Each
+// run is cancelled after timeout.
+func New(repo *repository.JobRunRepository, timeout time.Duration, instance string) *Scheduler {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Scheduler{
+		repo:     repo,
+		timeout:  timeout,
+		instance: instance,
+		cron:     cron.New(),
+		entries:  make(map[string]*entry),
+		ctx:      ctx,
+		cancel:   cancel,
+	}
+}
+
+func (s *Scheduler) Register(job Job) error {
+	var schedule cron.Schedule
+	if job.Spec != "" {
+		var err error
+		if schedule, err = cron.ParseStandard(job.Spec); err != nil {
+			return fmt.Errorf("job %s: %w", job.Name, err)
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.entries[job.Name]; ok {
+		return fmt.Errorf("job %s registered twice", job.Name)
+	}
+	s.entries[job.Name] = &entry{job: job, schedule: schedule}
+	s.names = append(s.names, job.Name)
+
+	if schedule != nil {
+		name := job.Name
+		s.cron.Schedule(schedule, cron.FuncJob(func() {
+			_, err := s.start(s.ctx, name, domain.JobTriggerSchedule)
+			if err != nil && !errors.Is(err, exception.ErrJobRunning) {
+				exception.LogError(err, "Scheduler: "+name)
+			}
+		}))
+	}
+
+	return nil
+}
+
+// Start marks the runs left running by a previous process of the instance as
+// failed, then starts running jobs on their spec.
+func (s *Scheduler) Start(ctx context.Context) error {
+	if _, err := s.repo.FailRunning(ctx, s.instance, "terhenti sebelum selesai"); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.stopped {
+		return exception.ErrSchedulerStopped
+	}
+	s.started = true
+	s.cron.Start()
+
+	return nil
+}
+
+// Stop stops scheduling and waits for the running jobs to finish. Once ctx is
+// done the jobs still running are cancelled and ctx's error is returned.
+func (s *Scheduler) Stop(ctx context.Context) error {
+	s.mu.Lock()
+	s.stopped = true
+	s.mu.Unlock()
+
+	<-s.cron.Stop().Done()
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		s.cancel()
+		return nil
+	case <-ctx.Done():
+		s.cancel()
+		return ctx.Err()
+	}
+}
+
+// Trigger starts a run of the job right away and returns it while it is still
+// running.
+func (s *Scheduler) Trigger(ctx context.Context, name string) (dao.JobRun, error) {
+	return s.start(ctx, name, domain.JobTriggerManual)
+}
+
+// Jobs describes the registered jobs in the order they were registered.
+func (s *Scheduler) Jobs() []Info {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	items := make([]Info, len(s.names))
+	for i, name := range s.names {
+		e := s.entries[name]
+		items[i] = Info{Name: name, Spec: e.job.Spec, Running: e.running}
+		if s.started && !s.stopped && e.schedule != nil {
+			next := e.schedule.Next(now)
+			items[i].NextRun = &next
+		}
+	}
+
+	return items
+}
+
+func (s *Scheduler) start(ctx context.Context, name string, trigger domain.TypeJobTrigger) (dao.JobRun, error) {
+	s.mu.Lock()
+	e, ok := s.entries[name]
+	switch {
+	case !ok:
+		s.mu.Unlock()
+		return dao.JobRun{}, exception.ErrJobNotFound
+	case s.stopped:
+		s.mu.Unlock()
+		return dao.JobRun{}, exception.ErrSchedulerStopped
+	case e.running:
+		s.mu.Unlock()
+		return dao.JobRun{}, exception.ErrJobRunning
+	}
+	e.running = true
+	s.wg.Add(1)
+	s.mu.Unlock()
+
+	run := dao.JobRun{
+		JobName:   name,
+		Instance:  s.instance,
+		Trigger:   trigger,
+		Status:    domain.JobRunning,
+		StartedAt: time.Now(),
+	}
+	if err := s.repo.Create(ctx, &run); err != nil {
+		s.done(e)
+		return run, err
+	}
+
+	go s.execute(e, run)
+
+	return run, nil
+}
+
+func (s *Scheduler) execute(e *entry, run dao.JobRun) {
+	defer s.done(e)
+
+	ctx, cancel := context.WithTimeout(s.ctx, s.timeout)
+	defer cancel()
+
+	message, err := safeRun(ctx, e.job)
+
+	finishedAt := time.Now()
+	run.FinishedAt = &finishedAt
+	run.DurationMs = finishedAt.Sub(run.StartedAt).Milliseconds()
+	run.Status = domain.JobSucceeded
+	if message != "" {
+		message = truncate(message)
+		run.Message = &message
+	}
+	if err != nil {
+		exception.LogError(err, "Scheduler: "+run.JobName)
+		errMsg := truncate(err.Error())
+		run.Status = domain.JobFailed
+		run.Error = &errMsg
+	}
+
+	// The run is recorded even when the job was cancelled by Stop.
+	if err := s.repo.Finish(context.Background(), &run); err != nil {
+		exception.LogError(err, "Scheduler.execute")
+	}
+}
+
+func (s *Scheduler) done(e *entry) {
+	s.mu.Lock()
+	e.running = false
+	s.mu.Unlock()
+	s.wg.Done()
+}
+
+// safeRun runs the job, turning a panic into an error so it is recorded as a
+// failed run instead of taking the process down.
+func safeRun(ctx context.Context, job Job) (message string, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+
+	return job.Run(ctx)
+}
+
+func truncate(s string) string {
+	r := []rune(s)
+	if len(r) <= maxTextLen {
+		return s
+	}
+	return string(r[:maxTextLen])
+}
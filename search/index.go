@@ -0,0 +1,507 @@
+// Package search is an in-memory full-text index ranking documents with BM25
+// over boosted fields. Query terms are matched exactly, by prefix for the
+// last one typed, or within a few typos, and every term must match.
+package search
+
+import (
+	"html"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// Tags wrapped around the matched words of a highlighted field. The rest of
+// the field is HTML escaped, so a highlight is safe to render as is.
+const (
+	HighlightPre  = "<mark>"
+	HighlightPost = "</mark>"
+)
+
+// BM25 parameters.
+const (
+	k1 = 1.2
+	b  = 0.75
+)
+
+// Weights of a term matching a query term other than exactly.
+const (
+	prefixWeight = 0.8
+	typoWeight   = 0.5 // per typo
+)
+
+// Field is a text field of the documents, its matches weighed by Boost.
+type Field struct {
+	Name  string
+	Boost float64
+}
+
+// Facet is a value documents are counted & filtered by, e.g. a publisher.
+type Facet struct {
+	ID   uint
+	Name string
+}
+
+type Document struct {
+	ID     uint
+	Fields map[string]string
+	Facets map[string][]Facet
+}
+
+type Query struct {
+	Text string
+	// Filters keeps the documents having the facet value of the given ID.
+	Filters map[string]uint
+	Start   int
+	Limit   int
+}
+
+type Hit struct {
+	ID    uint
+	Score float64
+	// Highlights holds the matching fields as HTML with the matched words
+	// tagged.
+	Highlights map[string]string
+}
+
+type FacetCount struct {
+	Facet
+	Count int
+}
+
+type Result struct {
+	// Total is the number of matching documents, all pages included.
+	Total int
+	Hits  []Hit
+	// Facets counts the matching documents by facet value, the most common
+	// first.
+	Facets map[string][]FacetCount
+}
+
+type Index struct {
+	mu       sync.RWMutex
+	fields   []Field
+	docs     map[uint]*entry
+	postings map[string]map[uint]bool
+	lengths  map[string]int
+}
+
+type entry struct {
+	doc    Document
+	terms  map[string]map[string]int // field, term, frequency
+	length map[string]int
+}
+
+func New(fields ...Field) *Index {
+	idx := &Index{fields: fields}
+	idx.Reset(nil)
+	return idx
+}
+
+// Reset replaces the documents of the index with the given ones.
+func (idx *Index) Reset(docs []Document) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	idx.docs = make(map[uint]*entry, len(docs))
+	idx.postings = make(map[string]map[uint]bool)
+	idx.lengths = make(map[string]int, len(idx.fields))
+	for _, doc := range docs {
+		idx.put(doc)
+	}
+}
+
+// Put adds the document or replaces the one with the same ID.
+func (idx *Index) Put(doc Document) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	idx.remove(doc.ID)
+	idx.put(doc)
+}
+
+func (idx *Index) Delete(id uint) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	idx.remove(id)
+}
+
+func (idx *Index) Len() int {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	return len(idx.docs)
+}
+
+func (idx *Index) put(doc Document) {
+	e := &entry{
+		doc:    doc,
+		terms:  make(map[string]map[string]int, len(idx.fields)),
+		length: make(map[string]int, len(idx.fields)),
+	}
+	for _, field := range idx.fields {
+		tokens := Tokenize(doc.Fields[field.Name])
+		if len(tokens) == 0 {
+			continue
+		}
+
+		freqs := make(map[string]int, len(tokens))
+		for _, token := range tokens {
+			freqs[token]++
+			if idx.postings[token] == nil {
+				idx.postings[token] = make(map[uint]bool)
+			}
+			idx.postings[token][doc.ID] = true
+		}
+		e.terms[field.Name] = freqs
+		e.length[field.Name] = len(tokens)
+		idx.lengths[field.Name] += len(tokens)
+	}
+	idx.docs[doc.ID] = e
+}
+
+func (idx *Index) remove(id uint) {
+	e, ok := idx.docs[id]
+	if !ok {
+		return
+	}
+
+	for field, freqs := range e.terms {
+		for term := range freqs {
+			delete(idx.postings[term], id)
+			if len(idx.postings[term]) == 0 {
+				delete(idx.postings, term)
+			}
+		}
+		idx.lengths[field] -= e.length[field]
+	}
+	delete(idx.docs, id)
+}
+
+// Search ranks the documents matching every term of the query, the best
+// first. A query without any term matches nothing.
+func (idx *Index) Search(q Query) Result {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	result := Result{Facets: make(map[string][]FacetCount)}
+
+	queryTerms := unique(Tokenize(q.Text))
+	if len(queryTerms) == 0 {
+		return result
+	}
+	expansions := make([]map[string]float64, len(queryTerms))
+	for i, term := range queryTerms {
+		expansions[i] = idx.expand(term, i == len(queryTerms)-1)
+	}
+
+	var hits []Hit
+	counts := make(map[string]map[uint]*FacetCount)
+	for id, e := range idx.docs {
+		if !e.matchesFilters(q.Filters) {
+			continue
+		}
+		score, ok := idx.score(e, expansions)
+		if !ok {
+			continue
+		}
+
+		hits = append(hits, Hit{ID: id, Score: score})
+		for name, facets := range e.doc.Facets {
+			if counts[name] == nil {
+				counts[name] = make(map[uint]*FacetCount)
+			}
+			for _, facet := range facets {
+				if c, ok := counts[name][facet.ID]; ok {
+					c.Count++
+				} else {
+					counts[name][facet.ID] = &FacetCount{Facet: facet, Count: 1}
+				}
+			}
+		}
+	}
+
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		return hits[i].ID < hits[j].ID
+	})
+	for name, byID := range counts {
+		result.Facets[name] = sortFacets(byID)
+	}
+
+	result.Total = len(hits)
+	hits = page(hits, q.Start, q.Limit)
+
+	matched := make(map[string]bool)
+	for _, terms := range expansions {
+		for term := range terms {
+			matched[term] = true
+		}
+	}
+	for i := range hits {
+		hits[i].Highlights = idx.highlight(idx.docs[hits[i].ID], matched)
+	}
+	result.Hits = hits
+
+	return result
+}
+
+// expand returns the indexed terms matching a query term with their weight:
+// the term itself, the terms it is a prefix of when last, and the terms
+// within the typos allowed for its length.
+func (idx *Index) expand(term string, last bool) map[string]float64 {
+	terms := make(map[string]float64)
+	if _, ok := idx.postings[term]; ok {
+		terms[term] = 1
+	}
+
+	maxTypos := allowedTypos(term)
+	for candidate := range idx.postings {
+		if candidate == term {
+			continue
+		}
+		if last && strings.HasPrefix(candidate, term) {
+			terms[candidate] = prefixWeight
+			continue
+		}
+		if maxTypos == 0 {
+			continue
+		}
+		if d := distance(term, candidate, maxTypos); d <= maxTypos {
+			terms[candidate] = math.Pow(typoWeight, float64(d))
+		}
+	}
+
+	return terms
+}
+
+// score sums the best BM25 score of each query term over the fields. It
+// fails when a query term does not match.
+func (idx *Index) score(e *entry, expansions []map[string]float64) (float64, bool) {
+	total := 0.0
+	for _, terms := range expansions {
+		best := 0.0
+		for term, weight := range terms {
+			if !idx.postings[term][e.doc.ID] {
+				continue
+			}
+			if s := weight * idx.bm25(e, term); s > best {
+				best = s
+			}
+		}
+		if best == 0 {
+			return 0, false
+		}
+		total += best
+	}
+
+	return total, true
+}
+
+func (idx *Index) bm25(e *entry, term string) float64 {
+	n := float64(len(idx.docs))
+	df := float64(len(idx.postings[term]))
+	idf := math.Log(1 + (n-df+0.5)/(df+0.5))
+
+	score := 0.0
+	for _, field := range idx.fields {
+		tf := float64(e.terms[field.Name][term])
+		if tf == 0 {
+			continue
+		}
+		avg := float64(idx.lengths[field.Name]) / n
+		norm := 1 - b + b*float64(e.length[field.Name])/avg
+		score += field.Boost * idf * tf * (k1 + 1) / (tf + k1*norm)
+	}
+
+	return score
+}
+
+func (idx *Index) highlight(e *entry, matched map[string]bool) map[string]string {
+	highlights := make(map[string]string)
+	for _, field := range idx.fields {
+		text := e.doc.Fields[field.Name]
+
+		var sb strings.Builder
+		found := false
+		last := 0
+		for _, span := range tokenSpans(text) {
+			if !matched[strings.ToLower(text[span[0]:span[1]])] {
+				continue
+			}
+			found = true
+			sb.WriteString(html.EscapeString(text[last:span[0]]))
+			sb.WriteString(HighlightPre)
+			sb.WriteString(html.EscapeString(text[span[0]:span[1]]))
+			sb.WriteString(HighlightPost)
+			last = span[1]
+		}
+		if found {
+			sb.WriteString(html.EscapeString(text[last:]))
+			highlights[field.Name] = sb.String()
+		}
+	}
+
+	return highlights
+}
+
+func (e *entry) matchesFilters(filters map[string]uint) bool {
+	for name, id := range filters {
+		found := false
+		for _, facet := range e.doc.Facets[name] {
+			if facet.ID == id {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Tokenize lower-cases the runs of letters & digits of a text.
+func Tokenize(text string) []string {
+	spans := tokenSpans(text)
+	tokens := make([]string, len(spans))
+	for i, span := range spans {
+		tokens[i] = strings.ToLower(text[span[0]:span[1]])
+	}
+
+	return tokens
+}
+
+// tokenSpans returns the byte offsets of the runs of letters & digits.
+func tokenSpans(text string) [][2]int {
+	var spans [][2]int
+	start := -1
+	for i, r := range text {
+		isWord := unicode.IsLetter(r) || unicode.IsDigit(r)
+		switch {
+		case isWord && start < 0:
+			start = i
+		case !isWord && start >= 0:
+			spans = append(spans, [2]int{start, i})
+			start = -1
+		}
+	}
+	if start >= 0 {
+		spans = append(spans, [2]int{start, len(text)})
+	}
+
+	return spans
+}
+
+// allowedTypos grows with the length of the term so short words, where a
+// typo makes another word, must match exactly.
+func allowedTypos(term string) int {
+	switch n := len([]rune(term)); {
+	case n < 4:
+		return 0
+	case n < 8:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// distance is the number of insertions, deletions, substitutions and swaps
+// of adjacent letters turning s into t. Past limit it returns limit+1.
+func distance(s, t string, limit int) int {
+	rs, rt := []rune(s), []rune(t)
+	if diff := len(rs) - len(rt); diff > limit || -diff > limit {
+		return limit + 1
+	}
+
+	prev2 := make([]int, len(rt)+1)
+	prev := make([]int, len(rt)+1)
+	curr := make([]int, len(rt)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(rs); i++ {
+		curr[0] = i
+		rowMin := curr[0]
+		for j := 1; j <= len(rt); j++ {
+			cost := 1
+			if rs[i-1] == rt[j-1] {
+				cost = 0
+			}
+			d := minInt(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && rs[i-1] == rt[j-2] && rs[i-2] == rt[j-1] {
+				d = minInt(d, prev2[j-2]+1)
+			}
+			curr[j] = d
+			rowMin = minInt(rowMin, d)
+		}
+		if rowMin > limit {
+			return limit + 1
+		}
+		prev2, prev, curr = prev, curr, prev2
+	}
+
+	if prev[len(rt)] > limit {
+		return limit + 1
+	}
+	return prev[len(rt)]
+}
+
+func minInt(values ...int) int {
+	m := values[0]
+	for _, v := range values[1:] {
+		if v < m {
+			m = v
+		}
+	}
+
+	return m
+}
+
+func unique(terms []string) []string {
+	seen := make(map[string]bool, len(terms))
+	var items []string
+	for _, term := range terms {
+		if !seen[term] {
+			seen[term] = true
+			items = append(items, term)
+		}
+	}
+
+	return items
+}
+
+func sortFacets(byID map[uint]*FacetCount) []FacetCount {
+	items := make([]FacetCount, 0, len(byID))
+	for _, c := range byID {
+		items = append(items, *c)
+	}
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].Count != items[j].Count {
+			return items[i].Count > items[j].Count
+		}
+		if items[i].Name != items[j].Name {
+			return items[i].Name < items[j].Name
+		}
+		return items[i].ID < items[j].ID
+	})
+
+	return items
+}
+
+func page(hits []Hit, start, limit int) []Hit {
+	if start >= len(hits) {
+		return nil
+	}
+	hits = hits[start:]
+	if limit > 0 && limit < len(hits) {
+		hits = hits[:limit]
+	}
+
+	return hits
+}
@@ -2,6 +2,7 @@ package server
 
 import (
 	"base-gin/config"
+	"base-gin/domain"
 	"base-gin/domain/dto"
 	"base-gin/exception"
 	"base-gin/repository"
@@ -26,7 +27,7 @@ import (
 )
 
 var (
-	ErrRequestThrottled = errors.New("ratelimit")
+	ErrRequestThrottled = exception.ErrRequestThrottled
 )
 
 type BindingErrorMessage struct {
@@ -35,14 +36,16 @@ type BindingErrorMessage struct {
 }
 
 type Handler struct {
-	cfg         config.Config
-	idValidator ut.Translator
-	accountRepo *repository.AccountRepository
+	cfg              config.Config
+	idValidator      ut.Translator
+	accountRepo      *repository.AccountRepository
+	revokedTokenRepo *repository.RevokedTokenRepository
 }
 
 func NewHandler(
 	cfg *config.Config,
 	accountRepo *repository.AccountRepository,
+	revokedTokenRepo *repository.RevokedTokenRepository,
 ) *Handler {
 	var idValidator ut.Translator
 
@@ -56,23 +59,15 @@ func NewHandler(
 		}
 	}
 	return &Handler{
-		cfg:         *cfg,
-		idValidator: idValidator,
-		accountRepo: accountRepo,
+		cfg:              *cfg,
+		idValidator:      idValidator,
+		accountRepo:      accountRepo,
+		revokedTokenRepo: revokedTokenRepo,
 	}
 }
 
 func (h *Handler) BindingError(err error) (int, dto.ErrorResponse) {
-	var ve validator.ValidationErrors
-	if errors.As(err, &ve) {
-		messageBag := make([]BindingErrorMessage, len(ve))
-		for i, fe := range ve {
-			translatedErrMsg := fe.Translate(h.idValidator)
-			messageBag[i] = BindingErrorMessage{
-				Field:   fe.Field(),
-				Message: translatedErrMsg,
-			}
-		}
+	if messageBag := h.ValidationMessages(err); messageBag != nil {
 		return http.StatusUnprocessableEntity, dto.ErrorResponse{
 			Success: false,
 			Message: "Validasi error",
@@ -87,6 +82,35 @@ func (h *Handler) BindingError(err error) (int, dto.ErrorResponse) {
 	}
 }
 
+// ValidationMessages translates the failed validations of err, nil when err
+// is not a validation error.
+func (h *Handler) ValidationMessages(err error) []BindingErrorMessage {
+	var ve validator.ValidationErrors
+	if !errors.As(err, &ve) {
+		return nil
+	}
+
+	messageBag := make([]BindingErrorMessage, len(ve))
+	for i, fe := range ve {
+		translatedErrMsg := fe.Translate(h.idValidator)
+		messageBag[i] = BindingErrorMessage{
+			Field:   fe.Field(),
+			Message: translatedErrMsg,
+		}
+	}
+	return messageBag
+}
+
+// PolicyViolation builds the response to an action blocked by a circulation
+// rule, telling which check and rule blocked it.
+func (h *Handler) PolicyViolation(v *exception.PolicyViolation) (int, dto.ErrorResponse) {
+	return http.StatusUnprocessableEntity, dto.ErrorResponse{
+		Success: false,
+		Message: v.Error(),
+		Errors:  dto.PolicyViolationResp{Code: v.Code, RuleID: v.RuleID},
+	}
+}
+
 func (h *Handler) ErrorResponse(message string) dto.ErrorResponse {
 	return dto.ErrorResponse{
 		Success: false,
@@ -120,6 +144,26 @@ func (h *Handler) verifyAuthRefreshToken(r *http.Request) (jwt.MapClaims, error)
 	return util.VerifyAuthRefreshToken(h.cfg, strArr[1])
 }
 
+// verifyNotRevoked rejects tokens without an ID, since those can not be
+// revoked, and tokens found on the denylist. It returns the status code to
+// abort with along with the error.
+func (h *Handler) verifyNotRevoked(c *gin.Context, info util.TokenInfo) (int, error) {
+	if info.ID == "" {
+		return http.StatusUnauthorized, util.ErrTokenInvalid
+	}
+
+	revoked, err := h.revokedTokenRepo.IsRevoked(c.Request.Context(), info.ID)
+	if err != nil {
+		log.Error().Err(err).Msg("Handler.verifyNotRevoked")
+		return http.StatusInternalServerError, errors.New("terdapat kesalahan server")
+	}
+	if revoked {
+		return http.StatusUnauthorized, exception.ErrTokenRevoked
+	}
+
+	return http.StatusOK, nil
+}
+
 func (h *Handler) AuthAccess() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		token, err := h.verifyAuthAccessToken(c.Request)
@@ -132,7 +176,16 @@ func (h *Handler) AuthAccess() gin.HandlerFunc {
 			return
 		}
 
-		account, err := h.accountRepo.GetByUsername(token["sub"].(string))
+		info := util.NewTokenInfo(token)
+		if status, err := h.verifyNotRevoked(c, info); err != nil {
+			c.AbortWithStatusJSON(status, dto.ErrorResponse{
+				Success: false,
+				Message: err.Error(),
+			})
+			return
+		}
+
+		account, err := h.accountRepo.GetByUsername(c.Request.Context(), info.Subject)
 		if err != nil {
 			c.AbortWithStatusJSON(http.StatusUnauthorized, dto.ErrorResponse{
 				Success: false,
@@ -147,13 +200,41 @@ func (h *Handler) AuthAccess() gin.HandlerFunc {
 			})
 			return
 		}
+		if account.IsTokenRevoked(info.IssuedAt) {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, dto.ErrorResponse{
+				Success: false,
+				Message: exception.ErrTokenRevoked.Error(),
+			})
+			return
+		}
 
+		c.Set(ParamTokenID, info.ID)
 		c.Set(ParamTokenUserID, account.ID)
 		c.Set(ParamTokenUsername, account.Username)
+		c.Set(ParamTokenRole, account.Role)
 		c.Next()
 	}
 }
 
+// RequireRole only lets accounts having one of the given roles through. It
+// reads the role set by AuthAccess, so it must be chained after it.
+func (h *Handler) RequireRole(roles ...domain.TypeRole) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		role := TokenRole(c)
+		for _, r := range roles {
+			if role == r {
+				c.Next()
+				return
+			}
+		}
+
+		c.AbortWithStatusJSON(http.StatusForbidden, dto.ErrorResponse{
+			Success: false,
+			Message: exception.ErrForbidden.Error(),
+		})
+	}
+}
+
 func (h *Handler) AuthRefresh() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		token, err := h.verifyAuthRefreshToken(c.Request)
@@ -165,7 +246,34 @@ func (h *Handler) AuthRefresh() gin.HandlerFunc {
 			})
 			return
 		}
-		c.Set(ParamTokenUsername, token["sub"])
+		info := util.NewTokenInfo(token)
+		if status, err := h.verifyNotRevoked(c, info); err != nil {
+			c.AbortWithStatusJSON(status, dto.ErrorResponse{
+				Success: false,
+				Message: err.Error(),
+			})
+			return
+		}
+
+		account, err := h.accountRepo.GetByUsername(c.Request.Context(), info.Subject)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, dto.ErrorResponse{
+				Success: false,
+				Message: err.Error(),
+			})
+			return
+		}
+		if account.IsTokenRevoked(info.IssuedAt) {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, dto.ErrorResponse{
+				Success: false,
+				Message: exception.ErrTokenRevoked.Error(),
+			})
+			return
+		}
+
+		c.Set(ParamTokenID, info.ID)
+		c.Set(ParamTokenUserID, account.ID)
+		c.Set(ParamTokenUsername, account.Username)
 		c.Next()
 	}
 }
@@ -204,6 +312,14 @@ func (h *Handler) MaxPostSizeMb(maxSizeInMB int64) gin.HandlerFunc {
 	}
 }
 
+// TokenRole returns the role of the account authenticated by AuthAccess, or an
+// empty role when the request is not authenticated.
+func TokenRole(c *gin.Context) domain.TypeRole {
+	role, _ := c.Get(ParamTokenRole)
+	r, _ := role.(domain.TypeRole)
+	return r
+}
+
 func (h *Handler) ClientInfo(c *gin.Context) dto.ClientInfo {
 	userAgent := c.GetHeader("User-Agent")
 	ua := user_agent.New(userAgent)
@@ -3,6 +3,7 @@ package server
 import (
 	"base-gin/config"
 	"base-gin/repository"
+	"base-gin/util"
 	"context"
 	"errors"
 	"net/http"
@@ -23,6 +24,8 @@ const (
 	ParamTokenUser     = "x-token-user"
 	ParamTokenUserID   = "x-token-user-id"
 	ParamTokenUsername = "x-token-uname"
+	ParamTokenID       = "x-token-id"
+	ParamTokenRole     = "x-token-role"
 )
 
 var (
@@ -32,12 +35,14 @@ var (
 func Init(
 	cfg *config.Config,
 	accountRepo *repository.AccountRepository,
+	revokedTokenRepo *repository.RevokedTokenRepository,
 ) *gin.Engine {
 	app := gin.New()
 	app.Use(gin.Recovery())       // panic handling
 	registerCustomValidationTag() // returns json field name on errors
+	registerISBNValidation()
 
-	handler = NewHandler(cfg, accountRepo)
+	handler = NewHandler(cfg, accountRepo, revokedTokenRepo)
 
 	return app
 }
@@ -54,7 +59,19 @@ func registerCustomValidationTag() {
 	}
 }
 
-func Serve(handler http.Handler) {
+// registerISBNValidation replaces the isbn tag with one accepting ISBN-10 and
+// ISBN-13, hyphenated or not, and checking their checksum.
+func registerISBNValidation() {
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		_ = v.RegisterValidation("isbn", func(fl validator.FieldLevel) bool {
+			return util.IsISBN(fl.Field().String())
+		})
+	}
+}
+
+// Serve serves handler until SIGINT or SIGTERM, then shuts the server down
+// and calls every onShutdown hook, all within the same grace period.
+func Serve(handler http.Handler, onShutdown ...func(ctx context.Context) error) {
 	srv := &http.Server{
 		Addr:              os.Getenv("SERVER_ADDRESS"),
 		Handler:           handler,
@@ -82,6 +99,11 @@ func Serve(handler http.Handler) {
 	if err := srv.Shutdown(ctx); err != nil {
 		log.Error().Stack().Err(err).Msg("Graceful Errors: Server forced to shutdown")
 	}
+	for _, fn := range onShutdown {
+		if err := fn(ctx); err != nil {
+			log.Error().Stack().Err(err).Msg("Graceful Errors: Shutdown hook")
+		}
+	}
 
 	log.Info().Msg("Graceful Info: Server exiting")
 }
@@ -3,12 +3,60 @@ package server
 const (
 	rootPath = "/v1"
 
-	RootAccount   = rootPath + "/accounts"
-	RootPerson    = rootPath + "/persons"
-	RootPublisher = rootPath + "/publishers"
-	RootAuthor    = rootPath + "/author"
-	RootBook      = rootPath + "/book"
-	RootBorrowing = rootPath + "/borrow"
-
-	PathLogin = "/login"
+	RootAccount      = rootPath + "/accounts"
+	RootPerson       = rootPath + "/persons"
+	RootPublisher    = rootPath + "/publishers"
+	RootAuthor       = rootPath + "/author"
+	RootBook         = rootPath + "/book"
+	RootCategory     = rootPath + "/categories"
+	RootBorrowing    = rootPath + "/borrow"
+	RootCirculation  = rootPath + "/circulation"
+	RootFine         = rootPath + "/fines"
+	RootHold         = rootPath + "/holds"
+	RootNotification = rootPath + "/notifications"
+	RootAdmin        = rootPath + "/admin"
+	RootSearch       = rootPath + "/search"
+	RootImport       = rootPath + "/import"
+
+	PathLogin    = "/login"
+	PathLogout   = "/logout"
+	PathRefresh  = "/refresh"
+	PathRegister = "/register"
+
+	PathCheckout = "/checkout"
+	PathCheckin  = "/checkin"
+
+	PathBorrowings   = "/:id/borrowings"
+	PathMyBorrowings = "/borrowings"
+	PathOverdue      = "/overdue"
+	PathRenew        = "/:id/renew"
+	PathRenewals     = "/:id/renewals"
+
+	PathAuthorBooks = "/:id/books"
+
+	PathCategoryBooks = "/:id/books"
+
+	PathImportCatalog = "/catalog"
+
+	PathBookISBN   = "/isbn/:isbn"
+	PathBookCopies = "/:id/copies"
+	PathBookCopy   = "/:id/copies/:copyId"
+
+	PathFinePerson   = "/persons/:id"
+	PathFinePayments = "/persons/:id/payments"
+	PathFineWaivers  = "/persons/:id/waivers"
+
+	PathNotifyPreference = "/persons/:id/preference"
+	PathNotifyDeliveries = "/persons/:id/deliveries"
+	PathNotifyReminders  = "/reminders"
+
+	PathAdminAccountRevoke  = "/accounts/:id/revoke-tokens"
+	PathAdminAccountRole    = "/accounts/:id/role"
+	PathAdminTokenRevoke    = "/tokens/revoke"
+	PathAdminPersonCategory = "/persons/:id/category"
+	PathAdminRules          = "/circulation-rules"
+	PathAdminRule           = "/circulation-rules/:id"
+	PathAdminJobs           = "/jobs"
+	PathAdminJobRuns        = "/jobs/runs"
+	PathAdminJobRun         = "/jobs/:name/run"
 )
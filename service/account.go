@@ -2,48 +2,334 @@ package service
 
 import (
 	"base-gin/config"
+	"base-gin/domain"
+	"base-gin/domain/dao"
 	"base-gin/domain/dto"
 	"base-gin/exception"
 	"base-gin/repository"
 	"base-gin/util"
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
 )
 
 type AccountService struct {
-	cfg  *config.Config
-	repo *repository.AccountRepository
+	cfg              *config.Config
+	txm              *repository.TxManager
+	repo             *repository.AccountRepository
+	refreshTokenRepo *repository.RefreshTokenRepository
+	revokedTokenRepo *repository.RevokedTokenRepository
+	attemptStore     repository.LoginAttemptStore
 }
 
 func NewAccountService(
 	cfg *config.Config,
+	txManager *repository.TxManager,
 	accountRepo *repository.AccountRepository,
+	refreshTokenRepo *repository.RefreshTokenRepository,
+	revokedTokenRepo *repository.RevokedTokenRepository,
+	attemptStore repository.LoginAttemptStore,
 ) *AccountService {
-	return &AccountService{cfg: cfg, repo: accountRepo}
+	return &AccountService{
+		cfg:              cfg,
+		txm:              txManager,
+		repo:             accountRepo,
+		refreshTokenRepo: refreshTokenRepo,
+		revokedTokenRepo: revokedTokenRepo,
+		attemptStore:     attemptStore,
+	}
 }
 
-func (s *AccountService) Login(p dto.AccountLoginReq) (dto.AccountLoginResp, error) {
+// Login verifies the credential and issues a token pair. Failed attempts are
+// counted per username and per client IP, and once either reaches
+// LoginMaxAttempt within LoginThrottleTTL further attempts are rejected with
+// a *exception.ThrottledError until the window ends.
+func (s *AccountService) Login(
+	ctx context.Context,
+	p dto.AccountLoginReq,
+	client dto.ClientInfo,
+) (dto.AccountLoginResp, error) {
 	var resp dto.AccountLoginResp
 
-	item, err := s.repo.GetByUsername(p.Username)
+	keys := loginThrottleKeys(p.Username, client)
+	if err := s.checkLoginThrottle(ctx, keys); err != nil {
+		return resp, err
+	}
+
+	item, err := s.repo.GetByUsername(ctx, p.Username)
+	if err == nil && !item.VerifyPassword(p.Password) {
+		err = exception.ErrUserLoginFailed
+	}
 	if err != nil {
+		if errors.Is(err, exception.ErrUserNotFound) || errors.Is(err, exception.ErrUserLoginFailed) {
+			s.hitLoginThrottle(ctx, keys)
+		}
 		return resp, err
 	}
 
-	if paswdOk := item.VerifyPassword(p.Password); !paswdOk {
-		return resp, exception.ErrUserLoginFailed
+	if err := s.attemptStore.Reset(ctx, keys[0]); err != nil {
+		exception.LogError(err, "AccountService.Login")
+	}
+
+	resp, _, err = s.issueTokens(ctx, s.refreshTokenRepo, &item)
+	return resp, err
+}
+
+// Register creates an account together with its person profile.
+func (s *AccountService) Register(ctx context.Context, p *dto.AccountRegisterReq) (dto.AccountRegisterResp, error) {
+	var resp dto.AccountRegisterResp
+
+	person, err := p.ToPerson()
+	if err != nil {
+		exception.LogError(err, "AccountService.Register")
+		return resp, exception.ErrDateParsing
 	}
 
-	aToken, err := util.CreateAuthAccessToken(*s.cfg, item.Username)
+	account, err := dao.NewUser(p.Username, p.Password, s.cfg.AuthN.PasswordEncryptionSecret)
 	if err != nil {
 		return resp, err
 	}
 
-	rToken, err := util.CreateAuthRefreshToken(*s.cfg, item.Username)
+	err = s.txm.WithTx(ctx, func(repos *repository.Repositories) error {
+		_, err := repos.Account.GetByUsername(ctx, p.Username)
+		if err == nil {
+			return exception.ErrUserConflict
+		}
+		if !errors.Is(err, exception.ErrUserNotFound) {
+			return err
+		}
+
+		if err := repos.Account.Create(ctx, &account); err != nil {
+			return err
+		}
+
+		person.AccountID = &account.ID
+		return repos.Person.Create(ctx, &person)
+	})
 	if err != nil {
 		return resp, err
 	}
 
+	resp.ID = account.ID
+	resp.Username = account.Username
+	resp.Fullname = person.Fullname
+
+	if p.Login {
+		token, _, err := s.issueTokens(ctx, s.refreshTokenRepo, &account)
+		if err != nil {
+			return resp, err
+		}
+		resp.Token = &token
+	}
+
+	return resp, nil
+}
+
+// Refresh exchanges an active refresh token for a new token pair. The given
+// token is revoked in the process, and presenting an already rotated token
+// revokes every refresh token of the account since it is likely stolen.
+func (s *AccountService) Refresh(ctx context.Context, username, tokenID string) (dto.AccountLoginResp, error) {
+	var resp dto.AccountLoginResp
+	var reusedByAccountID uint
+
+	err := s.txm.WithTx(ctx, func(repos *repository.Repositories) error {
+		account, err := repos.Account.GetByUsername(ctx, username)
+		if err != nil {
+			return err
+		}
+
+		item, err := repos.RefreshToken.GetByTokenID(ctx, tokenID)
+		if err != nil {
+			return err
+		}
+		if item.AccountID != account.ID {
+			return exception.ErrRefreshTokenRevoked
+		}
+		if item.RevokedAt != nil {
+			if item.ReplacedBy != nil {
+				reusedByAccountID = account.ID
+			}
+			return exception.ErrRefreshTokenRevoked
+		}
+
+		var newTokenID string
+		resp, newTokenID, err = s.issueTokens(ctx, repos.RefreshToken, &account)
+		if err != nil {
+			return err
+		}
+
+		return repos.RefreshToken.Revoke(ctx, tokenID, &newTokenID)
+	})
+	if reusedByAccountID != 0 {
+		if errRevoke := s.refreshTokenRepo.RevokeAllByAccountID(ctx, reusedByAccountID); errRevoke != nil {
+			exception.LogError(errRevoke, "AccountService.Refresh")
+		}
+	}
+	if err != nil {
+		return dto.AccountLoginResp{}, err
+	}
+
+	return resp, nil
+}
+
+// Logout revokes the given refresh token. Revoking a token twice is not an
+// error.
+func (s *AccountService) Logout(ctx context.Context, username, tokenID string) error {
+	account, err := s.repo.GetByUsername(ctx, username)
+	if err != nil {
+		return err
+	}
+
+	item, err := s.refreshTokenRepo.GetByTokenID(ctx, tokenID)
+	if err != nil {
+		return err
+	}
+	if item.AccountID != account.ID {
+		return exception.ErrRefreshTokenRevoked
+	}
+
+	err = s.refreshTokenRepo.Revoke(ctx, tokenID, nil)
+	if err != nil && !errors.Is(err, exception.ErrRefreshTokenRevoked) {
+		return err
+	}
+
+	return nil
+}
+
+// RevokeToken puts a single access or refresh token on the denylist. Expired
+// tokens are rejected since there is nothing left to revoke.
+func (s *AccountService) RevokeToken(ctx context.Context, p *dto.TokenRevokeReq) error {
+	claims, err := util.VerifyAuthAccessToken(*s.cfg, p.Token)
+	if err != nil {
+		claims, err = util.VerifyAuthRefreshToken(*s.cfg, p.Token)
+	}
+	if err != nil {
+		return util.ErrTokenInvalid
+	}
+
+	info := util.NewTokenInfo(claims)
+	if info.ID == "" {
+		return util.ErrTokenInvalid
+	}
+
+	account, err := s.repo.GetByUsername(ctx, info.Subject)
+	if err != nil {
+		return err
+	}
+
+	return s.revokedTokenRepo.Create(ctx, &dao.RevokedToken{
+		TokenID:   info.ID,
+		AccountID: account.ID,
+		ExpiresAt: info.ExpiresAt,
+	})
+}
+
+// RevokeAllTokens invalidates every token issued to the account so far.
+func (s *AccountService) RevokeAllTokens(ctx context.Context, accountID uint) error {
+	account, err := s.repo.GetByID(ctx, accountID)
+	if err != nil {
+		return err
+	}
+
+	return s.txm.WithTx(ctx, func(repos *repository.Repositories) error {
+		if err := repos.Account.UpdateTokensRevokedAt(ctx, account.ID, time.Now().UTC()); err != nil {
+			return err
+		}
+
+		return repos.RefreshToken.RevokeAllByAccountID(ctx, account.ID)
+	})
+}
+
+// loginThrottleKeys returns the throttle keys of a login attempt, the
+// username's first.
+func loginThrottleKeys(username string, client dto.ClientInfo) []string {
+	return []string{
+		"login:user:" + strings.ToLower(username),
+		"login:ip:" + client.IPAddress,
+	}
+}
+
+func (s *AccountService) checkLoginThrottle(ctx context.Context, keys []string) error {
+	if s.cfg.AuthN.LoginMaxAttempt < 1 {
+		return nil
+	}
+
+	var retryAfter time.Duration
+	for _, key := range keys {
+		count, expiresAt, err := s.attemptStore.Get(ctx, key)
+		if err != nil {
+			return err
+		}
+		if count < s.cfg.AuthN.LoginMaxAttempt {
+			continue
+		}
+		if d := time.Until(expiresAt); d > retryAfter {
+			retryAfter = d
+		}
+	}
+	if retryAfter > 0 {
+		return &exception.ThrottledError{RetryAfter: retryAfter}
+	}
+
+	return nil
+}
+
+// hitLoginThrottle records a failed attempt. A failing store must not hide
+// the login error, so errors are only logged.
+func (s *AccountService) hitLoginThrottle(ctx context.Context, keys []string) {
+	if s.cfg.AuthN.LoginMaxAttempt < 1 {
+		return
+	}
+
+	ttl := time.Duration(s.cfg.AuthN.LoginThrottleTTL) * time.Second
+	for _, key := range keys {
+		if _, _, err := s.attemptStore.Hit(ctx, key, ttl); err != nil {
+			exception.LogError(err, "AccountService.hitLoginThrottle")
+		}
+	}
+}
+
+// SetRole changes the role of the account. The new role applies to tokens
+// issued before the change too, since access checks use the stored role.
+func (s *AccountService) SetRole(ctx context.Context, accountID uint, role domain.TypeRole) error {
+	return s.repo.UpdateRole(ctx, accountID, role)
+}
+
+// issueTokens creates a token pair and records the refresh token through repo,
+// returning the ID of the new refresh token.
+func (s *AccountService) issueTokens(
+	ctx context.Context,
+	repo *repository.RefreshTokenRepository,
+	account *dao.Account,
+) (dto.AccountLoginResp, string, error) {
+	var resp dto.AccountLoginResp
+
+	aToken, err := util.CreateAuthAccessToken(
+		*s.cfg, account.Username, uuid.NewString(), string(account.Role))
+	if err != nil {
+		return resp, "", err
+	}
+
+	tokenID := uuid.NewString()
+	rToken, err := util.CreateAuthRefreshToken(*s.cfg, account.Username, tokenID)
+	if err != nil {
+		return resp, "", err
+	}
+
+	err = repo.Create(ctx, &dao.RefreshToken{
+		TokenID:   tokenID,
+		AccountID: account.ID,
+		ExpiresAt: time.Now().UTC().Add(time.Duration(s.cfg.AuthN.JWTRefreshTTL) * time.Second),
+	})
+	if err != nil {
+		return resp, "", err
+	}
+
 	resp.AccessToken = aToken
 	resp.RefreshToken = rToken
 
-	return resp, nil
+	return resp, tokenID, nil
 }
@@ -3,25 +3,27 @@ package service
 import (
 	"base-gin/domain/dto"
 	"base-gin/repository"
+	"context"
 )
 
 type AuthorService struct {
-	repo *repository.AuthorRepository
+	repo   *repository.AuthorRepository
+	search *SearchService
 }
 
-func NewAuthorService(repo *repository.AuthorRepository) *AuthorService {
-	return &AuthorService{repo: repo}
+func NewAuthorService(repo *repository.AuthorRepository, searchService *SearchService) *AuthorService {
+	return &AuthorService{repo: repo, search: searchService}
 }
 
-func (s *AuthorService) Create(params *dto.AuthorDTO) error {
+func (s *AuthorService) Create(ctx context.Context, params *dto.AuthorDTO) error {
 	author := params.ToEntity()
-	return s.repo.Create(&author)
+	return s.repo.Create(ctx, &author)
 }
 
-func (s *AuthorService) GetList() ([]dto.AuthorResp, error) {
-	authors, err := s.repo.GetList()
+func (s *AuthorService) GetList(ctx context.Context, params *dto.AuthorFilter) ([]dto.AuthorResp, dto.ListMeta, error) {
+	authors, meta, err := s.repo.GetList(ctx, params)
 	if err != nil {
-		return nil, err
+		return nil, meta, err
 	}
 
 	var response []dto.AuthorResp
@@ -31,13 +33,13 @@ func (s *AuthorService) GetList() ([]dto.AuthorResp, error) {
 		response = append(response, resp)
 	}
 
-	return response, nil
+	return response, meta, nil
 }
 
-func (s *AuthorService) GetByID(id uint) (dto.AuthorResp, error) {
+func (s *AuthorService) GetByID(ctx context.Context, id uint) (dto.AuthorResp, error) {
 	var response dto.AuthorResp
 
-	author, err := s.repo.GetByID(id)
+	author, err := s.repo.GetByID(ctx, id)
 	if err != nil {
 		return response, err
 	}
@@ -46,17 +48,21 @@ func (s *AuthorService) GetByID(id uint) (dto.AuthorResp, error) {
 	return response, nil
 }
 
-func (s *AuthorService) Update(params *dto.AuthorUpdate) error {
+func (s *AuthorService) Update(ctx context.Context, params *dto.AuthorUpdate) error {
 	author := params.ToEntity()
-	if err := s.repo.Update(&author); err != nil {
+	if err := s.repo.Update(ctx, &author); err != nil {
 		return err
 	}
+
+	s.search.syncAuthor(ctx, author.ID)
 	return nil
 }
 
-func (s *AuthorService) Delete(id uint) error {
-	if err := s.repo.Delete(id); err != nil {
+func (s *AuthorService) Delete(ctx context.Context, id uint) error {
+	if err := s.repo.Delete(ctx, id); err != nil {
 		return err
 	}
+
+	s.search.syncAuthor(ctx, id)
 	return nil
 }
@@ -1,75 +1,308 @@
 package service
 
 import (
+	"base-gin/domain"
+	"base-gin/domain/dao"
 	"base-gin/domain/dto"
+	"base-gin/exception"
 	"base-gin/repository"
+	"base-gin/util"
+	"context"
+	"errors"
+	"sort"
 )
 
 type BookService struct {
-	repo *repository.BookRepository
+	txm          *repository.TxManager
+	repo         *repository.BookRepository
+	copyRepo     *repository.BookCopyRepository
+	authorRepo   *repository.AuthorRepository
+	categoryRepo *repository.CategoryRepository
+	search       *SearchService
 }
 
-func NewBookService(bookRepo *repository.BookRepository) *BookService {
-	return &BookService{repo: bookRepo}
+func NewBookService(
+	txManager *repository.TxManager,
+	bookRepo *repository.BookRepository,
+	bookCopyRepo *repository.BookCopyRepository,
+	authorRepo *repository.AuthorRepository,
+	categoryRepo *repository.CategoryRepository,
+	searchService *SearchService,
+) *BookService {
+	return &BookService{
+		txm:          txManager,
+		repo:         bookRepo,
+		copyRepo:     bookCopyRepo,
+		authorRepo:   authorRepo,
+		categoryRepo: categoryRepo,
+		search:       searchService,
+	}
 }
 
-func (s *BookService) Create(params *dto.BookDTO) error {
+func (s *BookService) Create(ctx context.Context, params *dto.BookDTO) error {
 	newItem := params.ToEntity()
-	return s.repo.Create(&newItem)
+	if err := s.checkISBN(ctx, newItem.ISBN13, 0); err != nil {
+		return err
+	}
+	if err := s.checkContributors(ctx, newItem.Contributors); err != nil {
+		return err
+	}
+	categoryIDs, err := s.checkCategories(ctx, params.CategoryIDs)
+	if err != nil {
+		return err
+	}
+
+	err = s.txm.WithTx(ctx, func(repos *repository.Repositories) error {
+		if err := repos.Book.Create(ctx, &newItem); err != nil {
+			return err
+		}
+
+		return repos.Book.ReplaceCategories(ctx, newItem.ID, categoryIDs)
+	})
+	if err != nil {
+		return err
+	}
+
+	s.search.syncBooks(ctx, newItem.ID)
+	return nil
+}
+
+func (s *BookService) GetByID(ctx context.Context, id uint) (dto.BookResp, error) {
+	item, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return dto.BookResp{}, err
+	}
+
+	return s.withCopyCount(ctx, &item)
+}
+
+// GetByISBN looks a book up by its ISBN-10 or ISBN-13, hyphenated or not.
+func (s *BookService) GetByISBN(ctx context.Context, isbn string) (dto.BookResp, error) {
+	_, isbn13, err := util.ParseISBN(isbn)
+	if err != nil {
+		return dto.BookResp{}, err
+	}
+
+	item, err := s.repo.GetByISBN(ctx, isbn13)
+	if err != nil {
+		return dto.BookResp{}, err
+	}
+
+	return s.withCopyCount(ctx, &item)
 }
 
-func (s *BookService) GetByID(id uint) (dto.BookResp, error) {
+func (s *BookService) withCopyCount(ctx context.Context, item *dao.Book) (dto.BookResp, error) {
 	var resp dto.BookResp
+	resp.FromEntity(item)
 
-	item, err := s.repo.GetByID(id)
+	counts, err := s.copyRepo.CountByBookIDs(ctx, []uint{item.ID})
 	if err != nil {
 		return resp, err
 	}
+	resp.SetCopyCount(counts[item.ID])
+
+	return resp, nil
+}
+
+func (s *BookService) GetList(ctx context.Context, params *dto.BookFilter) ([]dto.BookResp, dto.ListMeta, error) {
+	items, meta, err := s.repo.GetList(ctx, params)
+	if err != nil {
+		return nil, meta, err
+	}
+
+	resp, err := bookResps(ctx, s.copyRepo, items)
+	return resp, meta, err
+}
+
+// GetListByCategoryID lists the books filed under the category or any of its
+// descendants, by title unless asked otherwise.
+func (s *BookService) GetListByCategoryID(
+	ctx context.Context,
+	categoryID uint,
+	params *dto.Filter,
+) ([]dto.BookResp, dto.ListMeta, error) {
+	categories, err := s.categoryRepo.GetList(ctx)
+	if err != nil {
+		return nil, dto.ListMeta{}, err
+	}
+	tree := newCategoryTree(categories)
+	if _, ok := tree.byID[categoryID]; !ok {
+		return nil, dto.ListMeta{}, exception.ErrDataNotFound
+	}
+
+	items, meta, err := s.repo.GetListByCategoryIDs(ctx, tree.descendants(categoryID), params)
+	if err != nil {
+		return nil, meta, err
+	}
+
+	resp, err := bookResps(ctx, s.copyRepo, items)
+	return resp, meta, err
+}
+
+// bookResps returns the books along with their copy counts.
+func bookResps(
+	ctx context.Context,
+	copyRepo *repository.BookCopyRepository,
+	items []dao.Book,
+) ([]dto.BookResp, error) {
+	ids := make([]uint, len(items))
+	for i, item := range items {
+		ids[i] = item.ID
+	}
+	counts, err := copyRepo.CountByBookIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
 
-	resp.FromEntity(&item)
+	resp := make([]dto.BookResp, len(items))
+	for i := range items {
+		resp[i].FromEntity(&items[i])
+		resp[i].SetCopyCount(counts[items[i].ID])
+	}
 
 	return resp, nil
 }
 
-func (s *BookService) GetList(params *dto.Filter) ([]dto.BookResp, error) {
-	var resp []dto.BookResp
+// Di service/book_service.go
+func (s *BookService) Update(ctx context.Context, input *dto.BookUpdate) error {
+	// Convert DTO to entity
+	book := input.ToEntity()
+	if err := s.checkISBN(ctx, book.ISBN13, book.ID); err != nil {
+		return err
+	}
+	if err := s.checkContributors(ctx, book.Contributors); err != nil {
+		return err
+	}
+	categoryIDs, err := s.checkCategories(ctx, input.CategoryIDs)
+	if err != nil {
+		return err
+	}
 
-	items, err := s.repo.GetList()
+	// Update di repository
+	err = s.txm.WithTx(ctx, func(repos *repository.Repositories) error {
+		if err := repos.Book.Update(ctx, book); err != nil {
+			return err
+		}
+		if err := repos.Book.ReplaceContributors(ctx, book.ID, book.Contributors); err != nil {
+			return err
+		}
+
+		return repos.Book.ReplaceCategories(ctx, book.ID, categoryIDs)
+	})
 	if err != nil {
+		return err
+	}
+
+	s.search.syncBooks(ctx, book.ID)
+	return nil
+}
+
+// GetListByAuthorID lists the books the author contributed to, in the given
+// role or in any, grouped by role.
+func (s *BookService) GetListByAuthorID(
+	ctx context.Context,
+	authorID uint,
+	params *dto.AuthorWorkFilter,
+) ([]dto.AuthorWorkResp, error) {
+	if _, err := s.authorRepo.GetByID(ctx, authorID); err != nil {
 		return nil, err
 	}
 
-	for _, item := range items {
-		var t dto.BookResp
-		t.FromEntity(&item)
+	items, err := s.repo.GetContributions(ctx, authorID, domain.TypeContributorRole(params.Role))
+	if err != nil {
+		return nil, err
+	}
 
-		resp = append(resp, t)
+	rank := map[domain.TypeContributorRole]int{
+		domain.ContributorAuthor:      0,
+		domain.ContributorEditor:      1,
+		domain.ContributorTranslator:  2,
+		domain.ContributorIllustrator: 3,
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		return rank[items[i].Role] < rank[items[j].Role]
+	})
+
+	resp := make([]dto.AuthorWorkResp, len(items))
+	for i := range items {
+		resp[i].FromEntity(&items[i])
 	}
 
 	return resp, nil
 }
 
-// Di service/book_service.go
-func (s *BookService) Update(input *dto.BookUpdate) error {
-    // Convert DTO to entity
-    book := input.ToEntity()
-    
-    // Update di repository
-    err := s.repo.Update(book)
-    if err != nil {
-        return err
-    }
-    
-    return nil
+func (s *BookService) Delete(ctx context.Context, id uint) error {
+	// Cek apakah buku ada
+	_, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	// Hapus buku
+	if err := s.repo.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	s.search.syncBooks(ctx, id)
+	return nil
 }
 
-func (s *BookService) Delete(id uint) error {
-    // Cek apakah buku ada
-    _, err := s.repo.GetByID(id)
-    if err != nil {
-        return err
-    }
+// checkISBN fails when the ISBN belongs to a book other than exceptID,
+// deleted ones included.
+func (s *BookService) checkISBN(ctx context.Context, isbn13 *string, exceptID uint) error {
+	if isbn13 == nil {
+		return nil
+	}
+
+	item, err := s.repo.GetByISBNUnscoped(ctx, *isbn13)
+	if errors.Is(err, exception.ErrDataNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if item.ID != exceptID {
+		return exception.ErrISBNConflict
+	}
+
+	return nil
+}
+
+// checkContributors fails when a contributor is not a known author.
+func (s *BookService) checkContributors(ctx context.Context, items []dao.BookContributor) error {
+	for _, item := range items {
+		_, err := s.authorRepo.GetByID(ctx, item.AuthorID)
+		if errors.Is(err, exception.ErrDataNotFound) {
+			return exception.ErrContributorNotFound
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// checkCategories drops the repeated IDs and fails when a category is not
+// known.
+func (s *BookService) checkCategories(ctx context.Context, ids []uint) ([]uint, error) {
+	seen := make(map[uint]bool, len(ids))
+	var unique []uint
+	for _, id := range ids {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		_, err := s.categoryRepo.GetByID(ctx, id)
+		if errors.Is(err, exception.ErrDataNotFound) {
+			return nil, exception.ErrCategoryNotFound
+		}
+		if err != nil {
+			return nil, err
+		}
+		unique = append(unique, id)
+	}
 
-    // Hapus buku
-    return s.repo.Delete(id)
+	return unique, nil
 }
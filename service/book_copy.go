@@ -0,0 +1,219 @@
+package service
+
+import (
+	"base-gin/config"
+	"base-gin/domain"
+	"base-gin/domain/dao"
+	"base-gin/domain/dto"
+	"base-gin/exception"
+	"base-gin/repository"
+	"context"
+	"errors"
+	"time"
+)
+
+type BookCopyService struct {
+	cfg           *config.Config
+	txm           *repository.TxManager
+	repo          *repository.BookCopyRepository
+	bookRepo      *repository.BookRepository
+	borrowingRepo *repository.BorrowingRepository
+}
+
+func NewBookCopyService(
+	cfg *config.Config,
+	txManager *repository.TxManager,
+	bookCopyRepo *repository.BookCopyRepository,
+	bookRepo *repository.BookRepository,
+	borrowingRepo *repository.BorrowingRepository,
+) *BookCopyService {
+	return &BookCopyService{
+		cfg:           cfg,
+		txm:           txManager,
+		repo:          bookCopyRepo,
+		bookRepo:      bookRepo,
+		borrowingRepo: borrowingRepo,
+	}
+}
+
+func (s *BookCopyService) Create(ctx context.Context, params *dto.BookCopyReq) (dto.BookCopyResp, error) {
+	var resp dto.BookCopyResp
+
+	newItem, err := params.ToEntity()
+	if err != nil {
+		exception.LogError(err, "BookCopyService.Create")
+		return resp, exception.ErrDateParsing
+	}
+
+	err = s.txm.WithTx(ctx, func(repos *repository.Repositories) error {
+		// The lock serialises the new copy with the holds of the book.
+		if _, err := repos.Book.LockByID(ctx, params.BookID); err != nil {
+			return err
+		}
+		if err := s.checkBarcode(ctx, repos.BookCopy, newItem.Barcode, 0); err != nil {
+			return err
+		}
+
+		if err := repos.BookCopy.Create(ctx, &newItem); err != nil {
+			return err
+		}
+		if newItem.Status != domain.CopyAvailable {
+			return nil
+		}
+
+		return s.shelve(ctx, repos, &newItem)
+	})
+	if err != nil {
+		return resp, err
+	}
+
+	resp.FromEntity(&newItem)
+	return resp, nil
+}
+
+func (s *BookCopyService) GetList(ctx context.Context, bookID uint) ([]dto.BookCopyResp, error) {
+	if _, err := s.bookRepo.GetByID(ctx, bookID); err != nil {
+		return nil, err
+	}
+
+	items, err := s.repo.GetListByBookID(ctx, bookID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := make([]dto.BookCopyResp, len(items))
+	for i := range items {
+		resp[i].FromEntity(&items[i])
+	}
+
+	return resp, nil
+}
+
+func (s *BookCopyService) GetByID(ctx context.Context, bookID, id uint) (dto.BookCopyResp, error) {
+	var resp dto.BookCopyResp
+
+	item, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return resp, err
+	}
+	if item.BookID != bookID {
+		return resp, exception.ErrDataNotFound
+	}
+
+	resp.FromEntity(&item)
+	return resp, nil
+}
+
+// Update saves a copy's detail. The status of a copy on loan is managed by
+// its borrowing and can not be changed here.
+func (s *BookCopyService) Update(ctx context.Context, params *dto.BookCopyReq) error {
+	item, err := params.ToEntity()
+	if err != nil {
+		exception.LogError(err, "BookCopyService.Update")
+		return exception.ErrDateParsing
+	}
+
+	return s.txm.WithTx(ctx, func(repos *repository.Repositories) error {
+		current, err := repos.BookCopy.LockByID(ctx, params.ID)
+		if err != nil {
+			return err
+		}
+		if current.BookID != params.BookID {
+			return exception.ErrDataNotFound
+		}
+
+		if params.Status == "" {
+			item.Status = current.Status
+		}
+		if current.Status == domain.CopyOnLoan && item.Status != domain.CopyOnLoan {
+			return exception.ErrCopyOnLoan
+		}
+		if current.Status == domain.CopyOnHold && item.Status != domain.CopyOnHold {
+			return exception.ErrCopyOnHold
+		}
+
+		if item.Barcode != current.Barcode {
+			if err := s.checkBarcode(ctx, repos.BookCopy, item.Barcode, current.ID); err != nil {
+				return err
+			}
+		}
+
+		if err := repos.BookCopy.Update(ctx, &item); err != nil {
+			return err
+		}
+		if item.Status != domain.CopyAvailable || current.Status == domain.CopyAvailable {
+			return nil
+		}
+
+		if _, err := repos.Book.LockByID(ctx, current.BookID); err != nil {
+			return err
+		}
+		return s.shelve(ctx, repos, &item)
+	})
+}
+
+// Delete removes a copy that was never lent out. Copies with a borrowing
+// history should be withdrawn instead so the history stays intact.
+func (s *BookCopyService) Delete(ctx context.Context, bookID, id uint) error {
+	item, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if item.BookID != bookID {
+		return exception.ErrDataNotFound
+	}
+	if item.Status == domain.CopyOnLoan {
+		return exception.ErrCopyOnLoan
+	}
+	if item.Status == domain.CopyOnHold {
+		return exception.ErrCopyOnHold
+	}
+
+	borrowed, err := s.borrowingRepo.HasByBookCopyID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if borrowed {
+		return exception.ErrCopyHasHistory
+	}
+
+	return s.repo.Delete(ctx, id)
+}
+
+// shelve hands a copy that became available to the first person queueing for
+// its book, setting the copy aside for them, and updates item's status.
+func (s *BookCopyService) shelve(ctx context.Context, repos *repository.Repositories, item *dao.BookCopy) error {
+	err := releaseForHolds(ctx, repos, s.cfg.Circulation.HoldPickupDays, item.BookID, &item.ID, time.Now())
+	if err != nil {
+		return err
+	}
+
+	current, err := repos.BookCopy.GetByID(ctx, item.ID)
+	if err != nil {
+		return err
+	}
+	item.Status = current.Status
+
+	return nil
+}
+
+// checkBarcode fails when the barcode belongs to a copy other than exceptID.
+func (s *BookCopyService) checkBarcode(
+	ctx context.Context,
+	repo *repository.BookCopyRepository,
+	barcode string,
+	exceptID uint,
+) error {
+	item, err := repo.GetByBarcode(ctx, barcode)
+	if errors.Is(err, exception.ErrDataNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if item.ID != exceptID {
+		return exception.ErrBarcodeConflict
+	}
+
+	return nil
+}
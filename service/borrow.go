@@ -1,63 +1,501 @@
 package service
 
 import (
+	"base-gin/config"
+	"base-gin/domain"
+	"base-gin/domain/dao"
 	"base-gin/domain/dto"
+	"base-gin/exception"
 	"base-gin/repository"
+	"context"
+	"errors"
+	"fmt"
+	"time"
 )
 
 type BorrowingService struct {
-	repo *repository.BorrowingRepository
+	cfg        *config.Config
+	txm        *repository.TxManager
+	repo       *repository.BorrowingRepository
+	bookRepo   *repository.BookRepository
+	personRepo *repository.PersonRepository
+	finePolicy FinePolicy
 }
 
-func NewBorrowingService(borrowingRepo *repository.BorrowingRepository) *BorrowingService {
-	return &BorrowingService{repo: borrowingRepo}
+func NewBorrowingService(
+	cfg *config.Config,
+	txManager *repository.TxManager,
+	borrowingRepo *repository.BorrowingRepository,
+	bookRepo *repository.BookRepository,
+	personRepo *repository.PersonRepository,
+) *BorrowingService {
+	return &BorrowingService{
+		cfg:        cfg,
+		txm:        txManager,
+		repo:       borrowingRepo,
+		bookRepo:   bookRepo,
+		personRepo: personRepo,
+		finePolicy: NewFinePolicy(&cfg.Circulation),
+	}
+}
+
+// Checkout lends a book out to a person as of now, either the copy with the
+// barcode or the first available one. The circulation rule for the person &
+// book caps the loans out at once and sets the loan period. A hold of the
+// person ready for pickup of the book is fulfilled by it.
+func (s *BorrowingService) Checkout(ctx context.Context, params *dto.CheckoutReq) (dto.BorrowingResp, error) {
+	var resp dto.BorrowingResp
+	now := time.Now()
+	newBorrowing := dao.Borrowing{
+		BookID:     params.BookID,
+		PersonID:   params.PersonID,
+		BorrowDate: now,
+	}
+
+	err := s.txm.WithTx(ctx, func(repos *repository.Repositories) error {
+		if params.Barcode != "" {
+			bookCopy, err := repos.BookCopy.GetByBarcode(ctx, params.Barcode)
+			if err != nil {
+				return err
+			}
+			if params.BookID != 0 && bookCopy.BookID != params.BookID {
+				return exception.ErrDataNotFound
+			}
+			newBorrowing.BookID = bookCopy.BookID
+			newBorrowing.BookCopyID = &bookCopy.ID
+		}
+
+		// The locks serialise concurrent checkouts of the same book and by the
+		// same person.
+		book, err := repos.Book.LockByID(ctx, newBorrowing.BookID)
+		if err != nil {
+			return err
+		}
+		person, err := repos.Person.LockByID(ctx, newBorrowing.PersonID)
+		if err != nil {
+			return err
+		}
+
+		rule, err := resolveRule(ctx, repos, &s.cfg.Circulation, person.Category, book.ItemType)
+		if err != nil {
+			return err
+		}
+		newBorrowing.DueDate = now.AddDate(0, 0, rule.LoanPeriodDays)
+
+		if err := checkLoanLimit(ctx, repos, &rule, person.ID); err != nil {
+			return err
+		}
+
+		pickupDays := s.cfg.Circulation.HoldPickupDays
+		if err := expireBookHolds(ctx, repos, pickupDays, newBorrowing.BookID, now); err != nil {
+			return err
+		}
+		if err := claimHold(ctx, repos, pickupDays, &newBorrowing); err != nil {
+			return err
+		}
+
+		if err := s.assignCopy(ctx, repos, &newBorrowing); err != nil {
+			return err
+		}
+		if err := repos.Borrowing.Create(ctx, &newBorrowing); err != nil {
+			return err
+		}
+
+		resp.FromEntity(&newBorrowing, now)
+		return nil
+	})
+
+	return resp, err
+}
+
+// Checkin returns, as of now, the loan of the copy with the barcode or of the
+// book by the person. The copy goes to the next person waiting for the book and
+// a late return is charged to the person. Loans already returned are refused.
+func (s *BorrowingService) Checkin(ctx context.Context, params *dto.CheckinReq) (dto.BorrowingResp, error) {
+	var resp dto.BorrowingResp
+	now := time.Now()
+	lookup := dto.BorrowingLookup{PersonID: params.PersonID, BookID: params.BookID}
+
+	err := s.txm.WithTx(ctx, func(repos *repository.Repositories) error {
+		if params.Barcode != "" {
+			bookCopy, err := repos.BookCopy.GetByBarcode(ctx, params.Barcode)
+			if err != nil {
+				return err
+			}
+			lookup.BookCopyID = bookCopy.ID
+		}
+
+		current, err := repos.Borrowing.LockLatest(ctx, &lookup)
+		if err != nil {
+			return err
+		}
+		if current.ReturnDate != nil {
+			return exception.ErrBorrowingReturned
+		}
+
+		if err := s.releaseCopy(ctx, repos, &current); err != nil {
+			return err
+		}
+		if err := repos.Borrowing.Return(ctx, current.ID, now); err != nil {
+			return err
+		}
+		current.ReturnDate = &now
+		if err := chargeLateReturn(ctx, repos, s.finePolicy, &current); err != nil {
+			return err
+		}
+
+		resp.FromEntity(&current, now)
+		return nil
+	})
+
+	return resp, err
+}
+
+// assignCopy checks that the borrowing can be made and marks the copy lent
+// out. The requested copy must be available, otherwise the first available
+// copy of the book is picked. Books without copies are lent out as a whole,
+// one borrowing at a time, and not while set aside for a hold.
+func (s *BorrowingService) assignCopy(ctx context.Context, repos *repository.Repositories, item *dao.Borrowing) error {
+	if item.BookCopyID != nil {
+		bookCopy, err := repos.BookCopy.LockByID(ctx, *item.BookCopyID)
+		if err != nil {
+			return err
+		}
+		if bookCopy.BookID != item.BookID {
+			return exception.ErrDataNotFound
+		}
+		if bookCopy.Status != domain.CopyAvailable {
+			return exception.ErrCopyUnavailable
+		}
+
+		return repos.BookCopy.UpdateStatus(ctx, bookCopy.ID, domain.CopyOnLoan)
+	}
+
+	hasCopies, err := repos.BookCopy.HasByBookID(ctx, item.BookID)
+	if err != nil {
+		return err
+	}
+	if !hasCopies {
+		borrowed, err := repos.Borrowing.HasActiveByBookID(ctx, item.BookID)
+		if err != nil {
+			return err
+		}
+		if borrowed {
+			return exception.ErrBookBorrowed
+		}
+
+		onHold, err := repos.Hold.HasByBookIDAndStatus(ctx, item.BookID, domain.HoldReady)
+		if err != nil {
+			return err
+		}
+		if onHold {
+			return exception.ErrBookOnHold
+		}
+
+		return nil
+	}
+
+	bookCopy, err := repos.BookCopy.LockAvailableByBookID(ctx, item.BookID)
+	if err != nil {
+		return err
+	}
+	item.BookCopyID = &bookCopy.ID
+
+	return repos.BookCopy.UpdateStatus(ctx, bookCopy.ID, domain.CopyOnLoan)
 }
 
-func (s *BorrowingService) Create(params *dto.BorrowingDTO) error {
-	newBorrowing := params.ToEntity()
-	return s.repo.Create(&newBorrowing)
+// releaseCopy hands the copy of a borrowing to the next person waiting for the
+// book, or makes it available again, unless it was marked otherwise in the
+// meantime, e.g. lost. Books without copies go to the next person likewise.
+func (s *BorrowingService) releaseCopy(ctx context.Context, repos *repository.Repositories, item *dao.Borrowing) error {
+	pickupDays := s.cfg.Circulation.HoldPickupDays
+	if item.BookCopyID == nil {
+		hasCopies, err := repos.BookCopy.HasByBookID(ctx, item.BookID)
+		if err != nil || hasCopies {
+			return err
+		}
+
+		return releaseForHolds(ctx, repos, pickupDays, item.BookID, nil, time.Now())
+	}
+
+	bookCopy, err := repos.BookCopy.LockByID(ctx, *item.BookCopyID)
+	if errors.Is(err, exception.ErrDataNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if bookCopy.Status != domain.CopyOnLoan {
+		return nil
+	}
+
+	return releaseForHolds(ctx, repos, pickupDays, item.BookID, &bookCopy.ID, time.Now())
 }
 
-func (s *BorrowingService) GetByID(id uint) (dto.BorrowingResp, error) {
+// GetByID returns a borrowing. When memberAccountID is set, borrowings of
+// other persons are reported as not found.
+func (s *BorrowingService) GetByID(ctx context.Context, id uint, memberAccountID *uint) (dto.BorrowingResp, error) {
 	var resp dto.BorrowingResp
-	borrowing, err := s.repo.GetByID(id)
+	borrowing, err := s.repo.GetByID(ctx, id)
 	if err != nil {
 		return resp, err
 	}
-	resp.FromEntity(&borrowing)
+
+	if err := checkOwnBorrowing(ctx, s.personRepo, memberAccountID, &borrowing); err != nil {
+		return resp, err
+	}
+
+	resp.FromEntity(&borrowing, time.Now())
 	return resp, nil
 }
 
-func (s *BorrowingService) GetList() ([]dto.BorrowingResp, error) {
-	var resp []dto.BorrowingResp
-	borrowings, err := s.repo.GetList()
+// checkOwnBorrowing reports borrowings of other persons than the member's as
+// not found. Any borrowing passes when memberAccountID is nil.
+func checkOwnBorrowing(
+	ctx context.Context,
+	personRepo *repository.PersonRepository,
+	memberAccountID *uint,
+	item *dao.Borrowing,
+) error {
+	if memberAccountID == nil {
+		return nil
+	}
+
+	person, err := personRepo.GetByAccountID(ctx, *memberAccountID)
+	if err != nil && !errors.Is(err, exception.ErrUserNotFound) {
+		return err
+	}
+	if err != nil || person.ID != item.PersonID {
+		return exception.ErrDataNotFound
+	}
+
+	return nil
+}
+
+// Renew extends the due date of a borrowing by the loan period. A borrowing
+// can be renewed as many times as its circulation rule allows, not once it is
+// overdue by more than the configured days and not while others queue for the
+// book. Members may only renew their own.
+func (s *BorrowingService) Renew(ctx context.Context, params *dto.BorrowingRenewReq, memberAccountID *uint) (dto.BorrowingResp, error) {
+	var resp dto.BorrowingResp
+	now := time.Now()
+
+	err := s.txm.WithTx(ctx, func(repos *repository.Repositories) error {
+		current, err := repos.Borrowing.LockByID(ctx, params.ID)
+		if err != nil {
+			return err
+		}
+		if err := checkOwnBorrowing(ctx, repos.Person, memberAccountID, &current); err != nil {
+			return err
+		}
+
+		if current.ReturnDate != nil {
+			return exception.ErrBorrowingReturned
+		}
+
+		book, err := repos.Book.GetByIDUnscoped(ctx, current.BookID)
+		if err != nil {
+			return err
+		}
+		person, err := repos.Person.GetByID(ctx, current.PersonID)
+		if err != nil {
+			return err
+		}
+		rule, err := resolveRule(ctx, repos, &s.cfg.Circulation, person.Category, book.ItemType)
+		if err != nil {
+			return err
+		}
+
+		if current.RenewalCount >= rule.MaxRenewals {
+			return newPolicyViolation(&rule, exception.PolicyRenewalLimit,
+				fmt.Sprintf("peminjaman sudah diperpanjang %d kali, batasnya %d", current.RenewalCount, rule.MaxRenewals))
+		}
+		if maxDays := s.cfg.Circulation.RenewMaxOverdueDays; current.DaysOverdue(now) > maxDays {
+			return &exception.PolicyViolation{
+				Code:    exception.PolicyRenewOverdue,
+				Message: fmt.Sprintf("peminjaman terlambat lebih dari %d hari tidak dapat diperpanjang", maxDays),
+			}
+		}
+
+		queued, err := repos.Hold.HasByBookIDAndStatus(ctx, current.BookID, domain.HoldWaiting)
+		if err != nil {
+			return err
+		}
+		if queued {
+			return exception.ErrHoldQueued
+		}
+
+		dueDate := current.DueDate.AddDate(0, 0, rule.LoanPeriodDays)
+		if err := repos.Borrowing.Renew(ctx, current.ID, dueDate); err != nil {
+			return err
+		}
+
+		err = repos.Borrowing.CreateRenewal(ctx, &dao.BorrowingRenewal{
+			BorrowingID:     current.ID,
+			PreviousDueDate: current.DueDate,
+			NewDueDate:      dueDate,
+			RenewedByID:     params.RenewedByID,
+		})
+		if err != nil {
+			return err
+		}
+
+		current.DueDate = dueDate
+		current.RenewalCount++
+		resp.FromEntity(&current, now)
+		return nil
+	})
+
+	return resp, err
+}
+
+// GetRenewals lists the renewals of a borrowing. When memberAccountID is set,
+// borrowings of other persons are reported as not found.
+func (s *BorrowingService) GetRenewals(ctx context.Context, id uint, memberAccountID *uint) ([]dto.BorrowingRenewalResp, error) {
+	borrowing, err := s.repo.GetByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
+	if err := checkOwnBorrowing(ctx, s.personRepo, memberAccountID, &borrowing); err != nil {
+		return nil, err
+	}
 
-	for _, borrowing := range borrowings {
-		var borrowingResp dto.BorrowingResp
-		borrowingResp.FromEntity(&borrowing)
-		resp = append(resp, borrowingResp)
+	items, err := s.repo.GetRenewals(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := make([]dto.BorrowingRenewalResp, len(items))
+	for i := range items {
+		resp[i].FromEntity(&items[i])
 	}
 
 	return resp, nil
 }
 
-func (s *BorrowingService) Update(input *dto.BorrowingUpdate) error {
-    // Convert DTO to entity
-    borrowing := input.ToEntity()
-    
-    // Update di repository
-    return s.repo.Update(borrowing)
+// GetList returns the borrowings matching the filter, or only those of the
+// account's person when memberAccountID is set.
+func (s *BorrowingService) GetList(ctx context.Context, params *dto.BorrowingFilter, memberAccountID *uint) ([]dto.BorrowingResp, dto.ListMeta, error) {
+	if memberAccountID != nil {
+		person, err := s.personRepo.GetByAccountID(ctx, *memberAccountID)
+		if errors.Is(err, exception.ErrUserNotFound) {
+			return []dto.BorrowingResp{}, dto.ListMeta{}, nil
+		}
+		if err != nil {
+			return nil, dto.ListMeta{}, err
+		}
+
+		params.PersonID = person.ID
+	}
+
+	return s.getHistory(ctx, params)
+}
+
+// GetListByPersonID returns the borrowing history of a person. When
+// memberAccountID is set only the person of that account may be read.
+func (s *BorrowingService) GetListByPersonID(
+	ctx context.Context,
+	personID uint,
+	params *dto.BorrowingFilter,
+	memberAccountID *uint,
+) ([]dto.BorrowingResp, dto.ListMeta, error) {
+	person, err := s.personRepo.GetByID(ctx, personID)
+	if err != nil {
+		return nil, dto.ListMeta{}, err
+	}
+	if memberAccountID != nil &&
+		(person.AccountID == nil || *person.AccountID != *memberAccountID) {
+		return nil, dto.ListMeta{}, exception.ErrForbidden
+	}
+
+	params.PersonID = person.ID
+	return s.getHistory(ctx, params)
+}
+
+// GetListByBookID returns the borrowing history of a book, deleted or not.
+func (s *BorrowingService) GetListByBookID(ctx context.Context, bookID uint, params *dto.BorrowingFilter) ([]dto.BorrowingResp, dto.ListMeta, error) {
+	book, err := s.bookRepo.GetByIDUnscoped(ctx, bookID)
+	if err != nil {
+		return nil, dto.ListMeta{}, err
+	}
+
+	params.BookID = book.ID
+	return s.getHistory(ctx, params)
+}
+
+func (s *BorrowingService) getHistory(ctx context.Context, params *dto.BorrowingFilter) ([]dto.BorrowingResp, dto.ListMeta, error) {
+	now := time.Now()
+	items, meta, err := s.repo.GetList(ctx, params, now)
+	if err != nil {
+		return nil, meta, err
+	}
+
+	resp := make([]dto.BorrowingResp, len(items))
+	for i := range items {
+		resp[i].FromEntity(&items[i], now)
+	}
+
+	return resp, meta, nil
 }
-func (s *BorrowingService) Delete(id uint) error {
-    // Cek apakah borrowing ada
-    _, err := s.repo.GetByID(id)
-    if err != nil {
-        return err
-    }
 
-    // Hapus borrowing
-    return s.repo.Delete(id)
-}
\ No newline at end of file
+func (s *BorrowingService) Delete(ctx context.Context, id uint) error {
+	return s.txm.WithTx(ctx, func(repos *repository.Repositories) error {
+		// Cek apakah borrowing ada
+		current, err := repos.Borrowing.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if current.ReturnDate == nil {
+			if err := s.releaseCopy(ctx, repos, &current); err != nil {
+				return err
+			}
+		}
+
+		// Hapus borrowing
+		return repos.Borrowing.Delete(ctx, id)
+	})
+}
+
+// GetOverdue lists the borrowings that are at least minDays past their due
+// date, the longest overdue first.
+func (s *BorrowingService) GetOverdue(ctx context.Context, params *dto.OverdueFilter) ([]dto.BorrowingResp, error) {
+	now := time.Now()
+	dueBefore := now.AddDate(0, 0, -params.MinDays)
+
+	items, err := s.repo.GetOverdue(ctx, dueBefore)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := make([]dto.BorrowingResp, len(items))
+	for i := range items {
+		resp[i].FromEntity(&items[i], now)
+	}
+
+	return resp, nil
+}
+
+// SummarizeOverdue counts the borrowings past their due date as of now,
+// telling apart those overdue for the long-overdue days or more.
+func (s *BorrowingService) SummarizeOverdue(ctx context.Context, now time.Time) (dto.OverdueSummary, error) {
+	var resp dto.OverdueSummary
+
+	items, err := s.repo.GetOverdue(ctx, now)
+	if err != nil {
+		return resp, err
+	}
+
+	resp.Overdue = len(items)
+	for i := range items {
+		days := items[i].DaysOverdue(now)
+		if days >= s.cfg.Notification.LongOverdueDays {
+			resp.LongOverdue++
+		}
+		if days > resp.MaxDaysOverdue {
+			resp.MaxDaysOverdue = days
+		}
+	}
+
+	return resp, nil
+}
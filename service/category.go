@@ -0,0 +1,302 @@
+package service
+
+import (
+	"base-gin/domain/dao"
+	"base-gin/domain/dto"
+	"base-gin/exception"
+	"base-gin/repository"
+	"context"
+	"errors"
+)
+
+type CategoryService struct {
+	txm  *repository.TxManager
+	repo *repository.CategoryRepository
+}
+
+func NewCategoryService(
+	txManager *repository.TxManager,
+	categoryRepo *repository.CategoryRepository,
+) *CategoryService {
+	return &CategoryService{txm: txManager, repo: categoryRepo}
+}
+
+func (s *CategoryService) Create(ctx context.Context, params *dto.CategoryReq) (dto.CategoryResp, error) {
+	var resp dto.CategoryResp
+
+	newItem := params.ToEntity()
+	err := s.txm.WithTx(ctx, func(repos *repository.Repositories) error {
+		items, err := repos.Category.LockList(ctx)
+		if err != nil {
+			return err
+		}
+		if err := s.check(ctx, repos, newCategoryTree(items), &newItem); err != nil {
+			return err
+		}
+
+		return repos.Category.Create(ctx, &newItem)
+	})
+	if err != nil {
+		return resp, err
+	}
+
+	resp.FromEntity(&newItem)
+	return resp, nil
+}
+
+// GetTree returns the top categories with their descendants nested, each
+// with its book counts.
+func (s *CategoryService) GetTree(ctx context.Context) ([]dto.CategoryResp, error) {
+	tree, counts, err := s.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var build func(parentID uint) []dto.CategoryResp
+	build = func(parentID uint) []dto.CategoryResp {
+		childIDs := tree.children[parentID]
+		resp := make([]dto.CategoryResp, len(childIDs))
+		for i, id := range childIDs {
+			resp[i] = counts.resp(tree.byID[id])
+			resp[i].Children = build(id)
+		}
+		return resp
+	}
+
+	return build(0), nil
+}
+
+// GetByID returns the category with its path from the top and its direct
+// children, each with its book counts.
+func (s *CategoryService) GetByID(ctx context.Context, id uint) (dto.CategoryResp, error) {
+	var resp dto.CategoryResp
+
+	tree, counts, err := s.load(ctx)
+	if err != nil {
+		return resp, err
+	}
+	item, ok := tree.byID[id]
+	if !ok {
+		return resp, exception.ErrDataNotFound
+	}
+
+	resp = counts.resp(item)
+	for _, ancestor := range tree.ancestors(id) {
+		var ref dto.CategoryRefResp
+		ref.FromEntity(ancestor)
+		resp.Path = append(resp.Path, ref)
+	}
+	for _, childID := range tree.children[id] {
+		resp.Children = append(resp.Children, counts.resp(tree.byID[childID]))
+	}
+
+	return resp, nil
+}
+
+func (s *CategoryService) Update(ctx context.Context, params *dto.CategoryReq) (dto.CategoryResp, error) {
+	var resp dto.CategoryResp
+
+	item := params.ToEntity()
+	err := s.txm.WithTx(ctx, func(repos *repository.Repositories) error {
+		items, err := repos.Category.LockList(ctx)
+		if err != nil {
+			return err
+		}
+		tree := newCategoryTree(items)
+		if _, ok := tree.byID[item.ID]; !ok {
+			return exception.ErrDataNotFound
+		}
+		if err := s.check(ctx, repos, tree, &item); err != nil {
+			return err
+		}
+
+		return repos.Category.Update(ctx, &item)
+	})
+	if err != nil {
+		return resp, err
+	}
+
+	resp.FromEntity(&item)
+	return resp, nil
+}
+
+// Delete removes a category without subcategories. The books filed under it
+// are kept.
+func (s *CategoryService) Delete(ctx context.Context, id uint) error {
+	return s.txm.WithTx(ctx, func(repos *repository.Repositories) error {
+		items, err := repos.Category.LockList(ctx)
+		if err != nil {
+			return err
+		}
+
+		tree := newCategoryTree(items)
+		if _, ok := tree.byID[id]; !ok {
+			return exception.ErrDataNotFound
+		}
+		if len(tree.children[id]) > 0 {
+			return exception.ErrCategoryHasChildren
+		}
+
+		return repos.Category.Delete(ctx, id)
+	})
+}
+
+// check fails when the slug is empty or taken by another category, or when
+// the parent is unknown or would make the tree a cycle. The tree is expected
+// to be locked until item is stored.
+func (s *CategoryService) check(
+	ctx context.Context, repos *repository.Repositories, tree *categoryTree, item *dao.Category,
+) error {
+	if item.Slug == "" {
+		return exception.ErrSlugInvalid
+	}
+
+	other, err := repos.Category.GetBySlug(ctx, item.Slug)
+	switch {
+	case errors.Is(err, exception.ErrDataNotFound):
+	case err != nil:
+		return err
+	case other.ID != item.ID:
+		return exception.ErrCategoryConflict
+	}
+
+	if item.ParentID == nil {
+		return nil
+	}
+
+	if _, ok := tree.byID[*item.ParentID]; !ok {
+		return exception.ErrCategoryNotFound
+	}
+	if item.ID > 0 {
+		for _, id := range tree.descendants(item.ID) {
+			if id == *item.ParentID {
+				return exception.ErrCategoryCycle
+			}
+		}
+	}
+
+	return nil
+}
+
+func (s *CategoryService) load(ctx context.Context) (*categoryTree, categoryCounts, error) {
+	items, err := s.repo.GetList(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	links, err := s.repo.GetBookLinks(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tree := newCategoryTree(items)
+	return tree, tree.countBooks(links), nil
+}
+
+// categoryTree indexes categories by ID and by parent, 0 standing for the
+// top. Children keep the order of the given categories.
+type categoryTree struct {
+	byID     map[uint]*dao.Category
+	children map[uint][]uint
+}
+
+func newCategoryTree(items []dao.Category) *categoryTree {
+	t := &categoryTree{
+		byID:     make(map[uint]*dao.Category, len(items)),
+		children: make(map[uint][]uint),
+	}
+	for i := range items {
+		t.byID[items[i].ID] = &items[i]
+	}
+	for i := range items {
+		var parentID uint
+		if p := items[i].ParentID; p != nil {
+			if _, ok := t.byID[*p]; ok {
+				parentID = *p
+			}
+		}
+		t.children[parentID] = append(t.children[parentID], items[i].ID)
+	}
+
+	return t
+}
+
+// descendants returns the category and every category below it. Each is
+// listed once, even should the stored parents make a cycle.
+func (t *categoryTree) descendants(id uint) []uint {
+	seen := map[uint]bool{id: true}
+	ids := []uint{id}
+	for i := 0; i < len(ids); i++ {
+		for _, childID := range t.children[ids[i]] {
+			if !seen[childID] {
+				seen[childID] = true
+				ids = append(ids, childID)
+			}
+		}
+	}
+
+	return ids
+}
+
+// ancestors returns the categories above the given one, the top one first. It
+// stops at a category met before, should the stored parents make a cycle.
+func (t *categoryTree) ancestors(id uint) []*dao.Category {
+	seen := map[uint]bool{id: true}
+	var items []*dao.Category
+	for item := t.byID[id]; item != nil && item.ParentID != nil; {
+		item = t.byID[*item.ParentID]
+		if item == nil || seen[item.ID] {
+			break
+		}
+		seen[item.ID] = true
+		items = append([]*dao.Category{item}, items...)
+	}
+
+	return items
+}
+
+// categoryCount is the number of books filed under a category and under it
+// or any descendant. A book filed under several categories of a subtree is
+// counted once.
+type categoryCount struct {
+	books int
+	total int
+}
+
+type categoryCounts map[uint]categoryCount
+
+func (t *categoryTree) countBooks(links []dao.BookCategory) categoryCounts {
+	direct := make(map[uint][]uint)
+	for _, link := range links {
+		direct[link.CategoryID] = append(direct[link.CategoryID], link.BookID)
+	}
+
+	counts := make(categoryCounts, len(t.byID))
+	var walk func(id uint) map[uint]bool
+	walk = func(id uint) map[uint]bool {
+		books := make(map[uint]bool, len(direct[id]))
+		for _, bookID := range direct[id] {
+			books[bookID] = true
+		}
+		for _, childID := range t.children[id] {
+			for bookID := range walk(childID) {
+				books[bookID] = true
+			}
+		}
+		counts[id] = categoryCount{books: len(direct[id]), total: len(books)}
+		return books
+	}
+	for _, id := range t.children[0] {
+		walk(id)
+	}
+
+	return counts
+}
+
+func (c categoryCounts) resp(item *dao.Category) dto.CategoryResp {
+	var resp dto.CategoryResp
+	resp.FromEntity(item)
+	resp.BookCount = c[item.ID].books
+	resp.TotalBookCount = c[item.ID].total
+
+	return resp
+}
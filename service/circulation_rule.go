@@ -0,0 +1,159 @@
+package service
+
+import (
+	"base-gin/config"
+	"base-gin/constant"
+	"base-gin/domain/dao"
+	"base-gin/domain/dto"
+	"base-gin/exception"
+	"base-gin/repository"
+	"context"
+	"errors"
+	"fmt"
+)
+
+type CirculationRuleService struct {
+	repo *repository.CirculationRuleRepository
+}
+
+func NewCirculationRuleService(ruleRepo *repository.CirculationRuleRepository) *CirculationRuleService {
+	return &CirculationRuleService{repo: ruleRepo}
+}
+
+func (s *CirculationRuleService) Create(ctx context.Context, params *dto.CirculationRuleReq) (dto.CirculationRuleResp, error) {
+	var resp dto.CirculationRuleResp
+
+	newItem := params.ToEntity()
+	if err := s.checkScope(ctx, &newItem); err != nil {
+		return resp, err
+	}
+	if err := s.repo.Create(ctx, &newItem); err != nil {
+		return resp, err
+	}
+
+	resp.FromEntity(&newItem)
+	return resp, nil
+}
+
+func (s *CirculationRuleService) GetList(ctx context.Context) ([]dto.CirculationRuleResp, error) {
+	items, err := s.repo.GetList(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := make([]dto.CirculationRuleResp, len(items))
+	for i := range items {
+		resp[i].FromEntity(&items[i])
+	}
+
+	return resp, nil
+}
+
+func (s *CirculationRuleService) GetByID(ctx context.Context, id uint) (dto.CirculationRuleResp, error) {
+	var resp dto.CirculationRuleResp
+
+	item, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return resp, err
+	}
+
+	resp.FromEntity(&item)
+	return resp, nil
+}
+
+func (s *CirculationRuleService) Update(ctx context.Context, params *dto.CirculationRuleReq) (dto.CirculationRuleResp, error) {
+	var resp dto.CirculationRuleResp
+
+	item := params.ToEntity()
+	if _, err := s.repo.GetByID(ctx, item.ID); err != nil {
+		return resp, err
+	}
+	if err := s.checkScope(ctx, &item); err != nil {
+		return resp, err
+	}
+	if err := s.repo.Update(ctx, &item); err != nil {
+		return resp, err
+	}
+
+	resp.FromEntity(&item)
+	return resp, nil
+}
+
+func (s *CirculationRuleService) Delete(ctx context.Context, id uint) error {
+	return s.repo.Delete(ctx, id)
+}
+
+// checkScope makes sure no other rule covers the same patron category and
+// item type.
+func (s *CirculationRuleService) checkScope(ctx context.Context, item *dao.CirculationRule) error {
+	existing, err := s.repo.GetByScope(ctx, item.PatronCategory, item.ItemType)
+	if errors.Is(err, exception.ErrDataNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if existing.ID != item.ID {
+		return exception.ErrRuleConflict
+	}
+
+	return nil
+}
+
+// resolveRule picks the most specific rule for the patron category and item
+// type, falling back on the configured defaults when none applies.
+func resolveRule(
+	ctx context.Context,
+	repos *repository.Repositories,
+	cfg *config.CirculationConfig,
+	patronCategory, itemType string,
+) (dao.CirculationRule, error) {
+	rule := dao.CirculationRule{
+		PatronCategory: constant.RuleWildcard,
+		ItemType:       constant.RuleWildcard,
+		MaxLoans:       cfg.MaxLoans,
+		LoanPeriodDays: cfg.LoanPeriodDays,
+		MaxRenewals:    cfg.MaxRenewals,
+	}
+
+	items, err := repos.Rule.GetMatching(ctx, patronCategory, itemType)
+	if err != nil {
+		return rule, err
+	}
+
+	for i := range items {
+		if rule.ID == 0 || items[i].Specificity() > rule.Specificity() {
+			rule = items[i]
+		}
+	}
+
+	return rule, nil
+}
+
+func newPolicyViolation(rule *dao.CirculationRule, code, message string) *exception.PolicyViolation {
+	violation := exception.PolicyViolation{Code: code, Message: message}
+	if rule.ID != 0 {
+		violation.RuleID = &rule.ID
+	}
+
+	return &violation
+}
+
+// checkLoanLimit blocks a checkout that would take the person over the
+// number of concurrent loans allowed by rule.
+func checkLoanLimit(ctx context.Context, repos *repository.Repositories, rule *dao.CirculationRule, personID uint) error {
+	if rule.MaxLoans <= 0 {
+		return nil
+	}
+
+	count, err := repos.Borrowing.CountActiveByPersonID(ctx, personID, rule.ItemType)
+	if err != nil {
+		return err
+	}
+	if count >= int64(rule.MaxLoans) {
+		return newPolicyViolation(rule, exception.PolicyLoanLimit,
+			fmt.Sprintf("jumlah pinjaman aktif sudah mencapai batas %d", rule.MaxLoans))
+	}
+
+	return nil
+}
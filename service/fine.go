@@ -0,0 +1,176 @@
+package service
+
+import (
+	"base-gin/config"
+	"base-gin/domain"
+	"base-gin/domain/dao"
+	"base-gin/domain/dto"
+	"base-gin/exception"
+	"base-gin/repository"
+	"context"
+)
+
+// FinePolicy decides the fine of a late return.
+type FinePolicy struct {
+	PerDay    int64
+	MaxAmount int64 // 0 for no cap
+	GraceDays int
+}
+
+func NewFinePolicy(cfg *config.CirculationConfig) FinePolicy {
+	return FinePolicy{
+		PerDay:    cfg.FinePerDay,
+		MaxAmount: cfg.FineMaxAmount,
+		GraceDays: cfg.FineGraceDays,
+	}
+}
+
+// Calculate returns the fine for returning a borrowing daysLate days past its
+// due date. The grace days are never charged, so a return within them is not
+// fined at all.
+func (p FinePolicy) Calculate(daysLate int) int64 {
+	days := daysLate - p.GraceDays
+	if days <= 0 || p.PerDay <= 0 {
+		return 0
+	}
+
+	amount := int64(days) * p.PerDay
+	if p.MaxAmount > 0 && amount > p.MaxAmount {
+		return p.MaxAmount
+	}
+
+	return amount
+}
+
+type FineService struct {
+	txm        *repository.TxManager
+	repo       *repository.FineRepository
+	personRepo *repository.PersonRepository
+}
+
+func NewFineService(
+	txManager *repository.TxManager,
+	fineRepo *repository.FineRepository,
+	personRepo *repository.PersonRepository,
+) *FineService {
+	return &FineService{
+		txm:        txManager,
+		repo:       fineRepo,
+		personRepo: personRepo,
+	}
+}
+
+func (s *FineService) GetOutstanding(ctx context.Context) ([]dto.FineBalanceResp, error) {
+	items, err := s.repo.GetOutstanding(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := make([]dto.FineBalanceResp, len(items))
+	for i := range items {
+		resp[i].FromEntity(&items[i])
+	}
+
+	return resp, nil
+}
+
+// GetLedger returns the balance and entries of a person. When memberAccountID
+// is set only the person of that account may be read.
+func (s *FineService) GetLedger(ctx context.Context, personID uint, memberAccountID *uint) (dto.FineLedgerResp, error) {
+	resp := dto.FineLedgerResp{PersonID: personID}
+
+	person, err := s.personRepo.GetByID(ctx, personID)
+	if err != nil {
+		return resp, err
+	}
+	if memberAccountID != nil &&
+		(person.AccountID == nil || *person.AccountID != *memberAccountID) {
+		return resp, exception.ErrForbidden
+	}
+
+	resp.Balance, err = s.repo.GetBalance(ctx, personID)
+	if err != nil {
+		return resp, err
+	}
+
+	items, err := s.repo.GetListByPersonID(ctx, personID)
+	if err != nil {
+		return resp, err
+	}
+
+	resp.Entries = make([]dto.FineEntryResp, len(items))
+	for i := range items {
+		resp.Entries[i].FromEntity(&items[i])
+	}
+
+	return resp, nil
+}
+
+func (s *FineService) RecordPayment(ctx context.Context, params *dto.FinePaymentReq) error {
+	entry := params.ToEntity()
+	return s.txm.WithTx(ctx, func(repos *repository.Repositories) error {
+		return s.settle(ctx, repos, &entry)
+	})
+}
+
+// Waive writes off part of a person's fines. The borrowing, when given, must
+// be one of the person's.
+func (s *FineService) Waive(ctx context.Context, params *dto.FineWaiverReq) error {
+	entry := params.ToEntity()
+	return s.txm.WithTx(ctx, func(repos *repository.Repositories) error {
+		if entry.BorrowingID != nil {
+			borrowing, err := repos.Borrowing.GetByID(ctx, *entry.BorrowingID)
+			if err != nil {
+				return err
+			}
+			if borrowing.PersonID != entry.PersonID {
+				return exception.ErrDataNotFound
+			}
+		}
+
+		return s.settle(ctx, repos, &entry)
+	})
+}
+
+// settle records a payment or waiver, which may not exceed the balance. The
+// person is locked so concurrent settlements can not both pass the check.
+func (s *FineService) settle(ctx context.Context, repos *repository.Repositories, entry *dao.FineEntry) error {
+	if _, err := repos.Person.LockByID(ctx, entry.PersonID); err != nil {
+		return err
+	}
+
+	balance, err := repos.Fine.GetBalance(ctx, entry.PersonID)
+	if err != nil {
+		return err
+	}
+	if entry.Amount > balance {
+		return exception.ErrFineExceedsBalance
+	}
+
+	return repos.Fine.Create(ctx, entry)
+}
+
+// chargeLateReturn adds the fine of a returned borrowing to the ledger of its
+// person. A borrowing is only returned once, and the ledger is checked as well
+// so that a charge already recorded for the borrowing is never doubled.
+func chargeLateReturn(ctx context.Context, repos *repository.Repositories, policy FinePolicy, item *dao.Borrowing) error {
+	amount := policy.Calculate(item.DaysLate())
+	if amount <= 0 {
+		return nil
+	}
+
+	charged, err := repos.Fine.HasChargeByBorrowingID(ctx, item.ID)
+	if err != nil {
+		return err
+	}
+	if charged {
+		return nil
+	}
+
+	return repos.Fine.Create(ctx, &dao.FineEntry{
+		PersonID:    item.PersonID,
+		BorrowingID: &item.ID,
+		Type:        domain.FineCharge,
+		Amount:      amount,
+	})
+}
@@ -0,0 +1,337 @@
+package service
+
+import (
+	"base-gin/config"
+	"base-gin/domain"
+	"base-gin/domain/dao"
+	"base-gin/domain/dto"
+	"base-gin/exception"
+	"base-gin/repository"
+	"context"
+	"errors"
+	"time"
+)
+
+type HoldService struct {
+	cfg        *config.Config
+	txm        *repository.TxManager
+	repo       *repository.HoldRepository
+	personRepo *repository.PersonRepository
+}
+
+func NewHoldService(
+	cfg *config.Config,
+	txManager *repository.TxManager,
+	holdRepo *repository.HoldRepository,
+	personRepo *repository.PersonRepository,
+) *HoldService {
+	return &HoldService{
+		cfg:        cfg,
+		txm:        txManager,
+		repo:       holdRepo,
+		personRepo: personRepo,
+	}
+}
+
+// Place queues a person for a book that is out. Members may only queue
+// themselves, while staff queue the given person or, without one, themselves.
+func (s *HoldService) Place(ctx context.Context, params *dto.HoldReq, memberAccountID *uint) (dto.HoldResp, error) {
+	var resp dto.HoldResp
+
+	if memberAccountID != nil || params.PersonID == 0 {
+		person, err := s.personRepo.GetByAccountID(ctx, params.AccountID)
+		if err != nil {
+			return resp, err
+		}
+		if params.PersonID != 0 && params.PersonID != person.ID {
+			return resp, exception.ErrForbidden
+		}
+		params.PersonID = person.ID
+	} else if _, err := s.personRepo.GetByID(ctx, params.PersonID); err != nil {
+		return resp, err
+	}
+
+	newItem := params.ToEntity()
+	newItem.Status = domain.HoldWaiting
+
+	err := s.txm.WithTx(ctx, func(repos *repository.Repositories) error {
+		// The lock serialises holds with checkouts & returns of the book.
+		if _, err := repos.Book.LockByID(ctx, newItem.BookID); err != nil {
+			return err
+		}
+		err := expireBookHolds(ctx, repos, s.cfg.Circulation.HoldPickupDays, newItem.BookID, time.Now())
+		if err != nil {
+			return err
+		}
+
+		queued, err := repos.Hold.HasActive(ctx, newItem.BookID, newItem.PersonID)
+		if err != nil {
+			return err
+		}
+		if queued {
+			return exception.ErrHoldConflict
+		}
+
+		available, err := isBookAvailable(ctx, repos, newItem.BookID)
+		if err != nil {
+			return err
+		}
+		if available {
+			return exception.ErrBookAvailable
+		}
+
+		return repos.Hold.Create(ctx, &newItem)
+	})
+	if err != nil {
+		return resp, err
+	}
+
+	items, err := s.withPositions(ctx, []dao.Hold{newItem})
+	if err != nil {
+		return resp, err
+	}
+
+	return items[0], nil
+}
+
+// GetList returns the holds matching params in queue order. Members only get
+// their own.
+func (s *HoldService) GetList(ctx context.Context, params *dto.HoldFilter, memberAccountID *uint) ([]dto.HoldResp, dto.ListMeta, error) {
+	if memberAccountID != nil {
+		person, err := s.personRepo.GetByAccountID(ctx, *memberAccountID)
+		if errors.Is(err, exception.ErrUserNotFound) {
+			return []dto.HoldResp{}, dto.ListMeta{}, nil
+		}
+		if err != nil {
+			return nil, dto.ListMeta{}, err
+		}
+		params.PersonID = person.ID
+	}
+
+	items, meta, err := s.repo.GetList(ctx, params)
+	if err != nil {
+		return nil, meta, err
+	}
+
+	resp, err := s.withPositions(ctx, items)
+	return resp, meta, err
+}
+
+// Cancel withdraws a hold. A copy set aside for it goes to the next person in
+// the queue. When memberAccountID is set, holds of other persons are reported
+// as not found.
+func (s *HoldService) Cancel(ctx context.Context, id uint, memberAccountID *uint) error {
+	return s.txm.WithTx(ctx, func(repos *repository.Repositories) error {
+		item, err := repos.Hold.LockByID(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if memberAccountID != nil {
+			person, err := repos.Person.GetByAccountID(ctx, *memberAccountID)
+			if err != nil && !errors.Is(err, exception.ErrUserNotFound) {
+				return err
+			}
+			if err != nil || person.ID != item.PersonID {
+				return exception.ErrDataNotFound
+			}
+		}
+
+		if !item.IsActive() {
+			return exception.ErrHoldInactive
+		}
+
+		wasReady := item.Status == domain.HoldReady
+		item.Status = domain.HoldCancelled
+		if err := repos.Hold.Update(ctx, &item); err != nil {
+			return err
+		}
+		if !wasReady {
+			return nil
+		}
+
+		return releaseForHolds(ctx, repos, s.cfg.Circulation.HoldPickupDays, item.BookID, item.BookCopyID, time.Now())
+	})
+}
+
+// ExpireDue expires the holds not picked up in time, passing their copies on
+// to the next in the queue, and returns how many expired.
+func (s *HoldService) ExpireDue(ctx context.Context) (int, error) {
+	var count int
+	err := s.txm.WithTx(ctx, func(repos *repository.Repositories) error {
+		var err error
+		count, err = expireHolds(ctx, repos, s.cfg.Circulation.HoldPickupDays, time.Now())
+		return err
+	})
+
+	return count, err
+}
+
+// withPositions converts holds into responses, numbering waiting holds by
+// their place in the queue of their book.
+func (s *HoldService) withPositions(ctx context.Context, items []dao.Hold) ([]dto.HoldResp, error) {
+	resp := make([]dto.HoldResp, len(items))
+
+	var bookIDs []uint
+	for i := range items {
+		resp[i].FromEntity(&items[i])
+		if items[i].Status == domain.HoldWaiting {
+			bookIDs = append(bookIDs, items[i].BookID)
+		}
+	}
+	if len(bookIDs) == 0 {
+		return resp, nil
+	}
+
+	waiting, err := s.repo.GetWaitingByBookIDs(ctx, bookIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	positions := make(map[uint]int, len(waiting))
+	counts := make(map[uint]int)
+	for _, item := range waiting {
+		counts[item.BookID]++
+		positions[item.ID] = counts[item.BookID]
+	}
+	for i := range resp {
+		resp[i].Position = positions[resp[i].ID]
+	}
+
+	return resp, nil
+}
+
+// isBookAvailable tells whether the book can be lent out right away, in which
+// case there is no point in queueing for it.
+func isBookAvailable(ctx context.Context, repos *repository.Repositories, bookID uint) (bool, error) {
+	counts, err := repos.BookCopy.CountByBookIDs(ctx, []uint{bookID})
+	if err != nil {
+		return false, err
+	}
+	if count, ok := counts[bookID]; ok {
+		return count.Available > 0, nil
+	}
+
+	borrowed, err := repos.Borrowing.HasActiveByBookID(ctx, bookID)
+	if err != nil || borrowed {
+		return false, err
+	}
+	onHold, err := repos.Hold.HasByBookIDAndStatus(ctx, bookID, domain.HoldReady)
+	return !onHold, err
+}
+
+// releaseForHolds hands a returned copy of a book to the first person in its
+// queue, setting it aside for pickup, or makes it available when nobody is
+// waiting. Books without copies pass a nil copy.
+func releaseForHolds(
+	ctx context.Context,
+	repos *repository.Repositories,
+	pickupDays int,
+	bookID uint,
+	bookCopyID *uint,
+	now time.Time,
+) error {
+	next, err := repos.Hold.LockNextWaiting(ctx, bookID)
+	if errors.Is(err, exception.ErrDataNotFound) {
+		if bookCopyID == nil {
+			return nil
+		}
+		return repos.BookCopy.UpdateStatus(ctx, *bookCopyID, domain.CopyAvailable)
+	}
+	if err != nil {
+		return err
+	}
+
+	expiresAt := now.AddDate(0, 0, pickupDays)
+	next.Status = domain.HoldReady
+	next.BookCopyID = bookCopyID
+	next.ReadyAt = &now
+	next.ExpiresAt = &expiresAt
+	if err := repos.Hold.Update(ctx, &next); err != nil {
+		return err
+	}
+	if bookCopyID == nil {
+		return nil
+	}
+
+	return repos.BookCopy.UpdateStatus(ctx, *bookCopyID, domain.CopyOnHold)
+}
+
+// claimHold fulfils the person's hold ready for pickup of the book being lent
+// out. The copy set aside for the hold is lent out unless another copy was
+// asked for, in which case it goes to the next in the queue.
+func claimHold(ctx context.Context, repos *repository.Repositories, pickupDays int, item *dao.Borrowing) error {
+	hold, err := repos.Hold.LockReady(ctx, item.BookID, item.PersonID)
+	if errors.Is(err, exception.ErrDataNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	hold.Status = domain.HoldFulfilled
+	if err := repos.Hold.Update(ctx, &hold); err != nil {
+		return err
+	}
+	if hold.BookCopyID == nil {
+		return nil
+	}
+
+	if item.BookCopyID == nil || *item.BookCopyID == *hold.BookCopyID {
+		// Made available again so it is lent out like any other copy.
+		item.BookCopyID = hold.BookCopyID
+		return repos.BookCopy.UpdateStatus(ctx, *hold.BookCopyID, domain.CopyAvailable)
+	}
+
+	return releaseForHolds(ctx, repos, pickupDays, item.BookID, hold.BookCopyID, time.Now())
+}
+
+// expireHolds expires the holds not picked up before now and passes their
+// copies on.
+func expireHolds(ctx context.Context, repos *repository.Repositories, pickupDays int, now time.Time) (int, error) {
+	items, err := repos.Hold.LockExpired(ctx, now)
+	if err != nil {
+		return 0, err
+	}
+
+	return len(items), expireLockedHolds(ctx, repos, pickupDays, items, now)
+}
+
+// expireBookHolds is expireHolds for a single book, so that a checkout or a
+// new hold does not wait for the scheduled expiry.
+func expireBookHolds(
+	ctx context.Context,
+	repos *repository.Repositories,
+	pickupDays int,
+	bookID uint,
+	now time.Time,
+) error {
+	items, err := repos.Hold.LockExpiredByBookID(ctx, bookID, now)
+	if err != nil {
+		return err
+	}
+
+	return expireLockedHolds(ctx, repos, pickupDays, items, now)
+}
+
+func expireLockedHolds(
+	ctx context.Context,
+	repos *repository.Repositories,
+	pickupDays int,
+	items []dao.Hold,
+	now time.Time,
+) error {
+	for i := range items {
+		items[i].Status = domain.HoldExpired
+		if err := repos.Hold.Update(ctx, &items[i]); err != nil {
+			return err
+		}
+
+		err := releaseForHolds(ctx, repos, pickupDays, items[i].BookID, items[i].BookCopyID, now)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
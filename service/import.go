@@ -0,0 +1,375 @@
+package service
+
+import (
+	"base-gin/domain"
+	"base-gin/domain/dao"
+	"base-gin/domain/dto"
+	"base-gin/exception"
+	"base-gin/repository"
+	"context"
+	"encoding/csv"
+	"errors"
+	"io"
+	"strings"
+
+	"github.com/gin-gonic/gin/binding"
+)
+
+// Columns of the catalog CSV.
+const (
+	importColTitle         = "title"
+	importColSubtitle      = "subtitle"
+	importColISBN          = "isbn"
+	importColItemType      = "item_type"
+	importColPublisher     = "publisher"
+	importColPublisherCity = "publisher_city"
+	importColAuthors       = "authors"
+)
+
+var importColumns = map[string]bool{
+	importColTitle:         true,
+	importColSubtitle:      false,
+	importColISBN:          false,
+	importColItemType:      false,
+	importColPublisher:     true,
+	importColPublisherCity: false,
+	importColAuthors:       true,
+}
+
+// errDryRun rolls back the import of a dry run.
+var errDryRun = errors.New("dry run")
+
+// ImportService imports the catalog from CSV, a book per row. Authors and
+// publishers are looked up by name and created when unknown.
+type ImportService struct {
+	txm    *repository.TxManager
+	search *SearchService
+}
+
+func NewImportService(txManager *repository.TxManager, searchService *SearchService) *ImportService {
+	return &ImportService{txm: txManager, search: searchService}
+}
+
+// importRow is a row of the catalog CSV. Authors are separated by semicolons,
+// each with its role in parentheses when not the author, e.g.
+// "Pramoedya Ananta Toer; Max Lane (translator)".
+type importRow struct {
+	line int
+	book dto.BookDTO
+	// publisher is only created when no publisher has its name.
+	publisher dto.PublisherCreateReq
+	authors   []importAuthor
+}
+
+type importAuthor struct {
+	name string
+	role string
+}
+
+// importRowError is why a row is skipped, as opposed to an error failing the
+// whole import. Field is the column at fault, or the one whose validation
+// failed.
+type importRowError struct {
+	field string
+	err   error
+}
+
+func (e *importRowError) Error() string {
+	return e.err.Error()
+}
+
+// importNames maps the lower-cased names of the authors or publishers met so
+// far to their ID.
+type importNames map[string]uint
+
+// ImportCatalog creates a book for each valid row of the CSV, all in one
+// transaction. Rows failing validation are reported and skipped without
+// undoing the others. On a dry run the transaction is rolled back.
+func (s *ImportService) ImportCatalog(ctx context.Context, r io.Reader, dryRun bool) (dto.CatalogImportResp, error) {
+	resp := dto.CatalogImportResp{
+		DryRun:  dryRun,
+		BookIDs: []uint{},
+		Errors:  []dto.CatalogImportRowResp{},
+	}
+
+	rows, err := readImportRows(r)
+	if err != nil {
+		return resp, err
+	}
+	resp.Rows = len(rows)
+
+	var bookIDs []uint
+	err = s.txm.WithTx(ctx, func(repos *repository.Repositories) error {
+		publishers := make(importNames)
+		authors := make(importNames)
+		for i := range rows {
+			row := &rows[i]
+			newPublishers := make(importNames)
+			newAuthors := make(importNames)
+
+			var bookID uint
+			err := repos.Savepoint(ctx, func(repos *repository.Repositories) error {
+				var err error
+				bookID, err = importBook(ctx, repos, row,
+					publishers, newPublishers, authors, newAuthors)
+				return err
+			})
+			var rowErr *importRowError
+			if errors.As(err, &rowErr) {
+				resp.Failed++
+				resp.Errors = append(resp.Errors, dto.CatalogImportRowResp{
+					Line:  row.line,
+					Field: rowErr.field,
+					Err:   rowErr.err,
+				})
+				continue
+			}
+			if err != nil {
+				return err
+			}
+
+			for name, id := range newPublishers {
+				publishers[name] = id
+			}
+			for name, id := range newAuthors {
+				authors[name] = id
+			}
+			resp.PublishersCreated += len(newPublishers)
+			resp.AuthorsCreated += len(newAuthors)
+			resp.Imported++
+			bookIDs = append(bookIDs, bookID)
+		}
+
+		if dryRun {
+			return errDryRun
+		}
+		return nil
+	})
+	if errors.Is(err, errDryRun) {
+		return resp, nil
+	}
+	if err != nil {
+		return resp, err
+	}
+
+	if len(bookIDs) > 0 {
+		resp.BookIDs = bookIDs
+		s.search.syncBooks(ctx, bookIDs...)
+	}
+	return resp, nil
+}
+
+// importBook creates the book of a row along with its unknown publisher and
+// authors, recording those in newPublishers & newAuthors.
+func importBook(
+	ctx context.Context,
+	repos *repository.Repositories,
+	row *importRow,
+	publishers, newPublishers importNames,
+	authors, newAuthors importNames,
+) (uint, error) {
+	publisherID, err := importPublisher(ctx, repos, &row.publisher, publishers, newPublishers)
+	if err != nil {
+		return 0, err
+	}
+	row.book.PublisherID = publisherID
+
+	if len(row.authors) == 0 {
+		return 0, &importRowError{field: importColAuthors, err: exception.ErrImportNoAuthor}
+	}
+	row.book.Contributors = make([]dto.BookContributorReq, len(row.authors))
+	for i, author := range row.authors {
+		id, err := importAuthorID(ctx, repos, author.name, authors, newAuthors)
+		if err != nil {
+			return 0, err
+		}
+		row.book.Contributors[i] = dto.BookContributorReq{AuthorID: id, Role: author.role}
+	}
+
+	if err := binding.Validator.ValidateStruct(&row.book); err != nil {
+		return 0, &importRowError{err: err}
+	}
+
+	item := row.book.ToEntity()
+	if item.ISBN13 != nil {
+		_, err := repos.Book.GetByISBNUnscoped(ctx, *item.ISBN13)
+		if err == nil {
+			return 0, &importRowError{field: importColISBN, err: exception.ErrISBNConflict}
+		}
+		if !errors.Is(err, exception.ErrDataNotFound) {
+			return 0, err
+		}
+	}
+	if err := repos.Book.Create(ctx, &item); err != nil {
+		return 0, err
+	}
+
+	return item.ID, nil
+}
+
+func importPublisher(
+	ctx context.Context,
+	repos *repository.Repositories,
+	params *dto.PublisherCreateReq,
+	known, created importNames,
+) (uint, error) {
+	key := strings.ToLower(params.Name)
+	if id, ok := known[key]; ok {
+		return id, nil
+	}
+	if params.Name != "" {
+		item, err := repos.Publisher.GetByName(ctx, params.Name)
+		if err == nil {
+			known[key] = item.ID
+			return item.ID, nil
+		}
+		if !errors.Is(err, exception.ErrDataNotFound) {
+			return 0, err
+		}
+	}
+
+	if err := binding.Validator.ValidateStruct(params); err != nil {
+		return 0, &importRowError{field: importColPublisher, err: err}
+	}
+	item := params.ToEntity()
+	if err := repos.Publisher.Create(ctx, &item); err != nil {
+		return 0, err
+	}
+	created[key] = item.ID
+
+	return item.ID, nil
+}
+
+func importAuthorID(
+	ctx context.Context,
+	repos *repository.Repositories,
+	name string,
+	known, created importNames,
+) (uint, error) {
+	key := strings.ToLower(name)
+	if id, ok := known[key]; ok {
+		return id, nil
+	}
+	if id, ok := created[key]; ok {
+		return id, nil
+	}
+	item, err := repos.Author.GetByName(ctx, name)
+	if err == nil {
+		known[key] = item.ID
+		return item.ID, nil
+	}
+	if !errors.Is(err, exception.ErrDataNotFound) {
+		return 0, err
+	}
+
+	params := dto.AuthorDTO{FullName: name}
+	if err := binding.Validator.ValidateStruct(&params); err != nil {
+		return 0, &importRowError{field: importColAuthors, err: err}
+	}
+	author := dao.Author{FullName: params.FullName}
+	if err := repos.Author.Create(ctx, &author); err != nil {
+		return 0, err
+	}
+	created[key] = author.ID
+
+	return author.ID, nil
+}
+
+// readImportRows reads the rows of the CSV by the columns of its header, in
+// any order and case.
+func readImportRows(r io.Reader) ([]importRow, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, exception.ErrImportInvalid
+	}
+	cols := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		if _, ok := importColumns[name]; !ok {
+			return nil, exception.ErrImportHeader
+		}
+		if _, ok := cols[name]; ok {
+			return nil, exception.ErrImportHeader
+		}
+		cols[name] = i
+	}
+	for name, required := range importColumns {
+		if _, ok := cols[name]; required && !ok {
+			return nil, exception.ErrImportHeader
+		}
+	}
+
+	var rows []importRow
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, exception.ErrImportInvalid
+		}
+		if strings.Join(record, "") == "" {
+			continue
+		}
+		line, _ := reader.FieldPos(0)
+
+		value := func(name string) string {
+			i, ok := cols[name]
+			if !ok || i >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[i])
+		}
+		optional := func(name string) *string {
+			if v := value(name); v != "" {
+				return &v
+			}
+			return nil
+		}
+
+		rows = append(rows, importRow{
+			line: line,
+			book: dto.BookDTO{
+				Title:    value(importColTitle),
+				Subtitle: optional(importColSubtitle),
+				ISBN:     optional(importColISBN),
+				ItemType: value(importColItemType),
+			},
+			publisher: dto.PublisherCreateReq{
+				Name: value(importColPublisher),
+				City: value(importColPublisherCity),
+			},
+			authors: parseImportAuthors(value(importColAuthors)),
+		})
+	}
+	if len(rows) == 0 {
+		return nil, exception.ErrImportInvalid
+	}
+
+	return rows, nil
+}
+
+func parseImportAuthors(s string) []importAuthor {
+	var items []importAuthor
+	for _, name := range strings.Split(s, ";") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+
+		role := string(domain.ContributorAuthor)
+		if strings.HasSuffix(name, ")") {
+			if i := strings.LastIndex(name, "("); i > 0 {
+				role = strings.ToLower(strings.TrimSpace(name[i+1 : len(name)-1]))
+				name = strings.TrimSpace(name[:i])
+			}
+		}
+		items = append(items, importAuthor{name: name, role: role})
+	}
+
+	return items
+}
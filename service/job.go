@@ -0,0 +1,233 @@
+package service
+
+import (
+	"base-gin/config"
+	"base-gin/domain/dto"
+	"base-gin/exception"
+	"base-gin/repository"
+	"base-gin/scheduler"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+// Names of the jobs run by the scheduler.
+const (
+	JobSendReminders  = "send_reminders"
+	JobExpireHolds    = "expire_holds"
+	JobOverdueSummary = "overdue_summary"
+	JobPurgeExpired   = "purge_expired"
+	JobReindexSearch  = "reindex_search"
+)
+
+type JobService struct {
+	cfg               *config.SchedulerConfig
+	scheduler         *scheduler.Scheduler
+	repo              *repository.JobRunRepository
+	refreshTokenRepo  *repository.RefreshTokenRepository
+	revokedTokenRepo  *repository.RevokedTokenRepository
+	loginAttemptStore repository.LoginAttemptStore
+}
+
+func NewJobService(
+	cfg *config.Config,
+	jobRunRepo *repository.JobRunRepository,
+	refreshTokenRepo *repository.RefreshTokenRepository,
+	revokedTokenRepo *repository.RevokedTokenRepository,
+	loginAttemptStore repository.LoginAttemptStore,
+) *JobService {
+	return &JobService{
+		cfg: &cfg.Scheduler,
+		scheduler: scheduler.New(
+			jobRunRepo, time.Duration(cfg.Scheduler.JobTimeout)*time.Second, schedulerInstance(&cfg.Scheduler)),
+		repo:              jobRunRepo,
+		refreshTokenRepo:  refreshTokenRepo,
+		revokedTokenRepo:  revokedTokenRepo,
+		loginAttemptStore: loginAttemptStore,
+	}
+}
+
+// schedulerInstance names the scheduler of this process, by default after the
+// host it runs on.
+func schedulerInstance(cfg *config.SchedulerConfig) string {
+	if cfg.Instance != "" {
+		return cfg.Instance
+	}
+	host, err := os.Hostname()
+	if err != nil {
+		exception.LogError(err, "JobService.schedulerInstance")
+	}
+
+	return host
+}
+
+func (s *JobService) Register(jobs ...scheduler.Job) error {
+	for _, job := range jobs {
+		if err := s.scheduler.Register(job); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Start runs the jobs on their spec until Stop is called.
+func (s *JobService) Start(ctx context.Context) error {
+	return s.scheduler.Start(ctx)
+}
+
+// Stop waits for the running jobs until ctx is done, then cancels them.
+func (s *JobService) Stop(ctx context.Context) error {
+	return s.scheduler.Stop(ctx)
+}
+
+// GetList describes the jobs along with their last run.
+func (s *JobService) GetList(ctx context.Context) ([]dto.JobResp, error) {
+	jobs := s.scheduler.Jobs()
+
+	resp := make([]dto.JobResp, len(jobs))
+	for i, job := range jobs {
+		resp[i] = dto.JobResp{
+			Name:    job.Name,
+			Spec:    job.Spec,
+			Running: job.Running,
+			NextRun: job.NextRun,
+		}
+
+		run, err := s.repo.GetLatestByJobName(ctx, job.Name)
+		if err != nil {
+			if errors.Is(err, exception.ErrDataNotFound) {
+				continue
+			}
+			return nil, err
+		}
+		resp[i].LastRun = &dto.JobRunResp{}
+		resp[i].LastRun.FromEntity(&run)
+	}
+
+	return resp, nil
+}
+
+func (s *JobService) GetRuns(ctx context.Context, params *dto.JobRunFilter) ([]dto.JobRunResp, error) {
+	items, err := s.repo.GetList(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := make([]dto.JobRunResp, len(items))
+	for i := range items {
+		resp[i].FromEntity(&items[i])
+	}
+
+	return resp, nil
+}
+
+// Trigger starts a run of the job right away. The run is returned while it is
+// still running.
+func (s *JobService) Trigger(ctx context.Context, name string) (dto.JobRunResp, error) {
+	var resp dto.JobRunResp
+
+	run, err := s.scheduler.Trigger(ctx, name)
+	if err != nil {
+		return resp, err
+	}
+	resp.FromEntity(&run)
+
+	return resp, nil
+}
+
+// PurgeExpired deletes the tokens and login counters that have expired along
+// with the job runs older than the retention days.
+func (s *JobService) PurgeExpired(ctx context.Context) (string, error) {
+	revoked, err := s.revokedTokenRepo.DeleteExpired(ctx)
+	if err != nil {
+		return "", err
+	}
+	refresh, err := s.refreshTokenRepo.DeleteExpired(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	// The in-memory store purges its counters by itself.
+	var attempts int64
+	if store, ok := s.loginAttemptStore.(*repository.DBLoginAttemptStore); ok {
+		if attempts, err = store.DeleteExpired(ctx); err != nil {
+			return "", err
+		}
+	}
+
+	runs, err := s.repo.DeleteBefore(ctx, time.Now().AddDate(0, 0, -s.cfg.RunRetentionDays))
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%d token dicabut, %d token refresh, %d percobaan login, %d riwayat job dihapus",
+		revoked, refresh, attempts, runs), nil
+}
+
+// newJobs lists the jobs of the library on their configured spec.
+func newJobs(
+	cfg *config.Config,
+	borrowingService *BorrowingService,
+	holdService *HoldService,
+	notifyService *NotificationService,
+	searchService *SearchService,
+	jobService *JobService,
+) []scheduler.Job {
+	return []scheduler.Job{
+		{
+			Name: JobSendReminders,
+			Spec: cfg.Scheduler.RemindersSpec,
+			Run: func(ctx context.Context) (string, error) {
+				resp, err := notifyService.SendReminders(ctx, time.Now())
+				if err != nil {
+					return "", err
+				}
+				return fmt.Sprintf("%d terkirim, %d gagal, %d dilewati",
+					resp.Sent, resp.Failed, resp.Skipped), nil
+			},
+		},
+		{
+			Name: JobExpireHolds,
+			Spec: cfg.Scheduler.ExpireHoldsSpec,
+			Run: func(ctx context.Context) (string, error) {
+				count, err := holdService.ExpireDue(ctx)
+				if err != nil {
+					return "", err
+				}
+				return fmt.Sprintf("%d antrean kedaluwarsa", count), nil
+			},
+		},
+		{
+			Name: JobOverdueSummary,
+			Spec: cfg.Scheduler.OverdueSummarySpec,
+			Run: func(ctx context.Context) (string, error) {
+				resp, err := borrowingService.SummarizeOverdue(ctx, time.Now())
+				if err != nil {
+					return "", err
+				}
+				return fmt.Sprintf("%d terlambat, %d lebih dari %d hari, terlama %d hari",
+					resp.Overdue, resp.LongOverdue, cfg.Notification.LongOverdueDays,
+					resp.MaxDaysOverdue), nil
+			},
+		},
+		{
+			Name: JobPurgeExpired,
+			Spec: cfg.Scheduler.PurgeSpec,
+			Run:  jobService.PurgeExpired,
+		},
+		{
+			Name: JobReindexSearch,
+			Spec: cfg.Scheduler.ReindexSpec,
+			Run: func(ctx context.Context) (string, error) {
+				count, err := searchService.Rebuild(ctx)
+				if err != nil {
+					return "", err
+				}
+				return fmt.Sprintf("%d buku diindeks", count), nil
+			},
+		},
+	}
+}
@@ -0,0 +1,245 @@
+package service
+
+import (
+	"base-gin/config"
+	"base-gin/domain"
+	"base-gin/domain/dao"
+	"base-gin/domain/dto"
+	"base-gin/exception"
+	"base-gin/notification"
+	"base-gin/repository"
+	"context"
+	"errors"
+	"time"
+)
+
+// deliveryErrorLen is the size of the error column of a delivery.
+const deliveryErrorLen = 255
+
+type NotificationService struct {
+	cfg           *config.NotificationConfig
+	repo          *repository.NotificationRepository
+	borrowingRepo *repository.BorrowingRepository
+	personRepo    *repository.PersonRepository
+	senders       []notification.Sender
+}
+
+func NewNotificationService(
+	cfg *config.Config,
+	notificationRepo *repository.NotificationRepository,
+	borrowingRepo *repository.BorrowingRepository,
+	personRepo *repository.PersonRepository,
+	senders ...notification.Sender,
+) *NotificationService {
+	return &NotificationService{
+		cfg:           &cfg.Notification,
+		repo:          notificationRepo,
+		borrowingRepo: borrowingRepo,
+		personRepo:    personRepo,
+		senders:       senders,
+	}
+}
+
+// SendReminders notifies persons of their loans due within the due-soon days
+// and of those overdue by the long-overdue days or more, through every
+// channel they can be reached on. Each notification goes out once per loan &
+// due date, so renewed loans are reminded again while failed tries are
+// retried on the next run.
+func (s *NotificationService) SendReminders(ctx context.Context, now time.Time) (dto.NotificationRunResp, error) {
+	var resp dto.NotificationRunResp
+	if len(s.senders) == 0 {
+		return resp, nil
+	}
+
+	dueSoon, err := s.borrowingRepo.GetDueBetween(ctx, now, now.AddDate(0, 0, s.cfg.DueSoonDays))
+	if err != nil {
+		return resp, err
+	}
+	overdue, err := s.borrowingRepo.GetOverdue(ctx, now.AddDate(0, 0, -s.cfg.LongOverdueDays))
+	if err != nil {
+		return resp, err
+	}
+
+	prefs := make(map[uint]dao.NotificationPreference)
+	batches := []struct {
+		kind  domain.TypeNotification
+		items []dao.Borrowing
+	}{
+		{domain.NotifyDueSoon, dueSoon},
+		{domain.NotifyLongOverdue, overdue},
+	}
+	for _, batch := range batches {
+		items := batch.items
+		for i := range items {
+			pref, ok := prefs[items[i].PersonID]
+			if !ok {
+				if pref, err = s.getPreference(ctx, items[i].PersonID); err != nil {
+					return resp, err
+				}
+				prefs[items[i].PersonID] = pref
+			}
+
+			if err := s.notify(ctx, batch.kind, &items[i], &pref, now, &resp); err != nil {
+				return resp, err
+			}
+		}
+	}
+
+	return resp, nil
+}
+
+// notify sends the notification about the borrowing through every channel
+// reaching the person, logging each try. Only a failure to read or log stops
+// it; failed sends are logged as such.
+func (s *NotificationService) notify(
+	ctx context.Context,
+	kind domain.TypeNotification,
+	item *dao.Borrowing,
+	pref *dao.NotificationPreference,
+	now time.Time,
+	resp *dto.NotificationRunResp,
+) error {
+	msg, err := notification.Render(kind, domain.TypeLocale(pref.Locale), notification.NewReminderData(item, now))
+	if err != nil {
+		return err
+	}
+	msg.PersonID = item.PersonID
+	msg.BorrowingID = item.ID
+
+	for _, sender := range s.senders {
+		channel := sender.Channel()
+		to, ok := pref.Recipient(channel)
+		if !ok {
+			resp.Skipped++
+			continue
+		}
+
+		sent, err := s.repo.HasSent(ctx, item.ID, kind, channel, item.DueDate)
+		if err != nil {
+			return err
+		}
+		if sent {
+			resp.Skipped++
+			continue
+		}
+
+		msg.To = to
+		delivery := dao.NotificationDelivery{
+			PersonID:    item.PersonID,
+			BorrowingID: item.ID,
+			Kind:        kind,
+			Channel:     channel,
+			DueDate:     item.DueDate,
+			Recipient:   to,
+			Status:      domain.DeliverySent,
+		}
+
+		sendCtx, cancelFunc := context.WithTimeout(ctx, time.Duration(s.cfg.SendTimeout)*time.Second)
+		err = sender.Send(sendCtx, msg)
+		cancelFunc()
+		if err != nil {
+			exception.LogError(err, "NotificationService.notify")
+			errMsg := err.Error()
+			// Cut by characters, as bytes may split one.
+			if r := []rune(errMsg); len(r) > deliveryErrorLen {
+				errMsg = string(r[:deliveryErrorLen])
+			}
+			delivery.Status = domain.DeliveryFailed
+			delivery.Error = &errMsg
+			resp.Failed++
+		} else {
+			resp.Sent++
+		}
+
+		if err := s.repo.CreateDelivery(ctx, &delivery); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// getPreference returns the preference of a person, or the default one when
+// they have not set any.
+func (s *NotificationService) getPreference(ctx context.Context, personID uint) (dao.NotificationPreference, error) {
+	pref, err := s.repo.GetPreference(ctx, personID)
+	if errors.Is(err, exception.ErrDataNotFound) {
+		return dao.NotificationPreference{PersonID: personID, Locale: s.cfg.DefaultLocale}, nil
+	}
+
+	return pref, err
+}
+
+// checkPerson makes sure the person exists and, when memberAccountID is set,
+// is the person of that account.
+func (s *NotificationService) checkPerson(ctx context.Context, personID uint, memberAccountID *uint) error {
+	person, err := s.personRepo.GetByID(ctx, personID)
+	if err != nil {
+		return err
+	}
+	if memberAccountID != nil &&
+		(person.AccountID == nil || *person.AccountID != *memberAccountID) {
+		return exception.ErrForbidden
+	}
+
+	return nil
+}
+
+// GetPreference returns the notification preference of a person. When
+// memberAccountID is set only the person of that account may be read.
+func (s *NotificationService) GetPreference(ctx context.Context, personID uint, memberAccountID *uint) (dto.NotificationPreferenceResp, error) {
+	var resp dto.NotificationPreferenceResp
+	if err := s.checkPerson(ctx, personID, memberAccountID); err != nil {
+		return resp, err
+	}
+
+	pref, err := s.getPreference(ctx, personID)
+	if err != nil {
+		return resp, err
+	}
+
+	resp.FromEntity(&pref)
+	return resp, nil
+}
+
+// UpdatePreference sets the notification preference of a person. When
+// memberAccountID is set only the person of that account may be changed.
+func (s *NotificationService) UpdatePreference(
+	ctx context.Context,
+	params *dto.NotificationPreferenceReq,
+	memberAccountID *uint,
+) (dto.NotificationPreferenceResp, error) {
+	var resp dto.NotificationPreferenceResp
+	if err := s.checkPerson(ctx, params.PersonID, memberAccountID); err != nil {
+		return resp, err
+	}
+
+	pref := params.ToEntity()
+	if err := s.repo.SavePreference(ctx, &pref); err != nil {
+		return resp, err
+	}
+
+	resp.FromEntity(&pref)
+	return resp, nil
+}
+
+// GetDeliveries returns the notifications sent to a person, the most recent
+// first. When memberAccountID is set only the person of that account may be
+// read.
+func (s *NotificationService) GetDeliveries(ctx context.Context, personID uint, memberAccountID *uint) ([]dto.NotificationDeliveryResp, error) {
+	if err := s.checkPerson(ctx, personID, memberAccountID); err != nil {
+		return nil, err
+	}
+
+	items, err := s.repo.GetDeliveriesByPersonID(ctx, personID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := make([]dto.NotificationDeliveryResp, len(items))
+	for i := range items {
+		resp[i].FromEntity(&items[i])
+	}
+
+	return resp, nil
+}
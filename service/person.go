@@ -4,6 +4,7 @@ import (
 	"base-gin/domain/dto"
 	"base-gin/exception"
 	"base-gin/repository"
+	"context"
 )
 
 type PersonService struct {
@@ -14,10 +15,10 @@ func NewPersonService(personRepo *repository.PersonRepository) *PersonService {
 	return &PersonService{repo: personRepo}
 }
 
-func (s *PersonService) GetAccountProfile(accountID uint) (dto.AccountProfileResp, error) {
+func (s *PersonService) GetAccountProfile(ctx context.Context, accountID uint) (dto.AccountProfileResp, error) {
 	var resp dto.AccountProfileResp
 
-	item, err := s.repo.GetByAccountID(accountID)
+	item, err := s.repo.GetByAccountID(ctx, accountID)
 	if err != nil {
 		return resp, err
 	}
@@ -27,10 +28,10 @@ func (s *PersonService) GetAccountProfile(accountID uint) (dto.AccountProfileRes
 	return resp, nil
 }
 
-func (s *PersonService) GetByID(id uint) (dto.PersonDetailResp, error) {
+func (s *PersonService) GetByID(ctx context.Context, id uint) (dto.PersonDetailResp, error) {
 	var resp dto.PersonDetailResp
 
-	item, err := s.repo.GetByID(id)
+	item, err := s.repo.GetByID(ctx, id)
 	if err != nil {
 		return resp, err
 	}
@@ -43,15 +44,12 @@ func (s *PersonService) GetByID(id uint) (dto.PersonDetailResp, error) {
 	return resp, nil
 }
 
-func (s *PersonService) GetList(params *dto.Filter) ([]dto.PersonDetailResp, error) {
+func (s *PersonService) GetList(ctx context.Context, params *dto.PersonFilter) ([]dto.PersonDetailResp, dto.ListMeta, error) {
 	var resp []dto.PersonDetailResp
 
-	items, err := s.repo.GetList(params)
+	items, meta, err := s.repo.GetList(ctx, params)
 	if err != nil {
-		return nil, err
-	}
-	if len(items) < 1 {
-		return nil, exception.ErrUserNotFound
+		return nil, meta, err
 	}
 
 	for _, item := range items {
@@ -61,14 +59,26 @@ func (s *PersonService) GetList(params *dto.Filter) ([]dto.PersonDetailResp, err
 		resp = append(resp, t)
 	}
 
-	return resp, nil
+	return resp, meta, nil
 }
 
-func (s *PersonService) Update(params *dto.PersonUpdateReq) error {
+// Update saves a person's detail. When memberAccountID is set only the person
+// of that account may be updated.
+func (s *PersonService) Update(ctx context.Context, params *dto.PersonUpdateReq, memberAccountID *uint) error {
 	if params.ID <= 0 {
 		return exception.ErrUserNotFound
 	}
 
+	if memberAccountID != nil {
+		item, err := s.repo.GetByID(ctx, params.ID)
+		if err != nil {
+			return err
+		}
+		if item.AccountID == nil || *item.AccountID != *memberAccountID {
+			return exception.ErrForbidden
+		}
+	}
+
 	birthDate, err := params.GetBirthDate()
 	if err != nil {
 		exception.LogError(err, "PersonService.Update")
@@ -76,5 +86,11 @@ func (s *PersonService) Update(params *dto.PersonUpdateReq) error {
 	}
 	params.BirthDate = birthDate
 
-	return s.repo.Update(params)
+	return s.repo.Update(ctx, params)
+}
+
+// SetCategory puts the person in a patron category, which picks the
+// circulation rules applying to their loans.
+func (s *PersonService) SetCategory(ctx context.Context, id uint, category string) error {
+	return s.repo.UpdateCategory(ctx, id, category)
 }
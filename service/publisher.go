@@ -4,25 +4,30 @@ import (
 	"base-gin/domain/dto"
 	"base-gin/exception"
 	"base-gin/repository"
+	"context"
 )
 
 type PublisherService struct {
-	repo *repository.PublisherRepository
+	repo   *repository.PublisherRepository
+	search *SearchService
 }
 
-func NewPublisherService(publisherRepo *repository.PublisherRepository) *PublisherService {
-	return &PublisherService{repo: publisherRepo}
+func NewPublisherService(
+	publisherRepo *repository.PublisherRepository,
+	searchService *SearchService,
+) *PublisherService {
+	return &PublisherService{repo: publisherRepo, search: searchService}
 }
 
-func (s *PublisherService) Create(params *dto.PublisherCreateReq) error {
+func (s *PublisherService) Create(ctx context.Context, params *dto.PublisherCreateReq) error {
 	newItem := params.ToEntity()
-	return s.repo.Create(&newItem)
+	return s.repo.Create(ctx, &newItem)
 }
 
-func (s *PublisherService) GetByID(id uint) (dto.PublisherResp, error) {
+func (s *PublisherService) GetByID(ctx context.Context, id uint) (dto.PublisherResp, error) {
 	var resp dto.PublisherResp
 
-	item, err := s.repo.GetByID(id)
+	item, err := s.repo.GetByID(ctx, id)
 	if err != nil {
 		return resp, err
 	}
@@ -36,15 +41,12 @@ func (s *PublisherService) GetByID(id uint) (dto.PublisherResp, error) {
 	return resp, nil
 }
 
-func (s *PublisherService) GetList(params *dto.Filter) ([]dto.PublisherResp, error) {
+func (s *PublisherService) GetList(ctx context.Context, params *dto.PublisherFilter) ([]dto.PublisherResp, dto.ListMeta, error) {
 	var resp []dto.PublisherResp
 
-	items, err := s.repo.GetList(params)
+	items, meta, err := s.repo.GetList(ctx, params)
 	if err != nil {
-		return nil, err
-	}
-	if len(items) < 1 {
-		return nil, exception.ErrDataNotFound
+		return nil, meta, err
 	}
 
 	for _, item := range items {
@@ -54,21 +56,31 @@ func (s *PublisherService) GetList(params *dto.Filter) ([]dto.PublisherResp, err
 		resp = append(resp, t)
 	}
 
-	return resp, nil
+	return resp, meta, nil
 }
 
-func (s *PublisherService) Update(params *dto.PublisherUpdateReq) error {
+func (s *PublisherService) Update(ctx context.Context, params *dto.PublisherUpdateReq) error {
 	if params.ID <= 0 {
 		return exception.ErrDataNotFound
 	}
 
-	return s.repo.Update(params)
+	if err := s.repo.Update(ctx, params); err != nil {
+		return err
+	}
+
+	s.search.syncPublisher(ctx, params.ID)
+	return nil
 }
 
-func (s *PublisherService) Delete(id uint) error {
+func (s *PublisherService) Delete(ctx context.Context, id uint) error {
 	if id <= 0 {
 		return exception.ErrDataNotFound
 	}
 
-	return s.repo.Delete(id)
+	if err := s.repo.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	s.search.syncPublisher(ctx, id)
+	return nil
 }
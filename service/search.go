@@ -0,0 +1,216 @@
+package service
+
+import (
+	"base-gin/domain"
+	"base-gin/domain/dao"
+	"base-gin/domain/dto"
+	"base-gin/repository"
+	"base-gin/search"
+	"context"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Fields & facets of the catalog index.
+const (
+	searchFieldTitle     = "title"
+	searchFieldSubtitle  = "subtitle"
+	searchFieldAuthors   = "authors"
+	searchFieldPublisher = "publisher"
+
+	searchFacetPublisher = "publisher"
+	searchFacetAuthor    = "author"
+)
+
+const searchDefaultLimit = 20
+
+// SearchService searches the catalog through an in-memory index of the books,
+// kept in sync as books, authors and publishers are written. The index is
+// built by Rebuild, on start and by a scheduled job.
+type SearchService struct {
+	index    *search.Index
+	repo     *repository.BookRepository
+	copyRepo *repository.BookCopyRepository
+}
+
+func NewSearchService(
+	bookRepo *repository.BookRepository,
+	bookCopyRepo *repository.BookCopyRepository,
+) *SearchService {
+	return &SearchService{
+		index: search.New(
+			search.Field{Name: searchFieldTitle, Boost: 3},
+			search.Field{Name: searchFieldSubtitle, Boost: 1.5},
+			search.Field{Name: searchFieldAuthors, Boost: 2},
+			search.Field{Name: searchFieldPublisher, Boost: 1},
+		),
+		repo:     bookRepo,
+		copyRepo: bookCopyRepo,
+	}
+}
+
+// Rebuild indexes every book anew and returns how many there are.
+func (s *SearchService) Rebuild(ctx context.Context) (int, error) {
+	items, err := s.repo.GetAll(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	docs := make([]search.Document, len(items))
+	for i := range items {
+		docs[i] = bookDocument(&items[i])
+	}
+	s.index.Reset(docs)
+
+	return len(docs), nil
+}
+
+// Search ranks the books matching every word of the query, allowing for
+// typos and for the last word being typed.
+func (s *SearchService) Search(ctx context.Context, params *dto.SearchFilter) (dto.SearchResp, error) {
+	resp := dto.SearchResp{Hits: []dto.SearchHitResp{}}
+
+	q := search.Query{
+		Text:    params.Query,
+		Filters: make(map[string]uint),
+		Start:   params.Start,
+		Limit:   params.Limit,
+	}
+	if q.Limit == 0 {
+		q.Limit = searchDefaultLimit
+	}
+	if params.PublisherID > 0 {
+		q.Filters[searchFacetPublisher] = params.PublisherID
+	}
+	if params.AuthorID > 0 {
+		q.Filters[searchFacetAuthor] = params.AuthorID
+	}
+
+	result := s.index.Search(q)
+	resp.Total = result.Total
+	resp.Facets.Publishers = facetResps(result.Facets[searchFacetPublisher])
+	resp.Facets.Authors = facetResps(result.Facets[searchFacetAuthor])
+
+	ids := make([]uint, len(result.Hits))
+	for i, hit := range result.Hits {
+		ids[i] = hit.ID
+	}
+	items, err := s.repo.GetListByIDs(ctx, ids)
+	if err != nil {
+		return resp, err
+	}
+	books, err := bookResps(ctx, s.copyRepo, items)
+	if err != nil {
+		return resp, err
+	}
+	byID := make(map[uint]dto.BookResp, len(books))
+	for _, book := range books {
+		byID[book.ID] = book
+	}
+
+	for _, hit := range result.Hits {
+		// Deleted since it was indexed.
+		book, ok := byID[hit.ID]
+		if !ok {
+			continue
+		}
+		resp.Hits = append(resp.Hits, dto.SearchHitResp{
+			Score:      hit.Score,
+			Highlights: hit.Highlights,
+			Book:       book,
+		})
+	}
+
+	return resp, nil
+}
+
+// syncBooks indexes the books again after they were written and drops those
+// deleted. Failures are only logged, the next rebuild catching up.
+func (s *SearchService) syncBooks(ctx context.Context, ids ...uint) {
+	items, err := s.repo.GetListByIDs(ctx, ids)
+	if err != nil {
+		log.Error().Err(err).Msg("SearchService.syncBooks")
+		return
+	}
+
+	found := make(map[uint]bool, len(items))
+	for i := range items {
+		found[items[i].ID] = true
+		s.index.Put(bookDocument(&items[i]))
+	}
+	for _, id := range ids {
+		if !found[id] {
+			s.index.Delete(id)
+		}
+	}
+}
+
+// syncPublisher indexes the books of the publisher again.
+func (s *SearchService) syncPublisher(ctx context.Context, publisherID uint) {
+	ids, err := s.repo.GetIDsByPublisherID(ctx, publisherID)
+	if err != nil {
+		log.Error().Err(err).Msg("SearchService.syncPublisher")
+		return
+	}
+	s.syncBooks(ctx, ids...)
+}
+
+// syncAuthor indexes the books the author contributed to again.
+func (s *SearchService) syncAuthor(ctx context.Context, authorID uint) {
+	ids, err := s.repo.GetIDsByAuthorID(ctx, authorID)
+	if err != nil {
+		log.Error().Err(err).Msg("SearchService.syncAuthor")
+		return
+	}
+	s.syncBooks(ctx, ids...)
+}
+
+// bookDocument indexes the book under the names of all its contributors,
+// faceted by its publisher and its authors.
+func bookDocument(item *dao.Book) search.Document {
+	doc := search.Document{
+		ID: item.ID,
+		Fields: map[string]string{
+			searchFieldTitle:     item.Title,
+			searchFieldPublisher: item.BookPublisher.Name,
+		},
+		Facets: map[string][]search.Facet{},
+	}
+	if item.Subtitle != nil {
+		doc.Fields[searchFieldSubtitle] = *item.Subtitle
+	}
+	if item.BookPublisher.ID > 0 {
+		doc.Facets[searchFacetPublisher] = []search.Facet{
+			{ID: item.BookPublisher.ID, Name: item.BookPublisher.Name},
+		}
+	}
+
+	var names []string
+	seen := make(map[uint]bool)
+	for _, c := range item.Contributors {
+		if c.Author == nil {
+			continue
+		}
+		if !seen[c.AuthorID] {
+			seen[c.AuthorID] = true
+			names = append(names, c.Author.FullName)
+		}
+		if c.Role == domain.ContributorAuthor {
+			doc.Facets[searchFacetAuthor] = append(doc.Facets[searchFacetAuthor],
+				search.Facet{ID: c.AuthorID, Name: c.Author.FullName})
+		}
+	}
+	doc.Fields[searchFieldAuthors] = strings.Join(names, "; ")
+
+	return doc
+}
+
+func facetResps(items []search.FacetCount) []dto.FacetResp {
+	resp := make([]dto.FacetResp, len(items))
+	for i := range items {
+		resp[i].FromFacetCount(&items[i])
+	}
+
+	return resp
+}
@@ -2,6 +2,7 @@ package service
 
 import (
 	"base-gin/config"
+	"base-gin/notification"
 	"base-gin/repository"
 )
 
@@ -11,16 +12,90 @@ var (
 	publisherService *PublisherService
 	authorService    *AuthorService
 	bookService      *BookService
+	categoryService  *CategoryService
+	bookCopyService  *BookCopyService
 	borrowingService *BorrowingService
+	fineService      *FineService
+	holdService      *HoldService
+	ruleService      *CirculationRuleService
+	notifyService    *NotificationService
+	jobService       *JobService
+	searchService    *SearchService
+	importService    *ImportService
 )
 
 func SetupServices(cfg *config.Config) {
-	accountService = NewAccountService(cfg, repository.GetAccountRepo())
+	accountService = NewAccountService(
+		cfg,
+		repository.GetTxManager(),
+		repository.GetAccountRepo(),
+		repository.GetRefreshTokenRepo(),
+		repository.GetRevokedTokenRepo(),
+		repository.GetLoginAttemptStore(),
+	)
 	personService = NewPersonService(repository.GetPersonRepo())
-	publisherService = NewPublisherService(repository.GetPublisherRepo())
-	authorService = NewAuthorService(repository.GetAuthorRepo())
-	bookService = NewBookService(repository.GetBookRepo())
-	borrowingService = NewBorrowingService(repository.GetBorrowingRepo())
+	searchService = NewSearchService(repository.GetBookRepo(), repository.GetBookCopyRepo())
+	publisherService = NewPublisherService(repository.GetPublisherRepo(), searchService)
+	authorService = NewAuthorService(repository.GetAuthorRepo(), searchService)
+	bookService = NewBookService(
+		repository.GetTxManager(),
+		repository.GetBookRepo(),
+		repository.GetBookCopyRepo(),
+		repository.GetAuthorRepo(),
+		repository.GetCategoryRepo(),
+		searchService,
+	)
+	importService = NewImportService(repository.GetTxManager(), searchService)
+	categoryService = NewCategoryService(
+		repository.GetTxManager(),
+		repository.GetCategoryRepo(),
+	)
+	bookCopyService = NewBookCopyService(
+		cfg,
+		repository.GetTxManager(),
+		repository.GetBookCopyRepo(),
+		repository.GetBookRepo(),
+		repository.GetBorrowingRepo(),
+	)
+	borrowingService = NewBorrowingService(
+		cfg,
+		repository.GetTxManager(),
+		repository.GetBorrowingRepo(),
+		repository.GetBookRepo(),
+		repository.GetPersonRepo(),
+	)
+	fineService = NewFineService(
+		repository.GetTxManager(),
+		repository.GetFineRepo(),
+		repository.GetPersonRepo(),
+	)
+	holdService = NewHoldService(
+		cfg,
+		repository.GetTxManager(),
+		repository.GetHoldRepo(),
+		repository.GetPersonRepo(),
+	)
+	ruleService = NewCirculationRuleService(repository.GetCirculationRuleRepo())
+	notifyService = NewNotificationService(
+		cfg,
+		repository.GetNotificationRepo(),
+		repository.GetBorrowingRepo(),
+		repository.GetPersonRepo(),
+		notification.NewSenders(&cfg.Notification)...,
+	)
+	jobService = NewJobService(
+		cfg,
+		repository.GetJobRunRepo(),
+		repository.GetRefreshTokenRepo(),
+		repository.GetRevokedTokenRepo(),
+		repository.GetLoginAttemptStore(),
+	)
+	// The specs are checked by config.NewConfig.
+	err := jobService.Register(
+		newJobs(cfg, borrowingService, holdService, notifyService, searchService, jobService)...)
+	if err != nil {
+		panic(err)
+	}
 }
 
 func GetAccountService() *AccountService {
@@ -43,10 +118,46 @@ func GetBookService() *BookService {
 	return bookService
 }
 
+func GetCategoryService() *CategoryService {
+	return categoryService
+}
+
+func GetBookCopyService() *BookCopyService {
+	return bookCopyService
+}
+
 func GetBorrowingService() *BorrowingService {
 	return borrowingService
 }
 
+func GetFineService() *FineService {
+	return fineService
+}
+
+func GetHoldService() *HoldService {
+	return holdService
+}
+
+func GetCirculationRuleService() *CirculationRuleService {
+	return ruleService
+}
+
+func GetNotificationService() *NotificationService {
+	return notifyService
+}
+
+func GetJobService() *JobService {
+	return jobService
+}
+
+func GetSearchService() *SearchService {
+	return searchService
+}
+
 func GetAuthorService() *AuthorService {
 	return authorService
 }
+
+func GetImportService() *ImportService {
+	return importService
+}
@@ -1,8 +1,16 @@
 package integration_test
 
 import (
+	"base-gin/domain/dao"
 	"base-gin/domain/dto"
+	"base-gin/exception"
 	"base-gin/server"
+	"base-gin/util"
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -35,3 +43,173 @@ func TestAccount_GetProfile_ErrorAccessToken(t *testing.T) {
 	w = doTest("GET", server.RootAccount, nil, "accessToken")
 	assert.Equal(t, 401, w.Code)
 }
+
+func TestAccount_Register_Success(t *testing.T) {
+	req := dto.AccountRegisterReq{
+		Username:     util.RandomStringAlpha(10),
+		Password:     password,
+		Fullname:     util.RandomStringAlpha(5) + " " + util.RandomStringAlpha(6),
+		Gender:       "f",
+		BirthDateStr: "1999-02-03",
+	}
+
+	w := doTest("POST", server.RootAccount+server.PathRegister, req, "")
+	assert.Equal(t, 201, w.Code)
+	assert.NotContains(t, w.Body.String(), "access_token")
+
+	account, err := accountRepo.GetByUsername(context.Background(), req.Username)
+	assert.Nil(t, err)
+
+	person, err := personRepo.GetByAccountID(context.Background(), account.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, req.Fullname, person.Fullname)
+}
+
+func TestAccount_Register_WithLogin(t *testing.T) {
+	req := dto.AccountRegisterReq{
+		Username: util.RandomStringAlpha(10),
+		Password: password,
+		Fullname: util.RandomStringAlpha(5) + " " + util.RandomStringAlpha(6),
+		Login:    true,
+	}
+
+	w := doTest("POST", server.RootAccount+server.PathRegister, req, "")
+	assert.Equal(t, 201, w.Code)
+	assert.Contains(t, w.Body.String(), "access_token")
+	assert.Contains(t, w.Body.String(), "refresh_token")
+}
+
+func TestAccount_Register_ErrorConflict(t *testing.T) {
+	req := dto.AccountRegisterReq{
+		Username: dummyAdmin.Account.Username,
+		Password: password,
+		Fullname: util.RandomStringAlpha(5) + " " + util.RandomStringAlpha(6),
+	}
+
+	w := doTest("POST", server.RootAccount+server.PathRegister, req, "")
+	assert.Equal(t, 409, w.Code)
+}
+
+func TestAccount_Create_ErrorConflict(t *testing.T) {
+	// Registrations racing past the username check still meet the index.
+	account, _ := dao.NewUser(dummyAdmin.Account.Username, util.RandomStringAlpha(10),
+		cfg.AuthN.PasswordEncryptionSecret)
+	err := accountRepo.Create(context.Background(), &account)
+	assert.ErrorIs(t, err, exception.ErrUserConflict)
+}
+
+func TestAccount_Register_ErrorValidation(t *testing.T) {
+	req := dto.AccountRegisterReq{
+		Username: "a!",
+		Password: "short",
+	}
+
+	w := doTest("POST", server.RootAccount+server.PathRegister, req, "")
+	assert.Equal(t, 422, w.Code)
+}
+
+func loginTokens(t *testing.T) dto.AccountLoginResp {
+	req := dto.AccountLoginReq{
+		Username: "admin",
+		Password: password,
+	}
+
+	w := doTest("POST", server.RootAccount+server.PathLogin, req, "")
+	assert.Equal(t, 200, w.Code)
+
+	var resp dto.SuccessResponse[dto.AccountLoginResp]
+	_ = json.Unmarshal(w.Body.Bytes(), &resp)
+	return resp.Data
+}
+
+func TestAccount_Refresh_Success(t *testing.T) {
+	tokens := loginTokens(t)
+
+	w := doTest("POST", server.RootAccount+server.PathRefresh, nil, tokens.RefreshToken)
+	assert.Equal(t, 200, w.Code)
+
+	var resp dto.SuccessResponse[dto.AccountLoginResp]
+	_ = json.Unmarshal(w.Body.Bytes(), &resp)
+	assert.NotEmpty(t, resp.Data.AccessToken)
+	assert.NotEqual(t, tokens.RefreshToken, resp.Data.RefreshToken)
+
+	// the rotated token can not be used twice, and reusing it revokes the new one
+	w = doTest("POST", server.RootAccount+server.PathRefresh, nil, tokens.RefreshToken)
+	assert.Equal(t, 401, w.Code)
+
+	w = doTest("POST", server.RootAccount+server.PathRefresh, nil, resp.Data.RefreshToken)
+	assert.Equal(t, 401, w.Code)
+}
+
+func TestAccount_Refresh_ErrorAccessToken(t *testing.T) {
+	tokens := loginTokens(t)
+
+	w := doTest("POST", server.RootAccount+server.PathRefresh, nil, tokens.AccessToken)
+	assert.Equal(t, 401, w.Code)
+}
+
+func TestAccount_Logout_Success(t *testing.T) {
+	tokens := loginTokens(t)
+
+	w := doTest("POST", server.RootAccount+server.PathLogout, nil, tokens.RefreshToken)
+	assert.Equal(t, 200, w.Code)
+
+	w = doTest("POST", server.RootAccount+server.PathRefresh, nil, tokens.RefreshToken)
+	assert.Equal(t, 401, w.Code)
+}
+
+func doLoginFrom(ipAddress, username, paswd string) *httptest.ResponseRecorder {
+	body, _ := json.Marshal(dto.AccountLoginReq{Username: username, Password: paswd})
+	r, _ := http.NewRequest("POST", server.RootAccount+server.PathLogin, bytes.NewBuffer(body))
+	r.Header.Set("Content-Type", "application/json")
+	r.RemoteAddr = ipAddress + ":40000"
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, r)
+	return w
+}
+
+func TestAccount_Login_ThrottledByUsername(t *testing.T) {
+	user := registerWithLogin(t)
+
+	for i := 0; i < cfg.AuthN.LoginMaxAttempt; i++ {
+		w := doLoginFrom("10.0.1.1", user.Username, "wrong-paswd")
+		assert.Equal(t, 400, w.Code)
+	}
+
+	w := doLoginFrom("10.0.1.1", user.Username, password)
+	assert.Equal(t, 429, w.Code)
+	assert.NotEmpty(t, w.Header().Get("Retry-After"))
+
+	w = doLoginFrom("10.0.1.2", user.Username, password)
+	assert.Equal(t, 429, w.Code)
+}
+
+func TestAccount_Login_ThrottledByIP(t *testing.T) {
+	for i := 0; i < cfg.AuthN.LoginMaxAttempt; i++ {
+		w := doLoginFrom("10.0.2.1", util.RandomStringAlpha(10), "wrong-paswd")
+		assert.Equal(t, 400, w.Code)
+	}
+
+	w := doLoginFrom("10.0.2.1", "admin", password)
+	assert.Equal(t, 429, w.Code)
+
+	w = doLoginFrom("10.0.2.2", "admin", password)
+	assert.Equal(t, 200, w.Code)
+}
+
+func TestAccount_Login_SuccessResetsThrottle(t *testing.T) {
+	user := registerWithLogin(t)
+
+	for i := 0; i < cfg.AuthN.LoginMaxAttempt-1; i++ {
+		w := doLoginFrom("10.0.3.1", user.Username, "wrong-paswd")
+		assert.Equal(t, 400, w.Code)
+	}
+
+	w := doLoginFrom("10.0.3.2", user.Username, password)
+	assert.Equal(t, 200, w.Code)
+
+	w = doLoginFrom("10.0.3.2", user.Username, "wrong-paswd")
+	assert.Equal(t, 400, w.Code)
+	w = doLoginFrom("10.0.3.2", user.Username, password)
+	assert.Equal(t, 200, w.Code)
+}
@@ -0,0 +1,145 @@
+package integration_test
+
+import (
+	"base-gin/domain/dto"
+	"base-gin/server"
+	"base-gin/util"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func registerWithLogin(t *testing.T) dto.AccountRegisterResp {
+	req := dto.AccountRegisterReq{
+		Username: util.RandomStringAlpha(10),
+		Password: password,
+		Fullname: util.RandomStringAlpha(5) + " " + util.RandomStringAlpha(6),
+		Login:    true,
+	}
+
+	w := doTest("POST", server.RootAccount+server.PathRegister, req, "")
+	assert.Equal(t, 201, w.Code)
+
+	var resp dto.SuccessResponse[dto.AccountRegisterResp]
+	_ = json.Unmarshal(w.Body.Bytes(), &resp)
+	return resp.Data
+}
+
+func TestAdmin_RevokeAccountTokens_Success(t *testing.T) {
+	accessToken := createAuthAccessToken(dummyAdmin.Account.Username)
+	user := registerWithLogin(t)
+
+	w := doTest("GET", server.RootAccount, nil, user.Token.AccessToken)
+	assert.Equal(t, 200, w.Code)
+
+	// Tokens only tell the second they were issued in, so the revocation
+	// comes in the next one.
+	time.Sleep(time.Until(time.Now().Truncate(time.Second).Add(time.Second)))
+
+	url := server.RootAdmin + fmt.Sprintf("/accounts/%d/revoke-tokens", user.ID)
+	w = doTest("POST", url, nil, accessToken)
+	assert.Equal(t, 200, w.Code)
+
+	w = doTest("GET", server.RootAccount, nil, user.Token.AccessToken)
+	assert.Equal(t, 401, w.Code)
+
+	w = doTest("POST", server.RootAccount+server.PathRefresh, nil, user.Token.RefreshToken)
+	assert.Equal(t, 401, w.Code)
+
+	// Logging in again right away gives tokens that are not revoked.
+	req := dto.AccountLoginReq{Username: user.Username, Password: password}
+	w = doTest("POST", server.RootAccount+server.PathLogin, req, "")
+	assert.Equal(t, 200, w.Code)
+	var login dto.SuccessResponse[dto.AccountLoginResp]
+	_ = json.Unmarshal(w.Body.Bytes(), &login)
+
+	w = doTest("GET", server.RootAccount, nil, login.Data.AccessToken)
+	assert.Equal(t, 200, w.Code)
+}
+
+func TestAdmin_RevokeAccountTokens_ErrorNotFound(t *testing.T) {
+	accessToken := createAuthAccessToken(dummyAdmin.Account.Username)
+
+	w := doTest("POST", server.RootAdmin+"/accounts/99999/revoke-tokens", nil, accessToken)
+	assert.Equal(t, 404, w.Code)
+}
+
+func TestAdmin_RevokeToken_Success(t *testing.T) {
+	accessToken := createAuthAccessToken(dummyAdmin.Account.Username)
+	user := registerWithLogin(t)
+
+	req := dto.TokenRevokeReq{Token: user.Token.AccessToken}
+	w := doTest("POST", server.RootAdmin+server.PathAdminTokenRevoke, req, accessToken)
+	assert.Equal(t, 200, w.Code)
+
+	w = doTest("GET", server.RootAccount, nil, user.Token.AccessToken)
+	assert.Equal(t, 401, w.Code)
+
+	// the refresh token of the same session is unaffected
+	w = doTest("POST", server.RootAccount+server.PathRefresh, nil, user.Token.RefreshToken)
+	assert.Equal(t, 200, w.Code)
+}
+
+func TestAdmin_RevokeToken_ErrorInvalid(t *testing.T) {
+	accessToken := createAuthAccessToken(dummyAdmin.Account.Username)
+
+	req := dto.TokenRevokeReq{Token: "not-a-token"}
+	w := doTest("POST", server.RootAdmin+server.PathAdminTokenRevoke, req, accessToken)
+	assert.Equal(t, 400, w.Code)
+}
+
+func TestAdmin_SetAccountRole_Success(t *testing.T) {
+	accessToken := createAuthAccessToken(dummyAdmin.Account.Username)
+	user := registerWithLogin(t)
+
+	params := dto.PublisherCreateReq{
+		Name: util.RandomStringAlpha(6),
+		City: util.RandomStringAlpha(8),
+	}
+	w := doTest("POST", server.RootPublisher, params, user.Token.AccessToken)
+	assert.Equal(t, 403, w.Code)
+
+	url := server.RootAdmin + fmt.Sprintf("/accounts/%d/role", user.ID)
+	w = doTest("PUT", url, dto.AccountRoleReq{Role: "librarian"}, accessToken)
+	assert.Equal(t, 200, w.Code)
+
+	// the stored role applies to tokens issued before the change
+	w = doTest("POST", server.RootPublisher, params, user.Token.AccessToken)
+	assert.Equal(t, 201, w.Code)
+}
+
+func TestAdmin_SetAccountRole_ErrorValidation(t *testing.T) {
+	accessToken := createAuthAccessToken(dummyAdmin.Account.Username)
+	user := registerWithLogin(t)
+
+	url := server.RootAdmin + fmt.Sprintf("/accounts/%d/role", user.ID)
+	w := doTest("PUT", url, dto.AccountRoleReq{Role: "owner"}, accessToken)
+	assert.Equal(t, 422, w.Code)
+}
+
+func TestAdmin_ErrorForbidden(t *testing.T) {
+	user := registerWithLogin(t)
+
+	url := server.RootAdmin + fmt.Sprintf("/accounts/%d/role", user.ID)
+	w := doTest("PUT", url, dto.AccountRoleReq{Role: "admin"}, user.Token.AccessToken)
+	assert.Equal(t, 403, w.Code)
+}
+
+func TestAdmin_RevokeTokens_ErrorForbidden(t *testing.T) {
+	user := registerWithLogin(t)
+	other := registerWithLogin(t)
+
+	url := server.RootAdmin + fmt.Sprintf("/accounts/%d/revoke-tokens", other.ID)
+	w := doTest("POST", url, nil, user.Token.AccessToken)
+	assert.Equal(t, 403, w.Code)
+
+	req := dto.TokenRevokeReq{Token: other.Token.AccessToken}
+	w = doTest("POST", server.RootAdmin+server.PathAdminTokenRevoke, req, user.Token.AccessToken)
+	assert.Equal(t, 403, w.Code)
+
+	w = doTest("GET", server.RootAccount, nil, other.Token.AccessToken)
+	assert.Equal(t, 200, w.Code)
+}
@@ -5,6 +5,7 @@ import (
 	"base-gin/domain/dto"
 	"base-gin/server"
 	"base-gin/util"
+	"context"
 	"fmt"
 	"testing"
 	"time"
@@ -36,13 +37,24 @@ func TestAuthor_Create_Success(t *testing.T) {
 	assert.Equal(t, 201, w.Code)
 }
 
+func TestAuthor_Create_ErrorForbidden(t *testing.T) {
+	member := registerWithLogin(t)
+	params := dto.AuthorDTO{
+		FullName: util.RandomStringAlpha(8),
+		Gender:   stringPtr("m"),
+	}
+
+	w := doTest("POST", server.RootAuthor, params, member.Token.AccessToken)
+	assert.Equal(t, 403, w.Code)
+}
+
 func TestAuthor_Update_Success(t *testing.T) {
 	a := dao.Author{
 		FullName:  util.RandomStringAlpha(8),
 		Gender:    *stringPtr("m"),
 		BirthDate: *timePtr(time.Now().AddDate(-30, 0, 0)),
 	}
-	_ = authorRepo.Create(&a)
+	_ = authorRepo.Create(context.Background(), &a)
 
 	params := dto.AuthorUpdate{
 		FullName:  util.RandomStringAlpha(10),
@@ -58,7 +70,7 @@ func TestAuthor_Update_Success(t *testing.T) {
 	)
 	assert.Equal(t, 200, w.Code)
 
-	item, _ := authorRepo.GetByID(a.ID)
+	item, _ := authorRepo.GetByID(context.Background(), a.ID)
 	assert.Equal(t, params.FullName, item.FullName)
 	assert.Equal(t, *params.Gender, item.Gender)
 	assert.WithinDuration(t, *params.BirthDate, item.BirthDate, time.Second)
@@ -71,7 +83,7 @@ func TestAuthor_Delete_Success(t *testing.T) {
 		Gender:    *stringPtr("f"),
 		BirthDate: *timePtr(time.Now().AddDate(-25, 0, 0)),
 	}
-	_ = authorRepo.Create(&a)
+	_ = authorRepo.Create(context.Background(), &a)
 
 	w := doTest(
 		"DELETE",
@@ -88,7 +100,7 @@ func TestAuthor_GetList_Success(t *testing.T) {
 		Gender:    *stringPtr("m"),
 		BirthDate: *timePtr(time.Now().AddDate(-25, 0, 0)),
 	}
-	_ = authorRepo.Create(&a)
+	_ = authorRepo.Create(context.Background(), &a)
 
 	w := doTest(
 		"GET",
@@ -105,7 +117,7 @@ func TestAuthor_GetDetail_Success(t *testing.T) {
 		Gender:    *stringPtr("f"),
 		BirthDate: *timePtr(time.Now().AddDate(-20, 0, 0)),
 	}
-	_ = authorRepo.Create(&a)
+	_ = authorRepo.Create(context.Background(), &a)
 
 	w := doTest(
 		"GET",
@@ -0,0 +1,162 @@
+package integration_test
+
+import (
+	"base-gin/domain/dao"
+	"base-gin/domain/dto"
+	"base-gin/server"
+	"base-gin/util"
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func createAuthor(t *testing.T) dao.Author {
+	author := dao.Author{FullName: util.RandomStringAlpha(8), Gender: "m"}
+	assert.Nil(t, authorRepo.Create(context.Background(), &author))
+	return author
+}
+
+func getAuthorBooks(t *testing.T, authorID uint, query string) []dto.AuthorWorkResp {
+	w := doTest("GET", fmt.Sprintf("%s/%d/books%s", server.RootAuthor, authorID, query), nil, "")
+	assert.Equal(t, 200, w.Code)
+
+	var resp dto.SuccessResponse[[]dto.AuthorWorkResp]
+	_ = json.Unmarshal(w.Body.Bytes(), &resp)
+	return resp.Data
+}
+
+func TestBook_Contributors_Create(t *testing.T) {
+	editor := createAuthor(t)
+	first := createAuthor(t)
+	second := createAuthor(t)
+
+	book := createBook(t, dto.BookDTO{Contributors: []dto.BookContributorReq{
+		{AuthorID: editor.ID, Role: "editor"},
+		{AuthorID: first.ID, Role: "author"},
+		{AuthorID: second.ID, Role: "author"},
+		{AuthorID: first.ID, Role: "author"},
+	}})
+
+	// The first credited author is the primary one and repeats are dropped.
+	assert.Equal(t, first.ID, book.AuthorID)
+	assert.Equal(t, []dto.BookContributorResp{
+		{AuthorID: editor.ID, FullName: editor.FullName, Role: "editor"},
+		{AuthorID: first.ID, FullName: first.FullName, Role: "author"},
+		{AuthorID: second.ID, FullName: second.FullName, Role: "author"},
+	}, book.Contributors)
+}
+
+func TestBook_Contributors_SoleAuthor(t *testing.T) {
+	author := createAuthor(t)
+	book := createDummyBook()
+	params := dto.BookDTO{
+		Title:       util.RandomStringAlpha(6),
+		Subtitle:    ptrToString(util.RandomStringAlpha(10)),
+		PublisherID: book.PublisherID,
+		AuthorID:    author.ID,
+	}
+	w := doTest("POST", server.RootBook, params, createAuthAccessToken(dummyAdmin.Account.Username))
+	assert.Equal(t, 201, w.Code)
+
+	works := getAuthorBooks(t, author.ID, "")
+	assert.Len(t, works, 1)
+	assert.Equal(t, "author", works[0].Role)
+	assert.Equal(t, params.Title, works[0].Title)
+}
+
+func TestBook_Contributors_Update(t *testing.T) {
+	author := createAuthor(t)
+	translator := createAuthor(t)
+	book := createBook(t, dto.BookDTO{Contributors: []dto.BookContributorReq{
+		{AuthorID: author.ID, Role: "author"},
+	}})
+
+	params := dto.BookUpdate{
+		Title:       book.Title,
+		Subtitle:    book.Subtitle,
+		PublisherID: book.PublisherID,
+		Contributors: []dto.BookContributorReq{
+			{AuthorID: translator.ID, Role: "translator"},
+			{AuthorID: author.ID, Role: "author"},
+		},
+	}
+	url := fmt.Sprintf("%s/%d", server.RootBook, book.ID)
+	w := doTest("PUT", url, params, createAuthAccessToken(dummyAdmin.Account.Username))
+	assert.Equal(t, 200, w.Code)
+
+	w = doTest("GET", url, nil, "")
+	var resp dto.SuccessResponse[dto.BookResp]
+	_ = json.Unmarshal(w.Body.Bytes(), &resp)
+	assert.Equal(t, author.ID, resp.Data.AuthorID)
+	assert.Len(t, resp.Data.Contributors, 2)
+	assert.Equal(t, "translator", resp.Data.Contributors[0].Role)
+
+	// Without contributors the author ID is the sole author.
+	params.Contributors = nil
+	params.AuthorID = translator.ID
+	w = doTest("PUT", url, params, createAuthAccessToken(dummyAdmin.Account.Username))
+	assert.Equal(t, 200, w.Code)
+	assert.Empty(t, getAuthorBooks(t, author.ID, ""))
+}
+
+func TestBook_Contributors_ErrorValidation(t *testing.T) {
+	author := createAuthor(t)
+	book := createDummyBook()
+	token := createAuthAccessToken(dummyAdmin.Account.Username)
+
+	params := dto.BookDTO{
+		Title:        util.RandomStringAlpha(6),
+		Subtitle:     ptrToString(util.RandomStringAlpha(10)),
+		PublisherID:  book.PublisherID,
+		Contributors: []dto.BookContributorReq{{AuthorID: author.ID, Role: "narrator"}},
+	}
+	w := doTest("POST", server.RootBook, params, token)
+	assert.Equal(t, 422, w.Code)
+
+	params.Contributors = []dto.BookContributorReq{{AuthorID: 999999, Role: "author"}}
+	w = doTest("POST", server.RootBook, params, token)
+	assert.Equal(t, 422, w.Code)
+}
+
+func TestAuthor_GetBooks_ByRole(t *testing.T) {
+	author := createAuthor(t)
+	other := createAuthor(t)
+	written := createBook(t, dto.BookDTO{Contributors: []dto.BookContributorReq{
+		{AuthorID: author.ID, Role: "author"},
+	}})
+	edited := createBook(t, dto.BookDTO{Contributors: []dto.BookContributorReq{
+		{AuthorID: other.ID, Role: "author"},
+		{AuthorID: author.ID, Role: "editor"},
+		{AuthorID: author.ID, Role: "illustrator"},
+	}})
+
+	works := getAuthorBooks(t, author.ID, "")
+	assert.Len(t, works, 3)
+	assert.Equal(t, []string{"author", "editor", "illustrator"},
+		[]string{works[0].Role, works[1].Role, works[2].Role})
+	assert.Equal(t, written.ID, works[0].BookID)
+	assert.Equal(t, edited.ID, works[1].BookID)
+
+	works = getAuthorBooks(t, author.ID, "?role=editor")
+	assert.Len(t, works, 1)
+	assert.Equal(t, edited.ID, works[0].BookID)
+	assert.Equal(t, edited.ISBN13, works[0].ISBN13)
+
+	// Deleted books are left out.
+	url := fmt.Sprintf("%s/%d", server.RootBook, written.ID)
+	w := doTest("DELETE", url, nil, createAuthAccessToken(dummyAdmin.Account.Username))
+	assert.Equal(t, 200, w.Code)
+	assert.Len(t, getAuthorBooks(t, author.ID, "?role=author"), 0)
+}
+
+func TestAuthor_GetBooks_Error(t *testing.T) {
+	w := doTest("GET", server.RootAuthor+"/999999/books", nil, "")
+	assert.Equal(t, 404, w.Code)
+
+	author := createAuthor(t)
+	w = doTest("GET", fmt.Sprintf("%s/%d/books?role=narrator", server.RootAuthor, author.ID), nil, "")
+	assert.Equal(t, 422, w.Code)
+}
@@ -0,0 +1,143 @@
+package integration_test
+
+import (
+	"base-gin/domain/dto"
+	"base-gin/server"
+	"base-gin/util"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func createBookCopy(t *testing.T, bookID uint) dto.BookCopyResp {
+	params := dto.BookCopyReq{
+		Barcode:       util.RandomStringAlpha(12),
+		AcquiredAtStr: "2024-01-15",
+		ShelfLocation: stringPtr("A-01"),
+	}
+
+	w := doTest(
+		"POST",
+		fmt.Sprintf("%s/%d/copies", server.RootBook, bookID),
+		params,
+		createAuthAccessToken(dummyAdmin.Account.Username),
+	)
+	assert.Equal(t, 201, w.Code)
+
+	var resp dto.SuccessResponse[dto.BookCopyResp]
+	_ = json.Unmarshal(w.Body.Bytes(), &resp)
+	return resp.Data
+}
+
+func getBook(t *testing.T, bookID uint) dto.BookResp {
+	w := doTest("GET", fmt.Sprintf("%s/%d", server.RootBook, bookID), nil, "")
+	assert.Equal(t, 200, w.Code)
+
+	var resp dto.SuccessResponse[dto.BookResp]
+	_ = json.Unmarshal(w.Body.Bytes(), &resp)
+	return resp.Data
+}
+
+func TestBookCopy_Create_Success(t *testing.T) {
+	book := createDummyBook()
+	item := createBookCopy(t, book.ID)
+	assert.Equal(t, "available", item.Status)
+	createBookCopy(t, book.ID)
+
+	resp := getBook(t, book.ID)
+	assert.Equal(t, 2, resp.TotalCopies)
+	assert.Equal(t, 2, resp.AvailableCopies)
+
+	w := doTest("GET", fmt.Sprintf("%s/%d/copies", server.RootBook, book.ID), nil, "")
+	assert.Equal(t, 200, w.Code)
+	assert.Contains(t, w.Body.String(), item.Barcode)
+}
+
+func TestBookCopy_Create_ErrorConflict(t *testing.T) {
+	book := createDummyBook()
+	item := createBookCopy(t, book.ID)
+
+	params := dto.BookCopyReq{Barcode: item.Barcode}
+	w := doTest(
+		"POST",
+		fmt.Sprintf("%s/%d/copies", server.RootBook, book.ID),
+		params,
+		createAuthAccessToken(dummyAdmin.Account.Username),
+	)
+	assert.Equal(t, 409, w.Code)
+}
+
+func TestBookCopy_Create_ErrorForbidden(t *testing.T) {
+	book := createDummyBook()
+	member := registerWithLogin(t)
+
+	params := dto.BookCopyReq{Barcode: util.RandomStringAlpha(12)}
+	w := doTest(
+		"POST",
+		fmt.Sprintf("%s/%d/copies", server.RootBook, book.ID),
+		params,
+		member.Token.AccessToken,
+	)
+	assert.Equal(t, 403, w.Code)
+}
+
+func TestBookCopy_Borrowing_Success(t *testing.T) {
+	accessToken := createAuthAccessToken(dummyAdmin.Account.Username)
+	book := createDummyBook()
+	first := createBookCopy(t, book.ID)
+	second := createBookCopy(t, book.ID)
+
+	// the first available copy is picked when none is given
+	w := checkout(book.ID, dummyMember.ID)
+	assert.Equal(t, 201, w.Code)
+	assert.Equal(t, 1, getBook(t, book.ID).AvailableCopies)
+
+	url := server.RootCirculation + server.PathCheckout
+	params := dto.CheckoutReq{PersonID: dummyMember.ID, Barcode: first.Barcode}
+	w = doTest("POST", url, params, accessToken)
+	assert.Equal(t, 409, w.Code)
+
+	params.Barcode = second.Barcode
+	w = doTest("POST", url, params, accessToken)
+	assert.Equal(t, 201, w.Code)
+	assert.Equal(t, 0, getBook(t, book.ID).AvailableCopies)
+
+	w = checkout(book.ID, dummyMember.ID)
+	assert.Equal(t, 409, w.Code)
+
+	// a copy on loan keeps its status until returned
+	update := dto.BookCopyReq{Barcode: first.Barcode, Status: "damaged"}
+	url = fmt.Sprintf("%s/%d/copies/%d", server.RootBook, book.ID, first.ID)
+	w = doTest("PUT", url, update, accessToken)
+	assert.Equal(t, 409, w.Code)
+
+	w = doTest("POST", server.RootCirculation+server.PathCheckin, dto.CheckinReq{Barcode: first.Barcode}, accessToken)
+	assert.Equal(t, 200, w.Code)
+	assert.Equal(t, 1, getBook(t, book.ID).AvailableCopies)
+
+	// copies with a borrowing history can only be withdrawn
+	w = doTest("DELETE", url, nil, accessToken)
+	assert.Equal(t, 409, w.Code)
+
+	update.Status = "withdrawn"
+	w = doTest("PUT", url, update, accessToken)
+	assert.Equal(t, 200, w.Code)
+}
+
+func TestBookCopy_Delete_Success(t *testing.T) {
+	book := createDummyBook()
+	item := createBookCopy(t, book.ID)
+
+	w := doTest(
+		"DELETE",
+		fmt.Sprintf("%s/%d/copies/%d", server.RootBook, book.ID, item.ID),
+		nil,
+		createAuthAccessToken(dummyAdmin.Account.Username),
+	)
+	assert.Equal(t, 200, w.Code)
+
+	w = doTest("GET", fmt.Sprintf("%s/%d/copies/%d", server.RootBook, book.ID, item.ID), nil, "")
+	assert.Equal(t, 404, w.Code)
+}
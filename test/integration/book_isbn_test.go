@@ -0,0 +1,117 @@
+package integration_test
+
+import (
+	"base-gin/domain/dto"
+	"base-gin/server"
+	"base-gin/util"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// randomISBN returns a new valid ISBN in both forms, the ISBN-10 hyphenated.
+func randomISBN() (string, string) {
+	body := util.RandomNumber(9)
+	for c := 0; c <= 10; c++ {
+		isbn10 := body + "0123456789X"[c:c+1]
+		if isbn13, err := util.ISBN10To13(isbn10); err == nil {
+			return isbn10[:1] + "-" + isbn10[1:5] + "-" + isbn10[5:9] + "-" + isbn10[9:], isbn13
+		}
+	}
+
+	panic("unreachable")
+}
+
+func getBookByISBN(isbn string) (int, dto.BookResp) {
+	w := doTest("GET", server.RootBook+"/isbn/"+isbn, nil, "")
+
+	var resp dto.SuccessResponse[dto.BookResp]
+	_ = json.Unmarshal(w.Body.Bytes(), &resp)
+	return w.Code, resp.Data
+}
+
+func TestBook_ISBN_Success(t *testing.T) {
+	isbn10, isbn13 := randomISBN()
+	book := createBook(t, dto.BookDTO{ISBN: &isbn10})
+	assert.Equal(t, strings.ReplaceAll(isbn10, "-", ""), *book.ISBN10)
+	assert.Equal(t, isbn13, *book.ISBN13)
+
+	code, found := getBookByISBN(isbn13)
+	assert.Equal(t, 200, code)
+	assert.Equal(t, book.ID, found.ID)
+}
+
+func TestBook_ISBN_Only13(t *testing.T) {
+	isbn13 := "979" + util.RandomNumber(9)
+	for c := 0; c <= 9; c++ {
+		if util.IsISBN(isbn13[:12] + fmt.Sprint(c)) {
+			isbn13 = isbn13[:12] + fmt.Sprint(c)
+			break
+		}
+	}
+	book := createBook(t, dto.BookDTO{ISBN: &isbn13})
+	assert.Nil(t, book.ISBN10)
+}
+
+func TestBook_ISBN_ErrorConflict(t *testing.T) {
+	isbn10, isbn13 := randomISBN()
+	book := createBook(t, dto.BookDTO{ISBN: &isbn13})
+
+	params := dto.BookDTO{
+		Title:       util.RandomStringAlpha(6),
+		Subtitle:    ptrToString(util.RandomStringAlpha(10)),
+		ISBN:        &isbn10,
+		PublisherID: book.PublisherID,
+		AuthorID:    book.AuthorID,
+	}
+	w := doTest("POST", server.RootBook, params, createAuthAccessToken(dummyAdmin.Account.Username))
+	assert.Equal(t, 409, w.Code)
+}
+
+func TestBook_ISBN_ErrorConflictOnUpdate(t *testing.T) {
+	isbn10, isbn13 := randomISBN()
+	existing := createBook(t, dto.BookDTO{ISBN: &isbn13})
+
+	book := createDummyBook()
+	params := dto.BookUpdate{
+		Title:       book.Title,
+		Subtitle:    ptrToString(util.RandomStringAlpha(10)),
+		ISBN:        &isbn10,
+		PublisherID: book.PublisherID,
+		AuthorID:    book.AuthorID,
+	}
+	url := fmt.Sprintf("%s/%d", server.RootBook, book.ID)
+	w := doTest("PUT", url, params, createAuthAccessToken(dummyAdmin.Account.Username))
+	assert.Equal(t, 409, w.Code)
+
+	// A book keeps its own ISBN.
+	params.ISBN = &isbn13
+	url = fmt.Sprintf("%s/%d", server.RootBook, existing.ID)
+	w = doTest("PUT", url, params, createAuthAccessToken(dummyAdmin.Account.Username))
+	assert.Equal(t, 200, w.Code)
+}
+
+func TestBook_ISBN_ErrorValidation(t *testing.T) {
+	book := createDummyBook()
+	params := dto.BookDTO{
+		Title:       util.RandomStringAlpha(6),
+		Subtitle:    ptrToString(util.RandomStringAlpha(10)),
+		ISBN:        ptrToString("0-306-40615-3"),
+		PublisherID: book.PublisherID,
+		AuthorID:    book.AuthorID,
+	}
+	w := doTest("POST", server.RootBook, params, createAuthAccessToken(dummyAdmin.Account.Username))
+	assert.Equal(t, 422, w.Code)
+}
+
+func TestBook_ISBN_ErrorLookup(t *testing.T) {
+	code, _ := getBookByISBN("12345")
+	assert.Equal(t, 400, code)
+
+	_, isbn13 := randomISBN()
+	code, _ = getBookByISBN(isbn13)
+	assert.Equal(t, 404, code)
+}
@@ -5,6 +5,7 @@ import (
 	"base-gin/domain/dto"
 	"base-gin/server"
 	"base-gin/util"
+	"context"
 	"fmt"
 	"testing"
 
@@ -19,8 +20,8 @@ func TestBook_Create_Success(t *testing.T) {
 	// Membuat data Publisher dan Author jika belum ada
 	publisher := dao.Publisher{Name: "Sample Publisher"}
 	author := dao.Author{FullName: "Sample Author"}
-	_ = publisherRepo.Create(&publisher)
-	_ = authorRepo.Create(&author)
+	_ = publisherRepo.Create(context.Background(), &publisher)
+	_ = authorRepo.Create(context.Background(), &author)
 
 	// Gunakan ID dari Publisher dan Author yang baru dibuat
 	params := dto.BookDTO{
@@ -47,7 +48,7 @@ func TestBook_Update_Success(t *testing.T) {
         Name: "Test Publisher " + util.RandomStringAlpha(4),
         City: "Test City",
     }
-    err := publisherRepo.Create(&publisher)
+    err := publisherRepo.Create(context.Background(), &publisher)
     assert.Nil(t, err)
     assert.NotZero(t, publisher.ID)
 
@@ -56,7 +57,7 @@ func TestBook_Update_Success(t *testing.T) {
         FullName: "Test Author " + util.RandomStringAlpha(4),
         Gender:   "m",
     }
-    err = authorRepo.Create(&author)
+    err = authorRepo.Create(context.Background(), &author)
     assert.Nil(t, err)
     assert.NotZero(t, author.ID)
 
@@ -67,7 +68,7 @@ func TestBook_Update_Success(t *testing.T) {
         PublisherID: publisher.ID,
         AuthorID:    author.ID,
     }
-    err = bookRepo.Create(&initialBook)
+    err = bookRepo.Create(context.Background(), &initialBook)
     assert.Nil(t, err)
     assert.NotZero(t, initialBook.ID)
 
@@ -90,7 +91,7 @@ func TestBook_Update_Success(t *testing.T) {
     assert.Equal(t, 200, w.Code)
 
     // 6. Verifikasi update
-    updatedBook, err := bookRepo.GetByID(initialBook.ID)
+    updatedBook, err := bookRepo.GetByID(context.Background(), initialBook.ID)
     assert.Nil(t, err)
     assert.Equal(t, updateParams.Title, updatedBook.Title)
     assert.Equal(t, updateParams.Subtitle, updatedBook.Subtitle)
@@ -104,7 +105,7 @@ func TestBook_Delete_Success(t *testing.T) {
         Name: "Test Publisher " + util.RandomStringAlpha(4),
         City: "Test City",
     }
-    err := publisherRepo.Create(&publisher)
+    err := publisherRepo.Create(context.Background(), &publisher)
     assert.Nil(t, err)
 
     // 2. Buat Author
@@ -112,7 +113,7 @@ func TestBook_Delete_Success(t *testing.T) {
         FullName: "Test Author " + util.RandomStringAlpha(4),
         Gender:   "m",
     }
-    err = authorRepo.Create(&author)
+    err = authorRepo.Create(context.Background(), &author)
     assert.Nil(t, err)
 
     // 3. Buat buku yang akan dihapus
@@ -122,7 +123,7 @@ func TestBook_Delete_Success(t *testing.T) {
         PublisherID: publisher.ID,
         AuthorID:    author.ID,
     }
-    err = bookRepo.Create(&b)
+    err = bookRepo.Create(context.Background(), &b)
     assert.Nil(t, err)
     assert.NotZero(t, b.ID)
 
@@ -137,7 +138,7 @@ func TestBook_Delete_Success(t *testing.T) {
     assert.Equal(t, 200, w.Code)
 
     // 5. Verifikasi book sudah terhapus
-    deletedBook, err := bookRepo.GetByIDUnscoped(b.ID)
+    deletedBook, err := bookRepo.GetByIDUnscoped(context.Background(), b.ID)
     assert.Nil(t, err)
     assert.NotNil(t, deletedBook.DeletedAt) // Verifikasi bahwa DeletedAt tidak nil
 }
@@ -146,7 +147,7 @@ func TestBook_GetList_Success(t *testing.T) {
 		Title:    util.RandomStringAlpha(6),
 		Subtitle: ptrToString(util.RandomStringAlpha(8)),
 	}
-	_ = bookRepo.Create(&b1)
+	_ = bookRepo.Create(context.Background(), &b1)
 
 	w := doTest(
 		"GET",
@@ -163,7 +164,7 @@ func TestBook_GetDetail_Success(t *testing.T) {
         Name: "Test Publisher " + util.RandomStringAlpha(4),
         City: "Test City",
     }
-    err := publisherRepo.Create(&publisher)
+    err := publisherRepo.Create(context.Background(), &publisher)
     assert.Nil(t, err)
 
     // 2. Buat Author
@@ -171,7 +172,7 @@ func TestBook_GetDetail_Success(t *testing.T) {
         FullName: "Test Author " + util.RandomStringAlpha(4),
         Gender:   "m",
     }
-    err = authorRepo.Create(&author)
+    err = authorRepo.Create(context.Background(), &author)
     assert.Nil(t, err)
 
     // 3. Buat buku
@@ -181,7 +182,7 @@ func TestBook_GetDetail_Success(t *testing.T) {
         PublisherID: publisher.ID,  // Tambahkan PublisherID
         AuthorID:    author.ID,     // Tambahkan AuthorID
     }
-    err = bookRepo.Create(&b)
+    err = bookRepo.Create(context.Background(), &b)
     assert.Nil(t, err)
     assert.NotZero(t, b.ID)
 
@@ -3,7 +3,10 @@ package integration_test
 import (
 	"base-gin/domain/dao"
 	"base-gin/domain/dto"
+	"base-gin/exception"
 	"base-gin/server"
+	"context"
+	"encoding/json"
 	"fmt"
 	"testing"
 	"time"
@@ -15,71 +18,6 @@ func ptrToTime(t time.Time) *time.Time {
 	return &t
 }
 
-func TestBorrowing_Create_Success(t *testing.T) {
-	params := dto.BorrowingDTO{
-		BorrowDate: time.Now(),
-		BookID:     1,
-		PersonID:   1,
-	}
-
-	w := doTest(
-		"POST",
-		server.RootBorrowing,
-		params,
-		createAuthAccessToken(dummyAdmin.Account.Username),
-	)
-	assert.Equal(t, 201, w.Code)
-}
-
-func TestBorrowing_Update_Success(t *testing.T) {
-    // Setup: Pastikan menggunakan data yang sudah ada di database
-    b := dao.Borrowing{
-        BorrowDate: time.Now(),
-        BookID:     1,  // Gunakan ID book yang sudah ada
-        PersonID:   1,  // Gunakan ID person yang sudah ada
-    }
-    err := borrowingRepo.Create(&b)
-    assert.Nil(t, err)
-    assert.NotZero(t, b.ID)
-
-    // Siapkan data update
-    returnDate := time.Now().AddDate(0, 0, 14)
-    params := dto.BorrowingUpdate{
-        ID:         b.ID,
-        ReturnDate: &returnDate,
-    }
-
-    // Kirim request update
-    w := doTest(
-        "PUT",
-        fmt.Sprintf("%s/%d", server.RootBorrowing, b.ID),
-        params,
-        createAuthAccessToken(dummyAdmin.Account.Username),
-    )
-
-    // Debug jika error
-    if w.Code != 200 {
-        t.Logf("Response Body: %s", w.Body.String())
-    }
-
-    assert.Equal(t, 200, w.Code)
-
-    // Verifikasi update
-    item, err := borrowingRepo.GetByID(b.ID)
-    assert.Nil(t, err)
-    
-    // Bandingkan dengan presisi detik
-    if item.ReturnDate != nil {
-        assert.Equal(t, 
-            returnDate.Unix(), 
-            item.ReturnDate.Unix(), 
-            "Return dates should match",
-        )
-    } else {
-        t.Fatalf("Return date should not be nil")
-    }
-}
-
 func TestBorrowing_Delete_Success(t *testing.T) {
     // Setup: Pastikan menggunakan data yang sudah ada di database
     b := dao.Borrowing{
@@ -87,7 +25,7 @@ func TestBorrowing_Delete_Success(t *testing.T) {
         BookID:     1,  // Gunakan ID book yang sudah ada
         PersonID:   1,  // Gunakan ID person yang sudah ada
     }
-    err := borrowingRepo.Create(&b)
+    err := borrowingRepo.Create(context.Background(), &b)
     assert.Nil(t, err)
     assert.NotZero(t, b.ID)
 
@@ -107,7 +45,7 @@ func TestBorrowing_Delete_Success(t *testing.T) {
     assert.Equal(t, 200, w.Code)
 
     // Verifikasi borrowing sudah terhapus
-    _, err = borrowingRepo.GetByID(b.ID)
+    _, err = borrowingRepo.GetByID(context.Background(), b.ID)
     assert.NotNil(t, err, "Borrowing should be deleted")
 }
 func TestBorrowing_GetList_Success(t *testing.T) {
@@ -116,30 +54,301 @@ func TestBorrowing_GetList_Success(t *testing.T) {
 		BookID:     1,
 		PersonID:   1,
 	}
-	_ = borrowingRepo.Create(&b1)
+	_ = borrowingRepo.Create(context.Background(), &b1)
 
 	w := doTest(
 		"GET",
 		server.RootBorrowing,
 		nil,
-		"",
+		createAuthAccessToken(dummyAdmin.Account.Username),
 	)
 	assert.Equal(t, 200, w.Code)
 }
 
+func TestBorrowing_GetList_ErrorAccessToken(t *testing.T) {
+	w := doTest("GET", server.RootBorrowing, nil, "")
+	assert.Equal(t, 401, w.Code)
+}
+
+func TestBorrowing_GetList_MemberOwnOnly(t *testing.T) {
+	member := registerWithLogin(t)
+	person, _ := personRepo.GetByAccountID(context.Background(), member.ID)
+
+	own := dao.Borrowing{BorrowDate: time.Now(), BookID: 1, PersonID: person.ID}
+	_ = borrowingRepo.Create(context.Background(), &own)
+	other := dao.Borrowing{BorrowDate: time.Now(), BookID: 1, PersonID: dummyMember.ID}
+	_ = borrowingRepo.Create(context.Background(), &other)
+
+	w := doTest("GET", server.RootBorrowing, nil, member.Token.AccessToken)
+	assert.Equal(t, 200, w.Code)
+
+	var resp dto.SuccessResponse[[]dto.BorrowingResp]
+	_ = json.Unmarshal(w.Body.Bytes(), &resp)
+	assert.Len(t, resp.Data, 1)
+
+	w = doTest("GET", fmt.Sprintf("%s/%d", server.RootBorrowing, own.ID), nil, member.Token.AccessToken)
+	assert.Equal(t, 200, w.Code)
+
+	w = doTest("GET", fmt.Sprintf("%s/%d", server.RootBorrowing, other.ID), nil, member.Token.AccessToken)
+	assert.Equal(t, 404, w.Code)
+}
+
 func TestBorrowing_GetDetail_Success(t *testing.T) {
 	b := dao.Borrowing{
 		BorrowDate: time.Now(),
 		BookID:     1,
 		PersonID:   1,
 	}
-	_ = borrowingRepo.Create(&b)
+	_ = borrowingRepo.Create(context.Background(), &b)
 
 	w := doTest(
 		"GET",
 		fmt.Sprintf("%s/%d", server.RootBorrowing, b.ID),
 		nil,
-		"",
+		createAuthAccessToken(dummyAdmin.Account.Username),
+	)
+	assert.Equal(t, 200, w.Code)
+}
+
+func TestBorrowing_GetOverdue_Success(t *testing.T) {
+	book := createDummyBook()
+	late := dao.Borrowing{
+		BorrowDate: time.Now().AddDate(0, 0, -24),
+		DueDate:    time.Now().AddDate(0, 0, -10),
+		BookID:     book.ID,
+		PersonID:   dummyMember.ID,
+	}
+	_ = borrowingRepo.Create(context.Background(), &late)
+	slightlyLate := dao.Borrowing{
+		BorrowDate: time.Now().AddDate(0, 0, -16),
+		DueDate:    time.Now().AddDate(0, 0, -2),
+		BookID:     book.ID,
+		PersonID:   dummyMember.ID,
+	}
+	_ = borrowingRepo.Create(context.Background(), &slightlyLate)
+	returned := dao.Borrowing{
+		BorrowDate: time.Now().AddDate(0, 0, -24),
+		DueDate:    time.Now().AddDate(0, 0, -10),
+		ReturnDate: ptrToTime(time.Now()),
+		BookID:     book.ID,
+		PersonID:   dummyMember.ID,
+	}
+	_ = borrowingRepo.Create(context.Background(), &returned)
+
+	w := doTest(
+		"GET",
+		server.RootBorrowing+server.PathOverdue+"?min_days=5",
+		nil,
+		createAuthAccessToken(dummyAdmin.Account.Username),
 	)
 	assert.Equal(t, 200, w.Code)
+
+	var resp dto.SuccessResponse[[]dto.BorrowingResp]
+	_ = json.Unmarshal(w.Body.Bytes(), &resp)
+
+	ids := make(map[uint]dto.BorrowingResp)
+	for _, item := range resp.Data {
+		assert.True(t, item.Overdue)
+		assert.GreaterOrEqual(t, item.DaysOverdue, 5)
+		ids[item.ID] = item
+	}
+	assert.Contains(t, ids, late.ID)
+	assert.NotContains(t, ids, slightlyLate.ID)
+	assert.NotContains(t, ids, returned.ID)
+
+	item := ids[late.ID]
+	assert.Equal(t, "overdue", item.Status)
+	assert.Equal(t, 10, item.DaysOverdue)
+	if assert.NotNil(t, item.Book) && assert.NotNil(t, item.Person) {
+		assert.Equal(t, book.Title, item.Book.Title)
+		assert.Equal(t, dummyMember.Fullname, item.Person.Fullname)
+	}
+}
+
+func TestBorrowing_GetOverdue_ErrorForbidden(t *testing.T) {
+	member := registerWithLogin(t)
+
+	w := doTest("GET", server.RootBorrowing+server.PathOverdue, nil, member.Token.AccessToken)
+	assert.Equal(t, 403, w.Code)
+}
+
+func TestBorrowing_Renew_Success(t *testing.T) {
+	member := registerWithLogin(t)
+	person, _ := personRepo.GetByAccountID(context.Background(), member.ID)
+	borrowing := dao.Borrowing{
+		BorrowDate: time.Now(),
+		DueDate:    time.Now().AddDate(0, 0, 3),
+		BookID:     createDummyBook().ID,
+		PersonID:   person.ID,
+	}
+	_ = borrowingRepo.Create(context.Background(), &borrowing)
+	url := fmt.Sprintf("%s/%d/renew", server.RootBorrowing, borrowing.ID)
+
+	dueDate := borrowing.DueDate
+	for i := 1; i <= cfg.Circulation.MaxRenewals; i++ {
+		w := doTest("POST", url, nil, member.Token.AccessToken)
+		assert.Equal(t, 200, w.Code)
+
+		var resp dto.SuccessResponse[dto.BorrowingResp]
+		_ = json.Unmarshal(w.Body.Bytes(), &resp)
+		dueDate = dueDate.AddDate(0, 0, cfg.Circulation.LoanPeriodDays)
+		assert.Equal(t, i, resp.Data.RenewalCount)
+		assert.Equal(t, dueDate.Unix(), resp.Data.DueDate.Unix())
+	}
+
+	w := doTest("POST", url, nil, member.Token.AccessToken)
+	assert.Equal(t, 422, w.Code)
+	assert.Equal(t, exception.PolicyRenewalLimit, getPolicyViolation(w).Code)
+
+	w = doTest("GET", fmt.Sprintf("%s/%d/renewals", server.RootBorrowing, borrowing.ID), nil, member.Token.AccessToken)
+	assert.Equal(t, 200, w.Code)
+
+	var resp dto.SuccessResponse[[]dto.BorrowingRenewalResp]
+	_ = json.Unmarshal(w.Body.Bytes(), &resp)
+	if assert.Len(t, resp.Data, cfg.Circulation.MaxRenewals) {
+		assert.Equal(t, borrowing.DueDate.Unix(), resp.Data[0].PreviousDueDate.Unix())
+	}
+}
+
+func TestBorrowing_Renew_ErrorOverdue(t *testing.T) {
+	borrowing := dao.Borrowing{
+		BorrowDate: time.Now().AddDate(0, 0, -30),
+		DueDate:    time.Now().AddDate(0, 0, -(cfg.Circulation.RenewMaxOverdueDays + 2)),
+		BookID:     createDummyBook().ID,
+		PersonID:   dummyMember.ID,
+	}
+	_ = borrowingRepo.Create(context.Background(), &borrowing)
+
+	url := fmt.Sprintf("%s/%d/renew", server.RootBorrowing, borrowing.ID)
+	w := doTest("POST", url, nil, createAuthAccessToken(dummyAdmin.Account.Username))
+	assert.Equal(t, 422, w.Code)
+	assert.Equal(t, exception.PolicyRenewOverdue, getPolicyViolation(w).Code)
+}
+
+func TestBorrowing_Renew_ErrorNotOwn(t *testing.T) {
+	member := registerWithLogin(t)
+	borrowing := dao.Borrowing{
+		BorrowDate: time.Now(),
+		DueDate:    time.Now().AddDate(0, 0, 3),
+		BookID:     createDummyBook().ID,
+		PersonID:   dummyMember.ID,
+	}
+	_ = borrowingRepo.Create(context.Background(), &borrowing)
+
+	url := fmt.Sprintf("%s/%d/renew", server.RootBorrowing, borrowing.ID)
+	w := doTest("POST", url, nil, member.Token.AccessToken)
+	assert.Equal(t, 404, w.Code)
+}
+
+func getBorrowings(t *testing.T, url string, token string) []dto.BorrowingResp {
+	w := doTest("GET", url, nil, token)
+	assert.Equal(t, 200, w.Code)
+
+	var resp dto.SuccessResponse[[]dto.BorrowingResp]
+	_ = json.Unmarshal(w.Body.Bytes(), &resp)
+	return resp.Data
+}
+
+// createHistory gives the person a returned, an overdue and an active
+// borrowing, the oldest first.
+func createHistory(personID uint) []dao.Borrowing {
+	now := time.Now()
+	items := []dao.Borrowing{
+		{
+			BorrowDate: now.AddDate(0, 0, -30),
+			DueDate:    now.AddDate(0, 0, -16),
+			ReturnDate: ptrToTime(now.AddDate(0, 0, -20)),
+		},
+		{BorrowDate: now.AddDate(0, 0, -20), DueDate: now.AddDate(0, 0, -6)},
+		{BorrowDate: now, DueDate: now.AddDate(0, 0, 14)},
+	}
+	for i := range items {
+		items[i].BookID = createDummyBook().ID
+		items[i].PersonID = personID
+		_ = borrowingRepo.Create(context.Background(), &items[i])
+	}
+
+	return items
+}
+
+func TestBorrowing_History_Person(t *testing.T) {
+	member := registerWithLogin(t)
+	person, _ := personRepo.GetByAccountID(context.Background(), member.ID)
+	history := createHistory(person.ID)
+	url := fmt.Sprintf("%s/%d/borrowings", server.RootPerson, person.ID)
+
+	items := getBorrowings(t, url, member.Token.AccessToken)
+	if assert.Len(t, items, 3) {
+		assert.Equal(t, history[2].ID, items[0].ID)
+		assert.NotNil(t, items[0].Book)
+		assert.NotNil(t, items[0].Person)
+	}
+
+	for i, status := range []string{"returned", "overdue", "active"} {
+		items = getBorrowings(t, url+"?status="+status, member.Token.AccessToken)
+		if assert.Len(t, items, 1, status) {
+			assert.Equal(t, history[i].ID, items[0].ID)
+			assert.Equal(t, status, items[0].Status)
+		}
+	}
+
+	items = getBorrowings(t, url+"?s=1&l=1", member.Token.AccessToken)
+	if assert.Len(t, items, 1) {
+		assert.Equal(t, history[1].ID, items[0].ID)
+	}
+
+	from := time.Now().AddDate(0, 0, -25).Format("2006-01-02")
+	to := time.Now().AddDate(0, 0, -1).Format("2006-01-02")
+	items = getBorrowings(t, url+"?from="+from+"&to="+to, member.Token.AccessToken)
+	if assert.Len(t, items, 1) {
+		assert.Equal(t, history[1].ID, items[0].ID)
+	}
+
+	w := doTest("GET", url+"?status=lost", nil, member.Token.AccessToken)
+	assert.Equal(t, 422, w.Code)
+
+	other := registerWithLogin(t)
+	w = doTest("GET", url, nil, other.Token.AccessToken)
+	assert.Equal(t, 403, w.Code)
+
+	items = getBorrowings(t, url, createAuthAccessToken(dummyAdmin.Account.Username))
+	assert.Len(t, items, 3)
+}
+
+func TestBorrowing_History_Book(t *testing.T) {
+	member := registerWithLogin(t)
+	person, _ := personRepo.GetByAccountID(context.Background(), member.ID)
+	history := createHistory(person.ID)
+	url := fmt.Sprintf("%s/%d/borrowings", server.RootBook, history[0].BookID)
+
+	items := getBorrowings(t, url, createAuthAccessToken(dummyAdmin.Account.Username))
+	if assert.Len(t, items, 1) {
+		assert.Equal(t, history[0].ID, items[0].ID)
+		assert.Equal(t, person.ID, items[0].Person.ID)
+	}
+
+	w := doTest("GET", url, nil, member.Token.AccessToken)
+	assert.Equal(t, 403, w.Code)
+
+	w = doTest(
+		"GET",
+		fmt.Sprintf("%s/%d/borrowings", server.RootBook, 999999),
+		nil,
+		createAuthAccessToken(dummyAdmin.Account.Username),
+	)
+	assert.Equal(t, 404, w.Code)
+}
+
+func TestBorrowing_History_Own(t *testing.T) {
+	member := registerWithLogin(t)
+	person, _ := personRepo.GetByAccountID(context.Background(), member.ID)
+	createHistory(person.ID)
+
+	items := getBorrowings(t, server.RootAccount+server.PathMyBorrowings+"?status=overdue", member.Token.AccessToken)
+	if assert.Len(t, items, 1) {
+		assert.Equal(t, person.ID, items[0].PersonID)
+	}
+
+	w := doTest("GET", server.RootAccount+server.PathMyBorrowings, nil, "")
+	assert.Equal(t, 401, w.Code)
 }
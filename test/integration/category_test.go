@@ -0,0 +1,276 @@
+package integration_test
+
+import (
+	"base-gin/domain/dto"
+	"base-gin/exception"
+	"base-gin/server"
+	"base-gin/util"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// createCategory creates a category through the API and returns it.
+func createCategory(t *testing.T, parentID *uint) dto.CategoryResp {
+	params := dto.CategoryReq{ParentID: parentID, Name: util.RandomStringAlpha(10)}
+	w := doTest("POST", server.RootCategory, params, createAuthAccessToken(dummyAdmin.Account.Username))
+	assert.Equal(t, 201, w.Code)
+
+	var resp dto.SuccessResponse[dto.CategoryResp]
+	_ = json.Unmarshal(w.Body.Bytes(), &resp)
+	return resp.Data
+}
+
+func getCategory(t *testing.T, id uint) dto.CategoryResp {
+	w := doTest("GET", fmt.Sprintf("%s/%d", server.RootCategory, id), nil, "")
+	assert.Equal(t, 200, w.Code)
+
+	var resp dto.SuccessResponse[dto.CategoryResp]
+	_ = json.Unmarshal(w.Body.Bytes(), &resp)
+	return resp.Data
+}
+
+// childByID returns the child of the category with the given ID.
+func childByID(t *testing.T, item dto.CategoryResp, id uint) dto.CategoryResp {
+	for _, child := range item.Children {
+		if child.ID == id {
+			return child
+		}
+	}
+	t.Fatalf("category %d missing from the children of %d", id, item.ID)
+	return dto.CategoryResp{}
+}
+
+func TestCategory_Create_Success(t *testing.T) {
+	parent := createCategory(t, nil)
+
+	params := dto.CategoryReq{
+		ParentID:    &parent.ID,
+		Name:        "Sejarah Dunia " + util.RandomStringAlpha(4),
+		Description: ptrToString("Buku sejarah"),
+	}
+	w := doTest("POST", server.RootCategory, params, createAuthAccessToken(dummyAdmin.Account.Username))
+	assert.Equal(t, 201, w.Code)
+
+	var resp dto.SuccessResponse[dto.CategoryResp]
+	_ = json.Unmarshal(w.Body.Bytes(), &resp)
+	assert.Equal(t, &parent.ID, resp.Data.ParentID)
+	assert.Equal(t, util.Slugify(params.Name), resp.Data.Slug)
+	assert.Equal(t, "Buku sejarah", *resp.Data.Description)
+
+	child := getCategory(t, resp.Data.ID)
+	assert.Equal(t, []dto.CategoryRefResp{
+		{ID: parent.ID, Name: parent.Name, Slug: parent.Slug},
+	}, child.Path)
+}
+
+func TestCategory_Create_Invalid(t *testing.T) {
+	token := createAuthAccessToken(dummyAdmin.Account.Username)
+	existing := createCategory(t, nil)
+
+	params := dto.CategoryReq{Name: util.RandomStringAlpha(8), Slug: existing.Slug}
+	w := doTest("POST", server.RootCategory, params, token)
+	assert.Equal(t, 409, w.Code)
+	assert.Contains(t, w.Body.String(), exception.ErrCategoryConflict.Error())
+
+	params = dto.CategoryReq{Name: util.RandomStringAlpha(8), Slug: "--"}
+	w = doTest("POST", server.RootCategory, params, token)
+	assert.Equal(t, 422, w.Code)
+	assert.Contains(t, w.Body.String(), exception.ErrSlugInvalid.Error())
+
+	missing := uint(999999)
+	params = dto.CategoryReq{Name: util.RandomStringAlpha(8), ParentID: &missing}
+	w = doTest("POST", server.RootCategory, params, token)
+	assert.Equal(t, 422, w.Code)
+	assert.Contains(t, w.Body.String(), exception.ErrCategoryNotFound.Error())
+}
+
+func TestCategory_Create_Forbidden(t *testing.T) {
+	params := dto.CategoryReq{Name: util.RandomStringAlpha(8)}
+
+	w := doTest("POST", server.RootCategory, params, "")
+	assert.Equal(t, 401, w.Code)
+
+	member := registerWithLogin(t)
+	w = doTest("POST", server.RootCategory, params, member.Token.AccessToken)
+	assert.Equal(t, 403, w.Code)
+}
+
+func TestCategory_Update_Cycle(t *testing.T) {
+	token := createAuthAccessToken(dummyAdmin.Account.Username)
+	top := createCategory(t, nil)
+	middle := createCategory(t, &top.ID)
+	bottom := createCategory(t, &middle.ID)
+
+	for _, parentID := range []uint{top.ID, bottom.ID} {
+		params := dto.CategoryReq{ParentID: &parentID, Name: top.Name, Slug: top.Slug}
+		w := doTest("PUT", fmt.Sprintf("%s/%d", server.RootCategory, top.ID), params, token)
+		assert.Equal(t, 422, w.Code)
+		assert.Contains(t, w.Body.String(), exception.ErrCategoryCycle.Error())
+	}
+
+	// Moving the bottom one to the top is fine.
+	params := dto.CategoryReq{Name: "Renamed " + bottom.Name}
+	w := doTest("PUT", fmt.Sprintf("%s/%d", server.RootCategory, bottom.ID), params, token)
+	assert.Equal(t, 200, w.Code)
+
+	item := getCategory(t, bottom.ID)
+	assert.Nil(t, item.ParentID)
+	assert.Empty(t, item.Path)
+	assert.Equal(t, util.Slugify(params.Name), item.Slug)
+	assert.Empty(t, getCategory(t, middle.ID).Children)
+}
+
+func TestCategory_Delete(t *testing.T) {
+	token := createAuthAccessToken(dummyAdmin.Account.Username)
+	parent := createCategory(t, nil)
+	child := createCategory(t, &parent.ID)
+	book := createBook(t, dto.BookDTO{CategoryIDs: []uint{child.ID}})
+
+	w := doTest("DELETE", fmt.Sprintf("%s/%d", server.RootCategory, parent.ID), nil, token)
+	assert.Equal(t, 409, w.Code)
+	assert.Contains(t, w.Body.String(), exception.ErrCategoryHasChildren.Error())
+
+	w = doTest("DELETE", fmt.Sprintf("%s/%d", server.RootCategory, child.ID), nil, token)
+	assert.Equal(t, 200, w.Code)
+
+	w = doTest("GET", fmt.Sprintf("%s/%d", server.RootCategory, child.ID), nil, "")
+	assert.Equal(t, 404, w.Code)
+
+	// The book is kept but no longer filed under it.
+	_, resp := getBookByISBN(*book.ISBN13)
+	assert.Empty(t, resp.Categories)
+}
+
+func TestCategory_Counts(t *testing.T) {
+	top := createCategory(t, nil)
+	left := createCategory(t, &top.ID)
+	right := createCategory(t, &top.ID)
+	leaf := createCategory(t, &left.ID)
+
+	createBook(t, dto.BookDTO{CategoryIDs: []uint{top.ID}})
+	createBook(t, dto.BookDTO{CategoryIDs: []uint{left.ID, right.ID}})
+	createBook(t, dto.BookDTO{CategoryIDs: []uint{leaf.ID, leaf.ID}})
+
+	item := getCategory(t, top.ID)
+	assert.Equal(t, 1, item.BookCount)
+	// A book filed under two categories of the subtree is counted once.
+	assert.Equal(t, 3, item.TotalBookCount)
+	assert.Len(t, item.Children, 2)
+	assert.Equal(t, 1, childByID(t, item, left.ID).BookCount)
+	assert.Equal(t, 2, childByID(t, item, left.ID).TotalBookCount)
+	assert.Equal(t, 1, childByID(t, item, right.ID).TotalBookCount)
+
+	w := doTest("GET", server.RootCategory, nil, "")
+	assert.Equal(t, 200, w.Code)
+
+	var resp dto.SuccessResponse[[]dto.CategoryResp]
+	_ = json.Unmarshal(w.Body.Bytes(), &resp)
+	for _, root := range resp.Data {
+		if root.ID != top.ID {
+			continue
+		}
+		assert.Equal(t, 3, root.TotalBookCount)
+		assert.Equal(t, 1, childByID(t, childByID(t, root, left.ID), leaf.ID).BookCount)
+		return
+	}
+	t.Fatal("category missing from the tree")
+}
+
+func TestCategory_Books(t *testing.T) {
+	parent := createCategory(t, nil)
+	child := createCategory(t, &parent.ID)
+	first := createBook(t, dto.BookDTO{CategoryIDs: []uint{parent.ID}})
+	second := createBook(t, dto.BookDTO{CategoryIDs: []uint{child.ID, parent.ID}})
+	createBook(t, dto.BookDTO{})
+
+	assert.Len(t, second.Categories, 2)
+
+	w := doTest("GET", fmt.Sprintf("%s/%d/books", server.RootCategory, parent.ID), nil, "")
+	assert.Equal(t, 200, w.Code)
+
+	var resp dto.SuccessResponse[[]dto.BookResp]
+	_ = json.Unmarshal(w.Body.Bytes(), &resp)
+	ids := []uint{}
+	for _, book := range resp.Data {
+		ids = append(ids, book.ID)
+	}
+	assert.ElementsMatch(t, []uint{first.ID, second.ID}, ids)
+	assert.Equal(t, int64(2), resp.Meta.Total)
+
+	w = doTest("GET", fmt.Sprintf("%s/%d/books?l=1", server.RootCategory, child.ID), nil, "")
+	assert.Equal(t, 200, w.Code)
+	resp = dto.SuccessResponse[[]dto.BookResp]{}
+	_ = json.Unmarshal(w.Body.Bytes(), &resp)
+	assert.Len(t, resp.Data, 1)
+	assert.Equal(t, second.ID, resp.Data[0].ID)
+
+	// Paged by cursor in the order asked for.
+	w = doTest("GET", fmt.Sprintf("%s/%d/books?l=1&sort=-id", server.RootCategory, parent.ID), nil, "")
+	assert.Equal(t, 200, w.Code)
+	resp = dto.SuccessResponse[[]dto.BookResp]{}
+	_ = json.Unmarshal(w.Body.Bytes(), &resp)
+	assert.Equal(t, second.ID, resp.Data[0].ID)
+	assert.NotEmpty(t, resp.Meta.NextCursor)
+
+	path := fmt.Sprintf("%s/%d/books?l=1&sort=-id&cursor=%s", server.RootCategory, parent.ID, resp.Meta.NextCursor)
+	w = doTest("GET", path, nil, "")
+	assert.Equal(t, 200, w.Code)
+	resp = dto.SuccessResponse[[]dto.BookResp]{}
+	_ = json.Unmarshal(w.Body.Bytes(), &resp)
+	assert.Equal(t, first.ID, resp.Data[0].ID)
+	assert.Empty(t, resp.Meta.NextCursor)
+
+	w = doTest("GET", fmt.Sprintf("%s/%d/books?q=%s", server.RootCategory, parent.ID, first.Title), nil, "")
+	assert.Equal(t, 200, w.Code)
+	resp = dto.SuccessResponse[[]dto.BookResp]{}
+	_ = json.Unmarshal(w.Body.Bytes(), &resp)
+	assert.Len(t, resp.Data, 1)
+	assert.Equal(t, first.ID, resp.Data[0].ID)
+
+	w = doTest("GET", fmt.Sprintf("%s/%d/books?sort=shelf", server.RootCategory, parent.ID), nil, "")
+	assert.Equal(t, 422, w.Code)
+
+	w = doTest("GET", fmt.Sprintf("%s/%d/books", server.RootCategory, 999999), nil, "")
+	assert.Equal(t, 404, w.Code)
+}
+
+func TestBook_Categories_Invalid(t *testing.T) {
+	book := createDummyBook()
+	params := dto.BookDTO{
+		Title:       util.RandomStringAlpha(6),
+		Subtitle:    ptrToString(util.RandomStringAlpha(10)),
+		PublisherID: book.PublisherID,
+		AuthorID:    book.AuthorID,
+		CategoryIDs: []uint{999999},
+	}
+	w := doTest("POST", server.RootBook, params, createAuthAccessToken(dummyAdmin.Account.Username))
+	assert.Equal(t, 422, w.Code)
+	assert.Contains(t, w.Body.String(), exception.ErrCategoryNotFound.Error())
+}
+
+func TestBook_Categories_Update(t *testing.T) {
+	first := createCategory(t, nil)
+	second := createCategory(t, nil)
+	book := createBook(t, dto.BookDTO{CategoryIDs: []uint{first.ID}})
+
+	params := dto.BookUpdate{
+		Title:       book.Title,
+		Subtitle:    book.Subtitle,
+		PublisherID: book.PublisherID,
+		AuthorID:    book.AuthorID,
+		CategoryIDs: []uint{second.ID},
+	}
+	w := doTest("PUT", fmt.Sprintf("%s/%d", server.RootBook, book.ID), params,
+		createAuthAccessToken(dummyAdmin.Account.Username))
+	assert.Equal(t, 200, w.Code)
+
+	w = doTest("GET", fmt.Sprintf("%s/%d", server.RootBook, book.ID), nil, "")
+	var resp dto.SuccessResponse[dto.BookResp]
+	_ = json.Unmarshal(w.Body.Bytes(), &resp)
+	assert.Equal(t, []dto.CategoryRefResp{
+		{ID: second.ID, Name: second.Name, Slug: second.Slug},
+	}, resp.Data.Categories)
+}
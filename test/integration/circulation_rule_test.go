@@ -0,0 +1,170 @@
+package integration_test
+
+import (
+	"base-gin/domain/dto"
+	"base-gin/exception"
+	"base-gin/repository"
+	"base-gin/server"
+	"base-gin/util"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func getPolicyViolation(w *httptest.ResponseRecorder) dto.PolicyViolationResp {
+	var resp struct {
+		Errors dto.PolicyViolationResp `json:"errors"`
+	}
+	_ = json.Unmarshal(w.Body.Bytes(), &resp)
+	return resp.Errors
+}
+
+func createRule(t *testing.T, params dto.CirculationRuleReq) dto.CirculationRuleResp {
+	w := doTest(
+		"POST",
+		server.RootAdmin+server.PathAdminRules,
+		params,
+		createAuthAccessToken(dummyAdmin.Account.Username),
+	)
+	assert.Equal(t, 201, w.Code)
+
+	var resp dto.SuccessResponse[dto.CirculationRuleResp]
+	_ = json.Unmarshal(w.Body.Bytes(), &resp)
+	return resp.Data
+}
+
+// registerPatron registers a member in a new patron category, so that rules
+// made for it do not affect the other tests, and returns the person's ID.
+func registerPatron(t *testing.T) (uint, string) {
+	member := registerWithLogin(t)
+	person, _ := personRepo.GetByAccountID(context.Background(), member.ID)
+
+	category := "cat-" + util.RandomStringAlpha(8)
+	w := doTest(
+		"PUT",
+		fmt.Sprintf("%s/persons/%d/category", server.RootAdmin, person.ID),
+		dto.PersonCategoryReq{Category: category},
+		createAuthAccessToken(dummyAdmin.Account.Username),
+	)
+	assert.Equal(t, 200, w.Code)
+
+	return person.ID, category
+}
+
+func TestCirculationRule_CRUD_Success(t *testing.T) {
+	accessToken := createAuthAccessToken(dummyAdmin.Account.Username)
+	category := "cat-" + util.RandomStringAlpha(8)
+	params := dto.CirculationRuleReq{
+		PatronCategory: category,
+		ItemType:       "*",
+		MaxLoans:       3,
+		LoanPeriodDays: 7,
+		MaxRenewals:    1,
+	}
+	rule := createRule(t, params)
+	assert.Equal(t, category, rule.PatronCategory)
+
+	w := doTest("POST", server.RootAdmin+server.PathAdminRules, params, accessToken)
+	assert.Equal(t, 409, w.Code)
+
+	url := fmt.Sprintf("%s/circulation-rules/%d", server.RootAdmin, rule.ID)
+	params.MaxLoans = 5
+	w = doTest("PUT", url, params, accessToken)
+	assert.Equal(t, 200, w.Code)
+
+	w = doTest("GET", url, nil, accessToken)
+	assert.Equal(t, 200, w.Code)
+	var resp dto.SuccessResponse[dto.CirculationRuleResp]
+	_ = json.Unmarshal(w.Body.Bytes(), &resp)
+	assert.Equal(t, 5, resp.Data.MaxLoans)
+
+	w = doTest("DELETE", url, nil, accessToken)
+	assert.Equal(t, 200, w.Code)
+	w = doTest("GET", url, nil, accessToken)
+	assert.Equal(t, 404, w.Code)
+}
+
+func TestCirculationRule_Create_ErrorConflict(t *testing.T) {
+	params := dto.CirculationRuleReq{
+		PatronCategory: "cat-" + util.RandomStringAlpha(8),
+		ItemType:       "*",
+		MaxLoans:       3,
+		LoanPeriodDays: 7,
+	}
+	createRule(t, params)
+
+	// A rule racing past the scope check still meets the index.
+	item := params.ToEntity()
+	err := repository.GetCirculationRuleRepo().Create(context.Background(), &item)
+	assert.ErrorIs(t, err, exception.ErrRuleConflict)
+}
+
+func TestCirculationRule_ErrorForbidden(t *testing.T) {
+	member := registerWithLogin(t)
+
+	w := doTest("GET", server.RootAdmin+server.PathAdminRules, nil, member.Token.AccessToken)
+	assert.Equal(t, 403, w.Code)
+}
+
+func TestCirculationRule_LoanLimit(t *testing.T) {
+	accessToken := createAuthAccessToken(dummyAdmin.Account.Username)
+	personID, category := registerPatron(t)
+	rule := createRule(t, dto.CirculationRuleReq{
+		PatronCategory: category,
+		ItemType:       "*",
+		MaxLoans:       1,
+		LoanPeriodDays: 5,
+		MaxRenewals:    0,
+	})
+
+	w := checkout(createDummyBook().ID, personID)
+	assert.Equal(t, 201, w.Code)
+
+	items, _ := borrowingRepo.GetListByPersonID(context.Background(), personID)
+	if assert.Len(t, items, 1) {
+		assert.Equal(t, items[0].BorrowDate.AddDate(0, 0, 5).Unix(), items[0].DueDate.Unix())
+	}
+
+	w = checkout(createDummyBook().ID, personID)
+	assert.Equal(t, 422, w.Code)
+	violation := getPolicyViolation(w)
+	assert.Equal(t, exception.PolicyLoanLimit, violation.Code)
+	assert.Equal(t, &rule.ID, violation.RuleID)
+
+	w = doTest("POST", fmt.Sprintf("%s/%d/renew", server.RootBorrowing, items[0].ID), nil, accessToken)
+	assert.Equal(t, 422, w.Code)
+	assert.Equal(t, exception.PolicyRenewalLimit, getPolicyViolation(w).Code)
+}
+
+func TestCirculationRule_MostSpecificWins(t *testing.T) {
+	personID, category := registerPatron(t)
+	createRule(t, dto.CirculationRuleReq{
+		PatronCategory: category,
+		ItemType:       "*",
+		MaxLoans:       1,
+		LoanPeriodDays: 5,
+	})
+	createRule(t, dto.CirculationRuleReq{
+		PatronCategory: category,
+		ItemType:       "book",
+		MaxLoans:       2,
+		LoanPeriodDays: 10,
+	})
+
+	for i := 0; i < 2; i++ {
+		w := checkout(createDummyBook().ID, personID)
+		assert.Equal(t, 201, w.Code)
+	}
+
+	items, _ := borrowingRepo.GetListByPersonID(context.Background(), personID)
+	for _, item := range items {
+		assert.Equal(t, item.BorrowDate.AddDate(0, 0, 10).Unix(), item.DueDate.Unix())
+	}
+
+	w := checkout(createDummyBook().ID, personID)
+	assert.Equal(t, 422, w.Code)
+}
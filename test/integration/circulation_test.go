@@ -0,0 +1,111 @@
+package integration_test
+
+import (
+	"base-gin/domain/dto"
+	"base-gin/server"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// checkout lends the book out to the person as an admin.
+func checkout(bookID, personID uint) *httptest.ResponseRecorder {
+	return doTest(
+		"POST",
+		server.RootCirculation+server.PathCheckout,
+		dto.CheckoutReq{BookID: bookID, PersonID: personID},
+		createAuthAccessToken(dummyAdmin.Account.Username),
+	)
+}
+
+// checkin returns the person's loan of the book as an admin.
+func checkin(bookID, personID uint) *httptest.ResponseRecorder {
+	return doTest(
+		"POST",
+		server.RootCirculation+server.PathCheckin,
+		dto.CheckinReq{BookID: bookID, PersonID: personID},
+		createAuthAccessToken(dummyAdmin.Account.Username),
+	)
+}
+
+func TestCirculation_Checkout_Success(t *testing.T) {
+	book := createDummyBook()
+
+	w := checkout(book.ID, dummyMember.ID)
+	assert.Equal(t, 201, w.Code)
+
+	var resp dto.SuccessResponse[dto.BorrowingResp]
+	_ = json.Unmarshal(w.Body.Bytes(), &resp)
+	assert.Equal(t, book.ID, resp.Data.BookID)
+	assert.Equal(t, "active", resp.Data.Status)
+	assert.WithinDuration(t, time.Now(), resp.Data.BorrowDate, time.Minute)
+	expected := resp.Data.BorrowDate.AddDate(0, 0, cfg.Circulation.LoanPeriodDays)
+	assert.Equal(t, expected.Unix(), resp.Data.DueDate.Unix())
+}
+
+func TestCirculation_Checkout_ErrorForbidden(t *testing.T) {
+	member := registerWithLogin(t)
+	params := dto.CheckoutReq{BookID: createDummyBook().ID, PersonID: dummyMember.ID}
+
+	w := doTest("POST", server.RootCirculation+server.PathCheckout, params, member.Token.AccessToken)
+	assert.Equal(t, 403, w.Code)
+}
+
+func TestCirculation_Checkout_ErrorValidation(t *testing.T) {
+	params := dto.CheckoutReq{PersonID: dummyMember.ID}
+
+	w := doTest(
+		"POST",
+		server.RootCirculation+server.PathCheckout,
+		params,
+		createAuthAccessToken(dummyAdmin.Account.Username),
+	)
+	assert.Equal(t, 422, w.Code)
+}
+
+func TestCirculation_Checkout_AlreadyBorrowed(t *testing.T) {
+	book := createDummyBook()
+
+	w := checkout(book.ID, dummyMember.ID)
+	assert.Equal(t, 201, w.Code)
+
+	w = checkout(book.ID, dummyAdmin.ID)
+	assert.Equal(t, 409, w.Code)
+}
+
+func TestCirculation_Checkout_BookNotFound(t *testing.T) {
+	w := checkout(999999, dummyMember.ID)
+	assert.Equal(t, 404, w.Code)
+}
+
+func TestCirculation_Checkin_Success(t *testing.T) {
+	book := createDummyBook()
+
+	w := checkout(book.ID, dummyMember.ID)
+	assert.Equal(t, 201, w.Code)
+
+	w = checkin(book.ID, dummyMember.ID)
+	assert.Equal(t, 200, w.Code)
+
+	var resp dto.SuccessResponse[dto.BorrowingResp]
+	_ = json.Unmarshal(w.Body.Bytes(), &resp)
+	assert.Equal(t, "returned", resp.Data.Status)
+	if assert.NotNil(t, resp.Data.ReturnDate) {
+		assert.WithinDuration(t, time.Now(), *resp.Data.ReturnDate, time.Minute)
+	}
+
+	// the book can be lent out again, but not returned twice
+	w = checkin(book.ID, dummyMember.ID)
+	assert.Equal(t, 409, w.Code)
+
+	w = checkout(book.ID, dummyAdmin.ID)
+	assert.Equal(t, 201, w.Code)
+}
+
+func TestCirculation_Checkin_NotFound(t *testing.T) {
+	w := checkin(createDummyBook().ID, dummyMember.ID)
+	assert.Equal(t, 404, w.Code)
+}
@@ -0,0 +1,121 @@
+package integration_test
+
+import (
+	"base-gin/domain/dao"
+	"base-gin/domain/dto"
+	"base-gin/server"
+	"base-gin/service"
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// returnLate lends a book to the person 10 days past due, returns it through
+// the API and gives the fine expected for it.
+func returnLate(t *testing.T, personID uint) int64 {
+	book := createDummyBook()
+	borrowing := dao.Borrowing{
+		BorrowDate: time.Now().AddDate(0, 0, -24),
+		DueDate:    time.Now().AddDate(0, 0, -10),
+		BookID:     book.ID,
+		PersonID:   personID,
+	}
+	_ = borrowingRepo.Create(context.Background(), &borrowing)
+
+	w := checkin(book.ID, personID)
+	assert.Equal(t, 200, w.Code)
+
+	return service.NewFinePolicy(&cfg.Circulation).Calculate(10)
+}
+
+func getFineLedger(t *testing.T, personID uint, token string) dto.FineLedgerResp {
+	w := doTest("GET", fmt.Sprintf("%s/persons/%d", server.RootFine, personID), nil, token)
+	assert.Equal(t, 200, w.Code)
+
+	var resp dto.SuccessResponse[dto.FineLedgerResp]
+	_ = json.Unmarshal(w.Body.Bytes(), &resp)
+	return resp.Data
+}
+
+func TestFine_LateReturn_Charged(t *testing.T) {
+	member := registerWithLogin(t)
+	person, _ := personRepo.GetByAccountID(context.Background(), member.ID)
+
+	expected := returnLate(t, person.ID)
+	assert.Greater(t, expected, int64(0))
+
+	ledger := getFineLedger(t, person.ID, member.Token.AccessToken)
+	assert.Equal(t, expected, ledger.Balance)
+	if assert.Len(t, ledger.Entries, 1) {
+		assert.Equal(t, "charge", ledger.Entries[0].Type)
+		assert.Equal(t, expected, ledger.Entries[0].Amount)
+		assert.NotNil(t, ledger.Entries[0].BorrowingID)
+	}
+
+	w := doTest("GET", server.RootFine, nil, createAuthAccessToken(dummyAdmin.Account.Username))
+	assert.Equal(t, 200, w.Code)
+
+	var resp dto.SuccessResponse[[]dto.FineBalanceResp]
+	_ = json.Unmarshal(w.Body.Bytes(), &resp)
+	var found bool
+	for _, item := range resp.Data {
+		if item.PersonID == person.ID {
+			found = true
+			assert.Equal(t, expected, item.Balance)
+			assert.Equal(t, person.Fullname, item.Fullname)
+		}
+	}
+	assert.True(t, found)
+}
+
+func TestFine_PaymentAndWaiver_Success(t *testing.T) {
+	accessToken := createAuthAccessToken(dummyAdmin.Account.Username)
+	member := registerWithLogin(t)
+	person, _ := personRepo.GetByAccountID(context.Background(), member.ID)
+	balance := returnLate(t, person.ID)
+
+	paymentURL := fmt.Sprintf("%s/persons/%d/payments", server.RootFine, person.ID)
+	w := doTest("POST", paymentURL, dto.FinePaymentReq{Amount: balance + 1}, accessToken)
+	assert.Equal(t, 409, w.Code)
+
+	w = doTest("POST", paymentURL, dto.FinePaymentReq{Amount: balance / 2}, accessToken)
+	assert.Equal(t, 201, w.Code)
+
+	waiverURL := fmt.Sprintf("%s/persons/%d/waivers", server.RootFine, person.ID)
+	w = doTest("POST", waiverURL, dto.FineWaiverReq{Amount: balance - balance/2}, accessToken)
+	assert.Equal(t, 422, w.Code)
+
+	params := dto.FineWaiverReq{Amount: balance - balance/2, Reason: "buku dikembalikan saat libur"}
+	w = doTest("POST", waiverURL, params, accessToken)
+	assert.Equal(t, 201, w.Code)
+
+	ledger := getFineLedger(t, person.ID, accessToken)
+	assert.Equal(t, int64(0), ledger.Balance)
+	assert.Len(t, ledger.Entries, 3)
+
+	w = doTest("GET", server.RootFine, nil, accessToken)
+	var resp dto.SuccessResponse[[]dto.FineBalanceResp]
+	_ = json.Unmarshal(w.Body.Bytes(), &resp)
+	for _, item := range resp.Data {
+		assert.NotEqual(t, person.ID, item.PersonID)
+	}
+}
+
+func TestFine_ErrorForbidden(t *testing.T) {
+	member := registerWithLogin(t)
+
+	url := fmt.Sprintf("%s/persons/%d", server.RootFine, dummyMember.ID)
+	w := doTest("GET", url, nil, member.Token.AccessToken)
+	assert.Equal(t, 403, w.Code)
+
+	w = doTest("GET", server.RootFine, nil, member.Token.AccessToken)
+	assert.Equal(t, 403, w.Code)
+
+	params := dto.FinePaymentReq{Amount: 1000}
+	w = doTest("POST", url+"/payments", params, member.Token.AccessToken)
+	assert.Equal(t, 403, w.Code)
+}
@@ -0,0 +1,230 @@
+package integration_test
+
+import (
+	"base-gin/domain/dao"
+	"base-gin/domain/dto"
+	"base-gin/server"
+	"base-gin/service"
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func placeHold(t *testing.T, bookID uint, accessToken string) dto.HoldResp {
+	w := doTest("POST", server.RootHold, dto.HoldReq{BookID: bookID}, accessToken)
+	assert.Equal(t, 201, w.Code)
+
+	var resp dto.SuccessResponse[dto.HoldResp]
+	_ = json.Unmarshal(w.Body.Bytes(), &resp)
+	return resp.Data
+}
+
+func getHolds(t *testing.T, query string, accessToken string) []dto.HoldResp {
+	w := doTest("GET", server.RootHold+query, nil, accessToken)
+	assert.Equal(t, 200, w.Code)
+
+	var resp dto.SuccessResponse[[]dto.HoldResp]
+	_ = json.Unmarshal(w.Body.Bytes(), &resp)
+	return resp.Data
+}
+
+// lendOut lends the book to the person and returns the borrowing.
+func lendOut(t *testing.T, bookID, personID uint) dao.Borrowing {
+	w := checkout(bookID, personID)
+	assert.Equal(t, 201, w.Code)
+
+	items, _ := borrowingRepo.GetListByPersonID(context.Background(), personID)
+	for _, item := range items {
+		if item.BookID == bookID && item.ReturnDate == nil {
+			return item
+		}
+	}
+	t.Fatal("borrowing not found")
+	return dao.Borrowing{}
+}
+
+func returnBorrowing(t *testing.T, borrowing dao.Borrowing) {
+	w := checkin(borrowing.BookID, borrowing.PersonID)
+	assert.Equal(t, 200, w.Code)
+}
+
+func TestHold_Queue_Success(t *testing.T) {
+	book := createDummyBook()
+	bookCopy := createBookCopy(t, book.ID)
+	first := registerWithLogin(t)
+	second := registerWithLogin(t)
+
+	// nobody needs to queue while a copy is on the shelf
+	w := doTest("POST", server.RootHold, dto.HoldReq{BookID: book.ID}, first.Token.AccessToken)
+	assert.Equal(t, 409, w.Code)
+
+	borrowing := lendOut(t, book.ID, dummyMember.ID)
+
+	firstHold := placeHold(t, book.ID, first.Token.AccessToken)
+	assert.Equal(t, "waiting", firstHold.Status)
+	assert.Equal(t, 1, firstHold.Position)
+	secondHold := placeHold(t, book.ID, second.Token.AccessToken)
+	assert.Equal(t, 2, secondHold.Position)
+
+	w = doTest("POST", server.RootHold, dto.HoldReq{BookID: book.ID}, first.Token.AccessToken)
+	assert.Equal(t, 409, w.Code)
+
+	// others queueing for the book keep the loan from being renewed
+	w = doTest("POST", fmt.Sprintf("%s/%d/renew", server.RootBorrowing, borrowing.ID), nil,
+		createAuthAccessToken(dummyAdmin.Account.Username))
+	assert.Equal(t, 409, w.Code)
+
+	returnBorrowing(t, borrowing)
+	assert.Equal(t, 0, getBook(t, book.ID).AvailableCopies)
+
+	holds := getHolds(t, "", first.Token.AccessToken)
+	if assert.Len(t, holds, 1) {
+		assert.Equal(t, "ready", holds[0].Status)
+		assert.Equal(t, &bookCopy.ID, holds[0].BookCopyID)
+		assert.NotNil(t, holds[0].ExpiresAt)
+	}
+	holds = getHolds(t, "", second.Token.AccessToken)
+	if assert.Len(t, holds, 1) {
+		assert.Equal(t, 1, holds[0].Position)
+	}
+
+	// the copy set aside can only go to the person it is held for
+	secondPerson, _ := personRepo.GetByAccountID(context.Background(), second.ID)
+	w = checkout(book.ID, secondPerson.ID)
+	assert.Equal(t, 409, w.Code)
+
+	firstPerson, _ := personRepo.GetByAccountID(context.Background(), first.ID)
+	loan := lendOut(t, book.ID, firstPerson.ID)
+	assert.Equal(t, &bookCopy.ID, loan.BookCopyID)
+
+	holds = getHolds(t, "?status=fulfilled", first.Token.AccessToken)
+	assert.Len(t, holds, 1)
+}
+
+func TestHold_Cancel_PassesOn(t *testing.T) {
+	book := createDummyBook()
+	first := registerWithLogin(t)
+	second := registerWithLogin(t)
+
+	borrowing := lendOut(t, book.ID, dummyMember.ID)
+	firstHold := placeHold(t, book.ID, first.Token.AccessToken)
+	placeHold(t, book.ID, second.Token.AccessToken)
+
+	returnBorrowing(t, borrowing)
+
+	// the book has no copies, so it is set aside as a whole
+	secondPerson, _ := personRepo.GetByAccountID(context.Background(), second.ID)
+	w := checkout(book.ID, secondPerson.ID)
+	assert.Equal(t, 409, w.Code)
+
+	w = doTest("DELETE", fmt.Sprintf("%s/%d", server.RootHold, firstHold.ID), nil, second.Token.AccessToken)
+	assert.Equal(t, 404, w.Code)
+
+	w = doTest("DELETE", fmt.Sprintf("%s/%d", server.RootHold, firstHold.ID), nil, first.Token.AccessToken)
+	assert.Equal(t, 200, w.Code)
+
+	w = doTest("DELETE", fmt.Sprintf("%s/%d", server.RootHold, firstHold.ID), nil, first.Token.AccessToken)
+	assert.Equal(t, 409, w.Code)
+
+	holds := getHolds(t, "", second.Token.AccessToken)
+	if assert.Len(t, holds, 1) {
+		assert.Equal(t, "ready", holds[0].Status)
+	}
+
+	lendOut(t, book.ID, secondPerson.ID)
+}
+
+func TestHold_Expire_PassesOn(t *testing.T) {
+	book := createDummyBook()
+	createBookCopy(t, book.ID)
+	first := registerWithLogin(t)
+	second := registerWithLogin(t)
+
+	borrowing := lendOut(t, book.ID, dummyMember.ID)
+	firstHold := placeHold(t, book.ID, first.Token.AccessToken)
+	placeHold(t, book.ID, second.Token.AccessToken)
+	returnBorrowing(t, borrowing)
+
+	db.Model(&dao.Hold{}).Where("id = ?", firstHold.ID).
+		Update("expires_at", time.Now().Add(-time.Minute))
+
+	// Listing holds leaves them as they are, the job expiring them.
+	accessToken := createAuthAccessToken(dummyAdmin.Account.Username)
+	holds := getHolds(t, "?status=expired", first.Token.AccessToken)
+	assert.Empty(t, holds)
+
+	w := doTest("POST", server.RootAdmin+"/jobs/"+service.JobExpireHolds+"/run", nil, accessToken)
+	assert.Equal(t, 202, w.Code)
+	waitJobRun(t, service.JobExpireHolds, accessToken)
+
+	holds = getHolds(t, fmt.Sprintf("?book_id=%d", book.ID), accessToken)
+	if assert.Len(t, holds, 1) {
+		assert.Equal(t, "ready", holds[0].Status)
+		assert.NotEqual(t, firstHold.ID, holds[0].ID)
+	}
+
+	holds = getHolds(t, "?status=expired", first.Token.AccessToken)
+	assert.Len(t, holds, 1)
+	assert.Equal(t, 0, getBook(t, book.ID).AvailableCopies)
+}
+
+func TestHold_Place_ExpiresBookHolds(t *testing.T) {
+	book := createDummyBook()
+	createBookCopy(t, book.ID)
+	first := registerWithLogin(t)
+	second := registerWithLogin(t)
+
+	borrowing := lendOut(t, book.ID, dummyMember.ID)
+	firstHold := placeHold(t, book.ID, first.Token.AccessToken)
+	placeHold(t, book.ID, second.Token.AccessToken)
+	returnBorrowing(t, borrowing)
+
+	db.Model(&dao.Hold{}).Where("id = ?", firstHold.ID).
+		Update("expires_at", time.Now().Add(-time.Minute))
+
+	// The stale hold no longer counts as queued, the copy going to the next.
+	hold := placeHold(t, book.ID, first.Token.AccessToken)
+	assert.Equal(t, "waiting", hold.Status)
+
+	holds := getHolds(t, "?status=ready", second.Token.AccessToken)
+	assert.Len(t, holds, 1)
+}
+
+func TestHold_NewCopy_PassesOn(t *testing.T) {
+	accessToken := createAuthAccessToken(dummyAdmin.Account.Username)
+	book := createDummyBook()
+	lost := createBookCopy(t, book.ID)
+	first := registerWithLogin(t)
+	second := registerWithLogin(t)
+
+	update := dto.BookCopyReq{Barcode: lost.Barcode, Status: "lost"}
+	url := fmt.Sprintf("%s/%d/copies/%d", server.RootBook, book.ID, lost.ID)
+	w := doTest("PUT", url, update, accessToken)
+	assert.Equal(t, 200, w.Code)
+	placeHold(t, book.ID, first.Token.AccessToken)
+	placeHold(t, book.ID, second.Token.AccessToken)
+
+	// A copy added goes to the first in the queue rather than the shelf.
+	added := createBookCopy(t, book.ID)
+	assert.Equal(t, "on_hold", added.Status)
+	holds := getHolds(t, "", first.Token.AccessToken)
+	if assert.Len(t, holds, 1) {
+		assert.Equal(t, "ready", holds[0].Status)
+		assert.Equal(t, &added.ID, holds[0].BookCopyID)
+	}
+
+	// So does one found again.
+	update.Status = "available"
+	w = doTest("PUT", url, update, accessToken)
+	assert.Equal(t, 200, w.Code)
+	holds = getHolds(t, "", second.Token.AccessToken)
+	if assert.Len(t, holds, 1) {
+		assert.Equal(t, "ready", holds[0].Status)
+		assert.Equal(t, &lost.ID, holds[0].BookCopyID)
+	}
+	assert.Equal(t, 0, getBook(t, book.ID).AvailableCopies)
+}
@@ -0,0 +1,193 @@
+package integration_test
+
+import (
+	"base-gin/domain/dto"
+	"base-gin/exception"
+	"base-gin/server"
+	"base-gin/util"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type importResp struct {
+	dto.CatalogImportResp
+	Errors []struct {
+		Line   int                          `json:"line"`
+		Errors []server.BindingErrorMessage `json:"errors"`
+	} `json:"errors"`
+}
+
+func doImport(body *bytes.Buffer, contentType, query, token string) *httptest.ResponseRecorder {
+	r, _ := http.NewRequest("POST", server.RootImport+server.PathImportCatalog+query, body)
+	r.Header.Set("Content-Type", contentType)
+	r.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, r)
+	return w
+}
+
+func importCatalog(t *testing.T, csv, query string) importResp {
+	w := doImport(bytes.NewBufferString(csv), "text/csv", query,
+		createAuthAccessToken(dummyAdmin.Account.Username))
+	assert.Equal(t, 200, w.Code, w.Body.String())
+
+	var resp dto.SuccessResponse[importResp]
+	_ = json.Unmarshal(w.Body.Bytes(), &resp)
+	return resp.Data
+}
+
+// catalogCSV returns a CSV of three books by a new publisher: the first two
+// valid, both crediting a new author, the third without a title.
+func catalogCSV(publisher, author, existingAuthor string) (string, []string) {
+	titles := []string{util.RandomStringAlpha(10), util.RandomStringAlpha(10)}
+	isbn, _ := randomISBN()
+	csv := "Title,Subtitle,ISBN,Publisher,Publisher_City,Authors\n" +
+		fmt.Sprintf("%s,Jilid satu,%s,%s,Jakarta,%s\n", titles[0], isbn, publisher, author) +
+		fmt.Sprintf("\"%s\",Jilid dua,,%s,,\"%s; %s (translator)\"\n",
+			titles[1], strings.ToUpper(publisher), author, strings.ToLower(existingAuthor)) +
+		fmt.Sprintf(",Tanpa judul,,%s,,%s\n", publisher, author)
+
+	return csv, titles
+}
+
+func TestImport_Catalog_Success(t *testing.T) {
+	existing := createAuthor(t)
+	publisher := util.RandomStringAlpha(12)
+	author := util.RandomStringAlpha(12)
+	csv, titles := catalogCSV(publisher, author, existing.FullName)
+
+	resp := importCatalog(t, csv, "")
+	assert.False(t, resp.DryRun)
+	assert.Equal(t, 3, resp.Rows)
+	assert.Equal(t, 2, resp.Imported)
+	assert.Equal(t, 1, resp.Failed)
+	assert.Equal(t, 1, resp.PublishersCreated)
+	assert.Equal(t, 1, resp.AuthorsCreated)
+	if assert.Len(t, resp.Errors, 1) {
+		assert.Equal(t, 4, resp.Errors[0].Line)
+		assert.Equal(t, "title", resp.Errors[0].Errors[0].Field)
+	}
+
+	if assert.Len(t, resp.BookIDs, 2) {
+		first := getBook(t, resp.BookIDs[0])
+		second := getBook(t, resp.BookIDs[1])
+		assert.Equal(t, titles[0], first.Title)
+		assert.Equal(t, first.PublisherID, second.PublisherID)
+		assert.Equal(t, first.AuthorID, second.AuthorID)
+		if assert.Len(t, second.Contributors, 2) {
+			assert.Equal(t, existing.ID, second.Contributors[1].AuthorID)
+			assert.Equal(t, "translator", second.Contributors[1].Role)
+		}
+	}
+
+	item, err := publisherRepo.GetByName(context.Background(), publisher)
+	if assert.Nil(t, err) {
+		assert.Equal(t, "Jakarta", item.City)
+	}
+	assert.Equal(t, []uint{resp.BookIDs[1]}, searchHitIDs(searchBooks(t, "q="+titles[1])))
+}
+
+func TestImport_Catalog_DryRun(t *testing.T) {
+	existing := createAuthor(t)
+	publisher := util.RandomStringAlpha(12)
+	author := util.RandomStringAlpha(12)
+	csv, _ := catalogCSV(publisher, author, existing.FullName)
+
+	resp := importCatalog(t, csv, "?dry_run=true")
+	assert.True(t, resp.DryRun)
+	assert.Equal(t, 2, resp.Imported)
+	assert.Equal(t, 1, resp.Failed)
+	assert.Equal(t, 1, resp.PublishersCreated)
+	assert.Equal(t, 1, resp.AuthorsCreated)
+	assert.Empty(t, resp.BookIDs)
+	assert.Len(t, resp.Errors, 1)
+
+	_, err := publisherRepo.GetByName(context.Background(), publisher)
+	assert.ErrorIs(t, err, exception.ErrDataNotFound)
+	_, err = authorRepo.GetByName(context.Background(), author)
+	assert.ErrorIs(t, err, exception.ErrDataNotFound)
+}
+
+func TestImport_Catalog_RowErrors(t *testing.T) {
+	publisher := util.RandomStringAlpha(12)
+	author := util.RandomStringAlpha(12)
+	isbn, _ := randomISBN()
+	csv := "title,subtitle,isbn,publisher,publisher_city,authors\n" +
+		fmt.Sprintf("%s,Jilid satu,%s,%s,Bandung,%s\n", util.RandomStringAlpha(8), isbn, publisher, author) +
+		// The ISBN is taken by the row above.
+		fmt.Sprintf("%s,Jilid dua,%s,%s,,%s\n", util.RandomStringAlpha(8), isbn, publisher, author) +
+		// A new publisher needs a city.
+		fmt.Sprintf("%s,Jilid tiga,,%s,,%s\n", util.RandomStringAlpha(8), util.RandomStringAlpha(12), author) +
+		fmt.Sprintf("%s,Jilid empat,,%s,,\n", util.RandomStringAlpha(8), publisher) +
+		fmt.Sprintf("%s,Jilid lima,,%s,,%s (penyunting)\n", util.RandomStringAlpha(8), publisher, util.RandomStringAlpha(12))
+
+	resp := importCatalog(t, csv, "")
+	assert.Equal(t, 1, resp.Imported)
+	assert.Equal(t, 4, resp.Failed)
+	// The author of the invalid role is not kept.
+	assert.Equal(t, 1, resp.AuthorsCreated)
+
+	fields := make(map[int]string)
+	for _, row := range resp.Errors {
+		if assert.NotEmpty(t, row.Errors) {
+			fields[row.Line] = row.Errors[0].Field
+		}
+	}
+	assert.Equal(t, map[int]string{3: "isbn", 4: "publisher.city", 5: "authors", 6: "role"}, fields)
+}
+
+func TestImport_Catalog_Multipart(t *testing.T) {
+	author := createAuthor(t)
+	title := util.RandomStringAlpha(10)
+	csv := "title,subtitle,publisher,publisher_city,authors\n" +
+		fmt.Sprintf("%s,Jilid satu,%s,Surabaya,%s\n", title, util.RandomStringAlpha(12), author.FullName)
+
+	body := &bytes.Buffer{}
+	form := multipart.NewWriter(body)
+	part, _ := form.CreateFormFile("file", "catalog.csv")
+	_, _ = part.Write([]byte(csv))
+	_ = form.Close()
+
+	w := doImport(body, form.FormDataContentType(), "",
+		createAuthAccessToken(dummyAdmin.Account.Username))
+	assert.Equal(t, 200, w.Code)
+
+	var resp dto.SuccessResponse[importResp]
+	_ = json.Unmarshal(w.Body.Bytes(), &resp)
+	if assert.Len(t, resp.Data.BookIDs, 1) {
+		book := getBook(t, resp.Data.BookIDs[0])
+		assert.Equal(t, title, book.Title)
+		assert.Equal(t, author.ID, book.AuthorID)
+	}
+}
+
+func TestImport_Catalog_ErrorInvalid(t *testing.T) {
+	token := createAuthAccessToken(dummyAdmin.Account.Username)
+	for _, csv := range []string{
+		"",
+		"title,publisher,authors\n",
+		"title,publisher\nBuku,Penerbit\n",
+		"title,publisher,authors,price\nBuku,Penerbit,Penulis,1000\n",
+		"title,publisher,authors\n\"Buku,Penerbit,Penulis\n",
+	} {
+		w := doImport(bytes.NewBufferString(csv), "text/csv", "", token)
+		assert.Equal(t, 422, w.Code, csv)
+	}
+}
+
+func TestImport_Catalog_ErrorForbidden(t *testing.T) {
+	member := registerWithLogin(t)
+	csv := "title,publisher,authors\nBuku,Penerbit,Penulis\n"
+
+	w := doImport(bytes.NewBufferString(csv), "text/csv", "", member.Token.AccessToken)
+	assert.Equal(t, 403, w.Code)
+}
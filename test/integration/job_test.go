@@ -0,0 +1,115 @@
+package integration_test
+
+import (
+	"base-gin/domain"
+	"base-gin/domain/dto"
+	"base-gin/server"
+	"base-gin/service"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// waitJobRun polls the runs of the job until its latest run has been recorded
+// as finished.
+func waitJobRun(t *testing.T, name, accessToken string) dto.JobRunResp {
+	for i := 0; i < 100; i++ {
+		w := doTest("GET", server.RootAdmin+server.PathAdminJobRuns+"?l=1&job="+name, nil, accessToken)
+		assert.Equal(t, 200, w.Code)
+
+		var resp dto.SuccessResponse[[]dto.JobRunResp]
+		_ = json.Unmarshal(w.Body.Bytes(), &resp)
+		if len(resp.Data) == 1 && resp.Data[0].FinishedAt != nil {
+			return resp.Data[0]
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	t.Fatalf("job %s did not finish", name)
+	return dto.JobRunResp{}
+}
+
+func TestJob_GetList_Success(t *testing.T) {
+	accessToken := createAuthAccessToken(dummyAdmin.Account.Username)
+
+	w := doTest("GET", server.RootAdmin+server.PathAdminJobs, nil, accessToken)
+	assert.Equal(t, 200, w.Code)
+
+	var resp dto.SuccessResponse[[]dto.JobResp]
+	_ = json.Unmarshal(w.Body.Bytes(), &resp)
+
+	names := make([]string, len(resp.Data))
+	for i, job := range resp.Data {
+		names[i] = job.Name
+		assert.NotEmpty(t, job.Spec)
+	}
+	assert.ElementsMatch(t, []string{
+		service.JobSendReminders,
+		service.JobExpireHolds,
+		service.JobOverdueSummary,
+		service.JobPurgeExpired,
+		service.JobReindexSearch,
+	}, names)
+}
+
+func TestJob_Trigger_Success(t *testing.T) {
+	accessToken := createAuthAccessToken(dummyAdmin.Account.Username)
+
+	w := doTest("POST", server.RootAdmin+"/jobs/"+service.JobOverdueSummary+"/run", nil, accessToken)
+	assert.Equal(t, 202, w.Code)
+
+	var resp dto.SuccessResponse[dto.JobRunResp]
+	_ = json.Unmarshal(w.Body.Bytes(), &resp)
+	assert.Equal(t, string(domain.JobTriggerManual), resp.Data.Trigger)
+
+	run := waitJobRun(t, service.JobOverdueSummary, accessToken)
+	assert.Equal(t, resp.Data.ID, run.ID)
+	assert.Equal(t, string(domain.JobSucceeded), run.Status)
+	assert.NotNil(t, run.Message)
+	assert.NotNil(t, run.FinishedAt)
+
+	w = doTest("GET", server.RootAdmin+server.PathAdminJobs, nil, accessToken)
+	var jobs dto.SuccessResponse[[]dto.JobResp]
+	_ = json.Unmarshal(w.Body.Bytes(), &jobs)
+	for _, job := range jobs.Data {
+		if job.Name == service.JobOverdueSummary {
+			assert.Equal(t, run.ID, job.LastRun.ID)
+		}
+	}
+}
+
+func TestJob_Trigger_Purge(t *testing.T) {
+	accessToken := createAuthAccessToken(dummyAdmin.Account.Username)
+
+	w := doTest("POST", server.RootAdmin+"/jobs/"+service.JobPurgeExpired+"/run", nil, accessToken)
+	assert.Equal(t, 202, w.Code)
+
+	run := waitJobRun(t, service.JobPurgeExpired, accessToken)
+	assert.Equal(t, string(domain.JobSucceeded), run.Status)
+}
+
+func TestJob_Trigger_ErrorNotFound(t *testing.T) {
+	accessToken := createAuthAccessToken(dummyAdmin.Account.Username)
+
+	w := doTest("POST", server.RootAdmin+"/jobs/unknown/run", nil, accessToken)
+	assert.Equal(t, 404, w.Code)
+}
+
+func TestJob_GetRuns_ErrorValidation(t *testing.T) {
+	accessToken := createAuthAccessToken(dummyAdmin.Account.Username)
+
+	w := doTest("GET", server.RootAdmin+server.PathAdminJobRuns+"?status=done", nil, accessToken)
+	assert.Equal(t, 422, w.Code)
+}
+
+func TestJob_ErrorForbidden(t *testing.T) {
+	user := registerWithLogin(t)
+
+	w := doTest("GET", server.RootAdmin+server.PathAdminJobs, nil, user.Token.AccessToken)
+	assert.Equal(t, 403, w.Code)
+
+	w = doTest("POST", server.RootAdmin+"/jobs/"+service.JobOverdueSummary+"/run", nil, user.Token.AccessToken)
+	assert.Equal(t, 403, w.Code)
+}
@@ -0,0 +1,181 @@
+package integration_test
+
+import (
+	"base-gin/domain/dao"
+	"base-gin/domain/dto"
+	"base-gin/server"
+	"base-gin/util"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func getPublisherPage(t *testing.T, query string) dto.SuccessResponse[[]dto.PublisherResp] {
+	w := doTest("GET", server.RootPublisher+"?"+query, nil, "")
+	assert.Equal(t, 200, w.Code)
+
+	var resp dto.SuccessResponse[[]dto.PublisherResp]
+	_ = json.Unmarshal(w.Body.Bytes(), &resp)
+	return resp
+}
+
+func publisherNames(items []dto.PublisherResp) []string {
+	names := make([]string, len(items))
+	for i, item := range items {
+		names[i] = item.Name
+	}
+	return names
+}
+
+// createPublisher creates a publisher in the city and returns it.
+func createPublisher(t *testing.T, city string) dao.Publisher {
+	publisher := dao.Publisher{Name: util.RandomStringAlpha(10), City: city}
+	assert.Nil(t, publisherRepo.Create(context.Background(), &publisher))
+	return publisher
+}
+
+// createPublishersIn creates n publishers in a new city and returns the city
+// along with their names, descending.
+func createPublishersIn(t *testing.T, n int) (string, []string) {
+	city := util.RandomStringAlpha(12)
+	names := make([]string, n)
+	for i := range names {
+		names[i] = createPublisher(t, city).Name
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(names)))
+
+	return city, names
+}
+
+func TestList_Offset(t *testing.T) {
+	city, names := createPublishersIn(t, 5)
+
+	resp := getPublisherPage(t, "city="+city+"&sort=-name&l=2")
+	assert.Equal(t, names[:2], publisherNames(resp.Data))
+	assert.Equal(t, int64(5), resp.Meta.Total)
+	assert.Equal(t, 2, resp.Meta.Limit)
+	assert.NotEmpty(t, resp.Meta.NextCursor)
+	assert.Empty(t, resp.Meta.PrevCursor)
+
+	resp = getPublisherPage(t, "city="+city+"&sort=-name&l=2&s=4")
+	assert.Equal(t, names[4:], publisherNames(resp.Data))
+	assert.Empty(t, resp.Meta.NextCursor)
+	assert.NotEmpty(t, resp.Meta.PrevCursor)
+
+	// Without a limit every item comes at once.
+	resp = getPublisherPage(t, "city="+city+"&sort=-name")
+	assert.Equal(t, names, publisherNames(resp.Data))
+	assert.Equal(t, int64(5), resp.Meta.Total)
+	assert.Empty(t, resp.Meta.NextCursor)
+}
+
+func TestList_Cursor(t *testing.T) {
+	city, names := createPublishersIn(t, 5)
+	query := "city=" + city + "&sort=-name&l=2"
+
+	first := getPublisherPage(t, query)
+	second := getPublisherPage(t, query+"&cursor="+first.Meta.NextCursor)
+	assert.Equal(t, names[2:4], publisherNames(second.Data))
+	assert.Equal(t, int64(5), second.Meta.Total)
+	assert.NotEmpty(t, second.Meta.PrevCursor)
+
+	last := getPublisherPage(t, query+"&cursor="+second.Meta.NextCursor)
+	assert.Equal(t, names[4:], publisherNames(last.Data))
+	assert.Empty(t, last.Meta.NextCursor)
+
+	// Going back gives the same pages.
+	back := getPublisherPage(t, query+"&cursor="+last.Meta.PrevCursor)
+	assert.Equal(t, names[2:4], publisherNames(back.Data))
+	assert.NotEmpty(t, back.Meta.NextCursor)
+
+	back = getPublisherPage(t, query+"&cursor="+back.Meta.PrevCursor)
+	assert.Equal(t, names[:2], publisherNames(back.Data))
+	assert.Empty(t, back.Meta.PrevCursor)
+	assert.NotEmpty(t, back.Meta.NextCursor)
+}
+
+func TestList_CursorByTime(t *testing.T) {
+	member := registerWithLogin(t)
+	person, _ := personRepo.GetByAccountID(context.Background(), member.ID)
+	history := createHistory(person.ID)
+	url := fmt.Sprintf("%s/%d/borrowings?sort=due_date&l=1", server.RootPerson, person.ID)
+
+	var ids []uint
+	cursor := ""
+	for i := 0; i <= len(history); i++ {
+		w := doTest("GET", url+cursor, nil, member.Token.AccessToken)
+		assert.Equal(t, 200, w.Code)
+
+		var resp dto.SuccessResponse[[]dto.BorrowingResp]
+		_ = json.Unmarshal(w.Body.Bytes(), &resp)
+		for _, item := range resp.Data {
+			ids = append(ids, item.ID)
+		}
+		if resp.Meta.NextCursor == "" {
+			break
+		}
+		cursor = "&cursor=" + resp.Meta.NextCursor
+	}
+
+	assert.Equal(t, []uint{history[0].ID, history[1].ID, history[2].ID}, ids)
+}
+
+func TestList_Filter(t *testing.T) {
+	editor := createAuthor(t)
+	book := createBook(t, dto.BookDTO{Contributors: []dto.BookContributorReq{
+		{AuthorID: editor.ID, Role: "editor"},
+	}})
+
+	w := doTest("GET", server.RootBook+fmt.Sprintf("?author_id=%d", editor.ID), nil, "")
+	assert.Equal(t, 200, w.Code)
+
+	var resp dto.SuccessResponse[[]dto.BookResp]
+	_ = json.Unmarshal(w.Body.Bytes(), &resp)
+	assert.Equal(t, int64(1), resp.Meta.Total)
+	if assert.Len(t, resp.Data, 1) {
+		assert.Equal(t, book.ID, resp.Data[0].ID)
+		assert.Len(t, resp.Data[0].Contributors, 1)
+	}
+}
+
+func TestList_PastTheEnd(t *testing.T) {
+	city, _ := createPublishersIn(t, 2)
+
+	resp := getPublisherPage(t, "city="+city+"&l=2&s=5")
+	assert.Empty(t, resp.Data)
+	assert.Equal(t, int64(2), resp.Meta.Total)
+
+	for _, root := range []string{server.RootPerson, server.RootAuthor, server.RootBook} {
+		w := doTest("GET", root+"?q="+util.RandomStringAlpha(16), nil, "")
+		assert.Equal(t, 200, w.Code, root)
+
+		var resp dto.SuccessResponse[[]any]
+		_ = json.Unmarshal(w.Body.Bytes(), &resp)
+		assert.Empty(t, resp.Data, root)
+		assert.Equal(t, int64(0), resp.Meta.Total, root)
+	}
+}
+
+func TestList_ErrorValidation(t *testing.T) {
+	city, _ := createPublishersIn(t, 3)
+	first := getPublisherPage(t, "city="+city+"&sort=-name&l=1")
+
+	for _, query := range []string{
+		"sort=bogus",
+		"sort=name,-name",
+		"sort=name&cursor=" + first.Meta.NextCursor,
+		"sort=-name&cursor=garbage",
+	} {
+		w := doTest("GET", server.RootPublisher+"?"+query, nil, "")
+		assert.Equal(t, 422, w.Code, query)
+	}
+
+	for _, root := range []string{server.RootPerson, server.RootAuthor, server.RootBook} {
+		w := doTest("GET", root+"?sort=bogus", nil, "")
+		assert.Equal(t, 422, w.Code, root)
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"base-gin/config"
 	"base-gin/domain"
 	"base-gin/domain/dao"
+	"base-gin/domain/dto"
+	"base-gin/migration"
 	"base-gin/repository"
 	"base-gin/rest"
 	"base-gin/server"
@@ -11,6 +13,7 @@ import (
 	"base-gin/storage"
 	"base-gin/util"
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"log"
@@ -21,7 +24,9 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"github.com/joho/godotenv"
+	"github.com/stretchr/testify/assert"
 	"gorm.io/gorm"
 )
 
@@ -65,7 +70,7 @@ func setup() {
 	teardownDB()
 	setupDB()
 
-	repository.SetupRepositories()
+	repository.SetupRepositories(&cfg)
 	accountRepo = repository.GetAccountRepo()
 	personRepo = repository.GetPersonRepo()
 	publisherRepo = repository.GetPublisherRepo()
@@ -79,36 +84,30 @@ func setup() {
 	createDummyProfile(nil)
 
 	service.SetupServices(&cfg)
+	if _, err := service.GetSearchService().Rebuild(context.Background()); err != nil {
+		log.Fatal(fmt.Errorf("Test.Search: %w", err))
+	}
 
-	app = server.Init(&cfg, accountRepo)
+	app = server.Init(&cfg, accountRepo, repository.GetRevokedTokenRepo())
 	rest.SetupRestHandlers(app)
 }
 
 func teardownDB() {
-	_ = db.Migrator().DropTable(
-		&dao.Account{},
-		&dao.Person{},
-		&dao.Publisher{},
-		&dao.Author{},
-		&dao.Book{},
-		&dao.Borrowing{},
-	)
+	if _, err := migration.Reset(db); err != nil {
+		log.Fatal(fmt.Errorf("Test.Migration: %w", err))
+	}
 }
 
 func setupDB() {
-	_ = db.AutoMigrate(
-		&dao.Account{},
-		&dao.Person{},
-		&dao.Publisher{},
-		&dao.Author{},
-		&dao.Book{},
-		&dao.Borrowing{},
-	)
+	if _, err := migration.Up(db); err != nil {
+		log.Fatal(fmt.Errorf("Test.Migration: %w", err))
+	}
 }
 
 func createDummyAccount() *dao.Account {
 	account, _ := dao.NewUser("admin", password, cfg.AuthN.PasswordEncryptionSecret)
-	accountRepo.Create(&account)
+	account.Role = domain.RoleAdmin
+	accountRepo.Create(context.Background(), &account)
 	return &account
 }
 
@@ -125,13 +124,59 @@ func createDummyProfile(account *dao.Account) *dao.Person {
 		person.Account = account
 	}
 
-	personRepo.Create(&person)
+	personRepo.Create(context.Background(), &person)
 
 	return &person
 }
 
+func createDummyBook() *dao.Book {
+	publisher := dao.Publisher{Name: util.RandomStringAlpha(8), City: util.RandomStringAlpha(8)}
+	_ = publisherRepo.Create(context.Background(), &publisher)
+	author := dao.Author{FullName: util.RandomStringAlpha(8), Gender: "f"}
+	_ = authorRepo.Create(context.Background(), &author)
+
+	book := dao.Book{
+		Title:       util.RandomStringAlpha(6),
+		PublisherID: publisher.ID,
+		AuthorID:    author.ID,
+	}
+	_ = bookRepo.Create(context.Background(), &book)
+
+	return &book
+}
+
+// createBook creates a book through the API and returns it. The fields left
+// empty are made up, the book going to a new publisher and author.
+func createBook(t *testing.T, params dto.BookDTO) dto.BookResp {
+	if params.Title == "" {
+		params.Title = util.RandomStringAlpha(6)
+	}
+	if params.Subtitle == nil {
+		params.Subtitle = ptrToString(util.RandomStringAlpha(10))
+	}
+	if params.ISBN == nil {
+		isbn, _ := randomISBN()
+		params.ISBN = &isbn
+	}
+	if params.PublisherID == 0 || (params.AuthorID == 0 && len(params.Contributors) == 0) {
+		book := createDummyBook()
+		if params.PublisherID == 0 {
+			params.PublisherID = book.PublisherID
+		}
+		if params.AuthorID == 0 && len(params.Contributors) == 0 {
+			params.AuthorID = book.AuthorID
+		}
+	}
+	w := doTest("POST", server.RootBook, params, createAuthAccessToken(dummyAdmin.Account.Username))
+	assert.Equal(t, 201, w.Code)
+
+	code, resp := getBookByISBN(*params.ISBN)
+	assert.Equal(t, 200, code)
+	return resp
+}
+
 func createAuthAccessToken(username string) string {
-	token, err := util.CreateAuthAccessToken(cfg, username)
+	token, err := util.CreateAuthAccessToken(cfg, username, uuid.NewString(), "")
 	if err != nil {
 		log.Fatal(fmt.Errorf("main_test.createAuthAccessToken %w", err))
 	}
@@ -0,0 +1,205 @@
+package integration_test
+
+import (
+	"base-gin/domain"
+	"base-gin/domain/dao"
+	"base-gin/domain/dto"
+	"base-gin/notification"
+	"base-gin/repository"
+	"base-gin/server"
+	"base-gin/service"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeSender records the messages sent through it, or fails them all.
+type fakeSender struct {
+	channel  domain.TypeChannel
+	fail     bool
+	messages []notification.Message
+}
+
+func (s *fakeSender) Channel() domain.TypeChannel {
+	return s.channel
+}
+
+func (s *fakeSender) Send(_ context.Context, msg notification.Message) error {
+	if s.fail {
+		return errors.New("connection refused")
+	}
+	s.messages = append(s.messages, msg)
+	return nil
+}
+
+func (s *fakeSender) sentTo(personID uint) []notification.Message {
+	var result []notification.Message
+	for _, msg := range s.messages {
+		if msg.PersonID == personID {
+			result = append(result, msg)
+		}
+	}
+	return result
+}
+
+func newNotificationService(senders ...notification.Sender) *service.NotificationService {
+	return service.NewNotificationService(
+		&cfg,
+		repository.GetNotificationRepo(),
+		borrowingRepo,
+		personRepo,
+		senders...,
+	)
+}
+
+func setPreference(t *testing.T, personID uint, params dto.NotificationPreferenceReq, token string) {
+	w := doTest("PUT", fmt.Sprintf("%s/persons/%d/preference", server.RootNotification, personID), params, token)
+	assert.Equal(t, 200, w.Code)
+}
+
+// createReminderLoans lends the person a book due tomorrow and one overdue
+// for longer than the long-overdue days.
+func createReminderLoans(personID uint) []dao.Borrowing {
+	now := time.Now()
+	items := []dao.Borrowing{
+		{BorrowDate: now.AddDate(0, 0, -13), DueDate: now.AddDate(0, 0, 1)},
+		{
+			BorrowDate: now.AddDate(0, 0, -40),
+			DueDate:    now.AddDate(0, 0, -cfg.Notification.LongOverdueDays-1),
+		},
+	}
+	for i := range items {
+		items[i].BookID = createDummyBook().ID
+		items[i].PersonID = personID
+		_ = borrowingRepo.Create(context.Background(), &items[i])
+	}
+
+	return items
+}
+
+func TestNotification_SendReminders_Success(t *testing.T) {
+	member := registerWithLogin(t)
+	person, _ := personRepo.GetByAccountID(context.Background(), member.ID)
+	email := "member@example.com"
+	setPreference(t, person.ID, dto.NotificationPreferenceReq{Email: &email, Locale: "en"}, member.Token.AccessToken)
+	createReminderLoans(person.ID)
+
+	sender := &fakeSender{channel: domain.ChannelEmail}
+	svc := newNotificationService(sender)
+
+	_, err := svc.SendReminders(context.Background(), time.Now())
+	assert.Nil(t, err)
+
+	messages := sender.sentTo(person.ID)
+	if assert.Len(t, messages, 2) {
+		assert.Equal(t, domain.NotifyDueSoon, messages[0].Kind)
+		assert.Equal(t, email, messages[0].To)
+		assert.Contains(t, messages[0].Subject, "Reminder")
+		assert.Equal(t, domain.NotifyLongOverdue, messages[1].Kind)
+		assert.Contains(t, messages[1].Subject, "Overdue")
+	}
+
+	// nothing goes out twice
+	_, err = svc.SendReminders(context.Background(), time.Now())
+	assert.Nil(t, err)
+	assert.Len(t, sender.sentTo(person.ID), 2)
+
+	w := doTest("GET", fmt.Sprintf("%s/persons/%d/deliveries", server.RootNotification, person.ID), nil, member.Token.AccessToken)
+	assert.Equal(t, 200, w.Code)
+	var resp dto.SuccessResponse[[]dto.NotificationDeliveryResp]
+	_ = json.Unmarshal(w.Body.Bytes(), &resp)
+	if assert.Len(t, resp.Data, 2) {
+		assert.Equal(t, "sent", resp.Data[0].Status)
+		assert.Equal(t, email, resp.Data[0].Recipient)
+	}
+}
+
+func TestNotification_SendReminders_OptOut(t *testing.T) {
+	member := registerWithLogin(t)
+	person, _ := personRepo.GetByAccountID(context.Background(), member.ID)
+	email := "member@example.com"
+	setPreference(t, person.ID, dto.NotificationPreferenceReq{
+		Email:       &email,
+		Locale:      "id",
+		EmailOptOut: true,
+	}, member.Token.AccessToken)
+	createReminderLoans(person.ID)
+
+	sender := &fakeSender{channel: domain.ChannelEmail}
+	webhook := &fakeSender{channel: domain.ChannelWebhook}
+	_, err := newNotificationService(sender, webhook).SendReminders(context.Background(), time.Now())
+	assert.Nil(t, err)
+
+	assert.Empty(t, sender.sentTo(person.ID))
+	if assert.Len(t, webhook.sentTo(person.ID), 2) {
+		assert.Contains(t, webhook.sentTo(person.ID)[0].Subject, "Pengingat")
+	}
+}
+
+func TestNotification_SendReminders_RetryFailed(t *testing.T) {
+	member := registerWithLogin(t)
+	person, _ := personRepo.GetByAccountID(context.Background(), member.ID)
+	phone := "+6281234567890"
+	setPreference(t, person.ID, dto.NotificationPreferenceReq{Phone: &phone, Locale: "id"}, member.Token.AccessToken)
+	createReminderLoans(person.ID)
+
+	sender := &fakeSender{channel: domain.ChannelSMS, fail: true}
+	svc := newNotificationService(sender)
+	resp, err := svc.SendReminders(context.Background(), time.Now())
+	assert.Nil(t, err)
+	assert.GreaterOrEqual(t, resp.Failed, 2)
+
+	sender.fail = false
+	_, err = svc.SendReminders(context.Background(), time.Now())
+	assert.Nil(t, err)
+	assert.Len(t, sender.sentTo(person.ID), 2)
+
+	w := doTest("GET", fmt.Sprintf("%s/persons/%d/deliveries", server.RootNotification, person.ID), nil, member.Token.AccessToken)
+	var deliveries dto.SuccessResponse[[]dto.NotificationDeliveryResp]
+	_ = json.Unmarshal(w.Body.Bytes(), &deliveries)
+	assert.Len(t, deliveries.Data, 4)
+}
+
+func TestNotification_Preference(t *testing.T) {
+	member := registerWithLogin(t)
+	person, _ := personRepo.GetByAccountID(context.Background(), member.ID)
+	url := fmt.Sprintf("%s/persons/%d/preference", server.RootNotification, person.ID)
+
+	w := doTest("GET", url, nil, member.Token.AccessToken)
+	assert.Equal(t, 200, w.Code)
+	var resp dto.SuccessResponse[dto.NotificationPreferenceResp]
+	_ = json.Unmarshal(w.Body.Bytes(), &resp)
+	assert.Equal(t, cfg.Notification.DefaultLocale, resp.Data.Locale)
+	assert.Nil(t, resp.Data.Email)
+
+	w = doTest("PUT", url, dto.NotificationPreferenceReq{Locale: "fr"}, member.Token.AccessToken)
+	assert.Equal(t, 422, w.Code)
+
+	email := "not-an-email"
+	w = doTest("PUT", url, dto.NotificationPreferenceReq{Email: &email, Locale: "en"}, member.Token.AccessToken)
+	assert.Equal(t, 422, w.Code)
+
+	setPreference(t, person.ID, dto.NotificationPreferenceReq{Locale: "en", SMSOptOut: true}, member.Token.AccessToken)
+	setPreference(t, person.ID, dto.NotificationPreferenceReq{Locale: "en", WebhookOptOut: true}, createAuthAccessToken(dummyAdmin.Account.Username))
+
+	w = doTest("GET", url, nil, member.Token.AccessToken)
+	_ = json.Unmarshal(w.Body.Bytes(), &resp)
+	assert.Equal(t, "en", resp.Data.Locale)
+	assert.False(t, resp.Data.SMSOptOut)
+	assert.True(t, resp.Data.WebhookOptOut)
+
+	other := registerWithLogin(t)
+	w = doTest("GET", url, nil, other.Token.AccessToken)
+	assert.Equal(t, 403, w.Code)
+
+	w = doTest("POST", server.RootNotification+server.PathNotifyReminders, nil, member.Token.AccessToken)
+	assert.Equal(t, 403, w.Code)
+
+	w = doTest("POST", server.RootNotification+server.PathNotifyReminders, nil, createAuthAccessToken(dummyAdmin.Account.Username))
+	assert.Equal(t, 200, w.Code)
+}
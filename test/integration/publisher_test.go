@@ -5,6 +5,7 @@ import (
 	"base-gin/domain/dto"
 	"base-gin/server"
 	"base-gin/util"
+	"context"
 	"fmt"
 	"testing"
 
@@ -26,12 +27,23 @@ func TestPublisher_Create_Success(t *testing.T) {
 	assert.Equal(t, 201, w.Code)
 }
 
+func TestPublisher_Create_ErrorForbidden(t *testing.T) {
+	member := registerWithLogin(t)
+	params := dto.PublisherCreateReq{
+		Name: util.RandomStringAlpha(6),
+		City: util.RandomStringAlpha(8),
+	}
+
+	w := doTest("POST", server.RootPublisher, params, member.Token.AccessToken)
+	assert.Equal(t, 403, w.Code)
+}
+
 func TestPublisher_Update_Success(t *testing.T) {
 	o := dao.Publisher{
 		Name: util.RandomStringAlpha(6),
 		City: util.RandomStringAlpha(8),
 	}
-	_ = publisherRepo.Create(&o)
+	_ = publisherRepo.Create(context.Background(), &o)
 
 	params := dto.PublisherUpdateReq{
 		Name: util.RandomStringAlpha(7),
@@ -46,7 +58,7 @@ func TestPublisher_Update_Success(t *testing.T) {
 	)
 	assert.Equal(t, 200, w.Code)
 
-	item, _ := publisherRepo.GetByID(o.ID)
+	item, _ := publisherRepo.GetByID(context.Background(), o.ID)
 	assert.Equal(t, params.Name, item.Name)
 	assert.Equal(t, params.City, item.City)
 	assert.Equal(t, false, item.DeletedAt.Valid)
@@ -57,7 +69,7 @@ func TestPublisher_Delete_Success(t *testing.T) {
 		Name: util.RandomStringAlpha(6),
 		City: util.RandomStringAlpha(8),
 	}
-	_ = publisherRepo.Create(&o)
+	_ = publisherRepo.Create(context.Background(), &o)
 
 	w := doTest(
 		"DELETE",
@@ -67,7 +79,7 @@ func TestPublisher_Delete_Success(t *testing.T) {
 	)
 	assert.Equal(t, 200, w.Code)
 
-	item, _ := publisherRepo.GetByID(o.ID)
+	item, _ := publisherRepo.GetByID(context.Background(), o.ID)
 	assert.Nil(t, item)
 }
 
@@ -76,13 +88,13 @@ func TestPublisher_GetList_Success(t *testing.T) {
 		Name: util.RandomStringAlpha(6),
 		City: util.RandomStringAlpha(8),
 	}
-	_ = publisherRepo.Create(&o1)
+	_ = publisherRepo.Create(context.Background(), &o1)
 
 	o2 := dao.Publisher{
 		Name: util.RandomStringAlpha(6),
 		City: util.RandomStringAlpha(8),
 	}
-	_ = publisherRepo.Create(&o2)
+	_ = publisherRepo.Create(context.Background(), &o2)
 
 	w := doTest(
 		"GET",
@@ -114,7 +126,7 @@ func TestPublisher_GetDetail_Success(t *testing.T) {
 		Name: util.RandomStringAlpha(6),
 		City: util.RandomStringAlpha(8),
 	}
-	_ = publisherRepo.Create(&o)
+	_ = publisherRepo.Create(context.Background(), &o)
 
 	w := doTest(
 		"GET",
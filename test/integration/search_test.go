@@ -0,0 +1,127 @@
+package integration_test
+
+import (
+	"base-gin/domain/dto"
+	"base-gin/search"
+	"base-gin/server"
+	"base-gin/util"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func searchBooks(t *testing.T, query string) dto.SearchResp {
+	w := doTest("GET", server.RootSearch+"?"+query, nil, "")
+	assert.Equal(t, 200, w.Code)
+
+	var resp dto.SuccessResponse[dto.SearchResp]
+	_ = json.Unmarshal(w.Body.Bytes(), &resp)
+	return resp.Data
+}
+
+func searchHitIDs(resp dto.SearchResp) []uint {
+	ids := make([]uint, len(resp.Hits))
+	for i, hit := range resp.Hits {
+		ids[i] = hit.Book.ID
+	}
+	return ids
+}
+
+func TestSearch_Success(t *testing.T) {
+	word := util.RandomStringAlpha(10)
+	author := createAuthor(t)
+	publisher := createPublisher(t, util.RandomStringAlpha(8))
+	book := createBook(t, dto.BookDTO{Title: "Kisah " + word, AuthorID: author.ID, PublisherID: publisher.ID})
+
+	resp := searchBooks(t, "q="+url.QueryEscape(word+" "+author.FullName))
+	assert.Equal(t, 1, resp.Total)
+	assert.Equal(t, []uint{book.ID}, searchHitIDs(resp))
+	assert.Equal(t, book.Title, resp.Hits[0].Book.Title)
+	assert.Equal(t, "Kisah "+search.HighlightPre+word+search.HighlightPost,
+		resp.Hits[0].Highlights["title"])
+	assert.Contains(t, resp.Hits[0].Highlights["authors"], search.HighlightPre)
+	assert.Equal(t, []dto.FacetResp{
+		{ID: publisher.ID, Name: publisher.Name, Count: 1},
+	}, resp.Facets.Publishers)
+	assert.Equal(t, []dto.FacetResp{
+		{ID: author.ID, Name: author.FullName, Count: 1},
+	}, resp.Facets.Authors)
+
+	// A typo and an incomplete last word still match.
+	typo := word[:3] + word[4:]
+	resp = searchBooks(t, "q="+url.QueryEscape(typo))
+	assert.Equal(t, []uint{book.ID}, searchHitIDs(resp))
+	resp = searchBooks(t, "q="+url.QueryEscape(publisher.Name[:6]))
+	assert.Equal(t, []uint{book.ID}, searchHitIDs(resp))
+}
+
+func TestSearch_Filter(t *testing.T) {
+	word := util.RandomStringAlpha(10)
+	author := createAuthor(t)
+	first := createPublisher(t, util.RandomStringAlpha(8))
+	second := createPublisher(t, util.RandomStringAlpha(8))
+	book := createBook(t, dto.BookDTO{Title: word, AuthorID: author.ID, PublisherID: first.ID})
+	other := createBook(t, dto.BookDTO{Title: word + " " + word, AuthorID: author.ID, PublisherID: second.ID})
+
+	resp := searchBooks(t, "q="+word)
+	assert.Equal(t, 2, resp.Total)
+	// The title repeating the word ranks first.
+	assert.Equal(t, []uint{other.ID, book.ID}, searchHitIDs(resp))
+	assert.Len(t, resp.Facets.Publishers, 2)
+	assert.Equal(t, []dto.FacetResp{
+		{ID: author.ID, Name: author.FullName, Count: 2},
+	}, resp.Facets.Authors)
+
+	resp = searchBooks(t, fmt.Sprintf("q=%s&publisher_id=%d", word, first.ID))
+	assert.Equal(t, []uint{book.ID}, searchHitIDs(resp))
+
+	resp = searchBooks(t, fmt.Sprintf("q=%s&l=1&s=1", word))
+	assert.Equal(t, 2, resp.Total)
+	assert.Equal(t, []uint{book.ID}, searchHitIDs(resp))
+}
+
+func TestSearch_Sync(t *testing.T) {
+	token := createAuthAccessToken(dummyAdmin.Account.Username)
+	word := util.RandomStringAlpha(10)
+	author := createAuthor(t)
+	publisher := createPublisher(t, util.RandomStringAlpha(8))
+	book := createBook(t, dto.BookDTO{Title: word, AuthorID: author.ID, PublisherID: publisher.ID})
+
+	renamed := util.RandomStringAlpha(10)
+	params := dto.BookUpdate{
+		Title:       renamed,
+		Subtitle:    book.Subtitle,
+		PublisherID: publisher.ID,
+		AuthorID:    author.ID,
+	}
+	w := doTest("PUT", fmt.Sprintf("%s/%d", server.RootBook, book.ID), params, token)
+	assert.Equal(t, 200, w.Code)
+	assert.Empty(t, searchBooks(t, "q="+word).Hits)
+	assert.Equal(t, []uint{book.ID}, searchHitIDs(searchBooks(t, "q="+renamed)))
+
+	// Renaming the author reindexes the book.
+	name := util.RandomStringAlpha(10)
+	gender := author.Gender
+	birthDate := time.Date(1970, 1, 1, 0, 0, 0, 0, time.UTC)
+	w = doTest("PUT", fmt.Sprintf("%s/%d", server.RootAuthor, author.ID),
+		dto.AuthorUpdate{FullName: name, Gender: &gender, BirthDate: &birthDate}, token)
+	assert.Equal(t, 200, w.Code)
+	assert.Equal(t, []uint{book.ID}, searchHitIDs(searchBooks(t, "q="+name)))
+
+	w = doTest("DELETE", fmt.Sprintf("%s/%d", server.RootBook, book.ID), nil, token)
+	assert.Equal(t, 200, w.Code)
+	assert.Empty(t, searchBooks(t, "q="+renamed).Hits)
+}
+
+func TestSearch_Invalid(t *testing.T) {
+	w := doTest("GET", server.RootSearch, nil, "")
+	assert.Equal(t, 422, w.Code)
+
+	resp := searchBooks(t, "q=--")
+	assert.Equal(t, 0, resp.Total)
+	assert.Empty(t, resp.Hits)
+}
@@ -0,0 +1,21 @@
+package unit_test
+
+import (
+	"base-gin/domain/dao"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccount_IsTokenRevoked(t *testing.T) {
+	revokedAt := time.Date(2024, 5, 1, 10, 0, 0, 300_000_000, time.UTC)
+	account := dao.Account{TokensRevokedAt: &revokedAt}
+
+	assert.True(t, account.IsTokenRevoked(revokedAt.Add(-time.Second).Truncate(time.Second)))
+	// A token issued right after the revocation has the same whole second.
+	assert.False(t, account.IsTokenRevoked(revokedAt.Add(100*time.Millisecond).Truncate(time.Second)))
+	assert.False(t, account.IsTokenRevoked(revokedAt.Add(time.Second)))
+
+	assert.False(t, (&dao.Account{}).IsTokenRevoked(revokedAt))
+}
@@ -0,0 +1,21 @@
+package unit_test
+
+import (
+	"base-gin/service"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFinePolicy_Calculate(t *testing.T) {
+	policy := service.FinePolicy{PerDay: 1000, MaxAmount: 5000, GraceDays: 2}
+
+	assert.Equal(t, int64(0), policy.Calculate(0))
+	assert.Equal(t, int64(0), policy.Calculate(2))
+	assert.Equal(t, int64(1000), policy.Calculate(3))
+	assert.Equal(t, int64(5000), policy.Calculate(7))
+	assert.Equal(t, int64(5000), policy.Calculate(30))
+
+	policy.MaxAmount = 0
+	assert.Equal(t, int64(28000), policy.Calculate(30))
+}
@@ -0,0 +1,65 @@
+package unit_test
+
+import (
+	"base-gin/util"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestISBN_Valid(t *testing.T) {
+	for _, isbn := range []string{
+		"0306406152",
+		"0-306-40615-2",
+		"080442957x",
+		"978-0-306-40615-7",
+		"979 10 90636 07 1",
+	} {
+		assert.True(t, util.IsISBN(isbn), isbn)
+	}
+}
+
+func TestISBN_Invalid(t *testing.T) {
+	for _, isbn := range []string{
+		"",
+		"0306406153",
+		"03064061X2",
+		"978-0-306-40615-8",
+		"9770306406157",
+		"97803064061",
+		"abcdefghij",
+	} {
+		assert.False(t, util.IsISBN(isbn), isbn)
+	}
+}
+
+func TestISBN_Convert(t *testing.T) {
+	isbn13, err := util.ISBN10To13("0-8044-2957-X")
+	assert.Nil(t, err)
+	assert.Equal(t, "9780804429573", isbn13)
+
+	isbn10, err := util.ISBN13To10(isbn13)
+	assert.Nil(t, err)
+	assert.Equal(t, "080442957X", isbn10)
+
+	_, err = util.ISBN13To10("9791090636071")
+	assert.ErrorIs(t, err, util.ErrISBNInvalid)
+
+	_, err = util.ISBN10To13("0306406153")
+	assert.ErrorIs(t, err, util.ErrISBNInvalid)
+}
+
+func TestISBN_Parse(t *testing.T) {
+	isbn10, isbn13, err := util.ParseISBN("978-0-306-40615-7")
+	assert.Nil(t, err)
+	assert.Equal(t, "0306406152", isbn10)
+	assert.Equal(t, "9780306406157", isbn13)
+
+	isbn10, isbn13, err = util.ParseISBN("979-10-90636-07-1")
+	assert.Nil(t, err)
+	assert.Empty(t, isbn10)
+	assert.Equal(t, "9791090636071", isbn13)
+
+	_, _, err = util.ParseISBN("12345")
+	assert.ErrorIs(t, err, util.ErrISBNInvalid)
+}
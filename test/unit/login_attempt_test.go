@@ -0,0 +1,57 @@
+package unit_test
+
+import (
+	"base-gin/repository"
+	"base-gin/util"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testLoginAttemptStore(t *testing.T, store repository.LoginAttemptStore) {
+	ctx := context.Background()
+	key := "login:user:" + util.RandomStringAlpha(8)
+
+	count, _, err := store.Get(ctx, key)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, count)
+
+	count, expiresAt, err := store.Hit(ctx, key, time.Minute)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, count)
+	assert.True(t, expiresAt.After(time.Now()))
+
+	count, _, err = store.Hit(ctx, key, time.Minute)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, count)
+
+	count, _, err = store.Get(ctx, key)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, count)
+
+	assert.Nil(t, store.Reset(ctx, key))
+	count, _, err = store.Get(ctx, key)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, count)
+
+	// an ended window is not counted and the next hit starts a new one
+	_, _, err = store.Hit(ctx, key, -time.Second)
+	assert.Nil(t, err)
+	count, _, err = store.Get(ctx, key)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, count)
+
+	count, _, err = store.Hit(ctx, key, time.Minute)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, count)
+}
+
+func TestLoginAttempt_MemoryStore(t *testing.T) {
+	testLoginAttemptStore(t, repository.NewMemoryLoginAttemptStore())
+}
+
+func TestLoginAttempt_DBStore(t *testing.T) {
+	testLoginAttemptStore(t, repository.NewDBLoginAttemptStore(db))
+}
@@ -4,9 +4,11 @@ import (
 	"base-gin/config"
 	"base-gin/domain"
 	"base-gin/domain/dao"
+	"base-gin/migration"
 	"base-gin/repository"
 	"base-gin/storage"
 	"base-gin/util"
+	"context"
 	"fmt"
 	"log"
 	"os"
@@ -53,7 +55,7 @@ func setup() {
 	teardownDB()
 	setupDB()
 
-	repository.SetupRepositories()
+	repository.SetupRepositories(&cfg)
 	accountRepo = repository.GetAccountRepo()
 	personRepo = repository.GetPersonRepo()
 
@@ -64,22 +66,21 @@ func setup() {
 }
 
 func teardownDB() {
-	_ = db.Migrator().DropTable(
-		&dao.Account{},
-		&dao.Person{},
-	)
+	if _, err := migration.Reset(db); err != nil {
+		log.Fatal(fmt.Errorf("Test.Migration: %w", err))
+	}
 }
 
 func setupDB() {
-	_ = db.AutoMigrate(
-		&dao.Account{},
-		&dao.Person{},
-	)
+	if _, err := migration.Up(db); err != nil {
+		log.Fatal(fmt.Errorf("Test.Migration: %w", err))
+	}
 }
 
 func createDummyAccount() *dao.Account {
 	account, _ := dao.NewUser("admin", password, cfg.AuthN.PasswordEncryptionSecret)
-	accountRepo.Create(&account)
+	account.Role = domain.RoleAdmin
+	accountRepo.Create(context.Background(), &account)
 	return &account
 }
 
@@ -96,7 +97,7 @@ func createDummyProfile(account *dao.Account) *dao.Person {
 		person.Account = account
 	}
 
-	personRepo.Create(&person)
+	personRepo.Create(context.Background(), &person)
 
 	return &person
 }
@@ -0,0 +1,56 @@
+package unit_test
+
+import (
+	"base-gin/migration"
+	"base-gin/storage"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestMigration_Status_AllApplied(t *testing.T) {
+	items, err := migration.GetStatus(db)
+	assert.Nil(t, err)
+	assert.Len(t, items, len(migration.All()))
+
+	for i, item := range items {
+		assert.True(t, item.Applied(), item.Name)
+		if i > 0 {
+			assert.Greater(t, item.Version, items[i-1].Version)
+		}
+	}
+}
+
+func TestMigration_Up_Idempotent(t *testing.T) {
+	done, err := migration.Up(db)
+	assert.Nil(t, err)
+	assert.Empty(t, done)
+}
+
+func TestMigration_Down_InvalidStep(t *testing.T) {
+	_, err := migration.Down(db, 0)
+	assert.ErrorIs(t, err, migration.ErrInvalidStep)
+}
+
+func TestMigration_DownAll_UpAgain(t *testing.T) {
+	// A database of its own, the shared one being in use by the other tests.
+	dialector, err := storage.NewDialector("sqlite://file:migration_round_trip?mode=memory&cache=shared")
+	assert.Nil(t, err)
+	roundTripDB, err := gorm.Open(dialector, &gorm.Config{})
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	done, err := migration.Up(roundTripDB)
+	assert.Nil(t, err)
+	assert.Len(t, done, len(migration.All()))
+
+	done, err = migration.Reset(roundTripDB)
+	assert.Nil(t, err)
+	assert.Len(t, done, len(migration.All()))
+
+	done, err = migration.Up(roundTripDB)
+	assert.Nil(t, err)
+	assert.Len(t, done, len(migration.All()))
+}
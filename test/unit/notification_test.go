@@ -0,0 +1,183 @@
+package unit_test
+
+import (
+	"base-gin/domain"
+	"base-gin/notification"
+	"bufio"
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type smtpMail struct {
+	from string
+	to   []string
+	data string
+}
+
+// startFakeSMTP serves a single SMTP session on a local port, without TLS or
+// auth, and hands over the mail it received.
+func startFakeSMTP(t *testing.T) (int, <-chan smtpMail) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	mails := make(chan smtpMail, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		var mail smtpMail
+		r := bufio.NewReader(conn)
+		reply := func(line string) { _, _ = conn.Write([]byte(line + "\r\n")) }
+
+		reply("220 localhost ESMTP")
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			cmd := strings.TrimSpace(line)
+
+			switch {
+			case strings.HasPrefix(cmd, "EHLO"), strings.HasPrefix(cmd, "HELO"):
+				reply("250 localhost")
+			case strings.HasPrefix(cmd, "MAIL FROM:"):
+				mail.from = strings.Trim(strings.TrimPrefix(cmd, "MAIL FROM:"), "<>")
+				reply("250 OK")
+			case strings.HasPrefix(cmd, "RCPT TO:"):
+				mail.to = append(mail.to, strings.Trim(strings.TrimPrefix(cmd, "RCPT TO:"), "<>"))
+				reply("250 OK")
+			case cmd == "DATA":
+				reply("354 End data with <CR><LF>.<CR><LF>")
+				var data strings.Builder
+				for {
+					l, err := r.ReadString('\n')
+					if err != nil {
+						return
+					}
+					if l == ".\r\n" {
+						break
+					}
+					data.WriteString(l)
+				}
+				mail.data = data.String()
+				reply("250 OK")
+			case cmd == "QUIT":
+				reply("221 Bye")
+				mails <- mail
+				return
+			default:
+				reply("502 Command not implemented")
+			}
+		}
+	}()
+
+	return ln.Addr().(*net.TCPAddr).Port, mails
+}
+
+func TestSMTPSender_Send(t *testing.T) {
+	port, mails := startFakeSMTP(t)
+	sender := notification.NewSMTPSender("127.0.0.1", port, "", "", "library@example.com")
+
+	ctx, cancelFunc := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancelFunc()
+
+	err := sender.Send(ctx, notification.Message{
+		To:      "member@example.com",
+		Subject: "Pengingat jatuh tempo",
+		Body:    "Halo, bukunya jatuh tempo besok.",
+	})
+	assert.Nil(t, err)
+
+	select {
+	case mail := <-mails:
+		assert.Equal(t, "library@example.com", mail.from)
+		assert.Equal(t, []string{"member@example.com"}, mail.to)
+		assert.Contains(t, mail.data, "Subject: Pengingat jatuh tempo")
+		assert.Contains(t, mail.data, "Halo, bukunya jatuh tempo besok.")
+	case <-time.After(5 * time.Second):
+		t.Fatal("no mail received")
+	}
+}
+
+func TestSMTPSender_Send_ErrorUnreachable(t *testing.T) {
+	ln, _ := net.Listen("tcp", "127.0.0.1:0")
+	port := ln.Addr().(*net.TCPAddr).Port
+	ln.Close()
+
+	sender := notification.NewSMTPSender("127.0.0.1", port, "", "", "library@example.com")
+
+	err := sender.Send(context.Background(), notification.Message{To: "member@example.com"})
+	assert.NotNil(t, err)
+}
+
+func TestWebhookSender_Send(t *testing.T) {
+	var received map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&received)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	sender := notification.NewWebhookSender(srv.URL)
+	err := sender.Send(context.Background(), notification.Message{
+		Kind:        domain.NotifyLongOverdue,
+		PersonID:    7,
+		BorrowingID: 9,
+		Subject:     "Overdue",
+		Body:        "Please return it.",
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, "long_overdue", received["kind"])
+	assert.Equal(t, float64(7), received["person_id"])
+}
+
+func TestSMSSender_Send_ErrorStatus(t *testing.T) {
+	var auth string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer srv.Close()
+
+	sender := notification.NewSMSSender(srv.URL, "secret")
+	err := sender.Send(context.Background(), notification.Message{To: "+6281234567890", Body: "Halo"})
+	assert.NotNil(t, err)
+	assert.Equal(t, "Bearer secret", auth)
+}
+
+func TestNotification_Render(t *testing.T) {
+	data := notification.ReminderData{
+		Fullname: "Budi",
+		Title:    "Laskar Pelangi",
+		DueDate:  time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC),
+		DaysLeft: 2,
+	}
+
+	msg, err := notification.Render(domain.NotifyDueSoon, domain.LocaleID, data)
+	assert.Nil(t, err)
+	assert.Contains(t, msg.Subject, "05-03-2024")
+	assert.Contains(t, msg.Body, "Halo Budi")
+	assert.Contains(t, msg.Body, "2 hari lagi")
+
+	msg, err = notification.Render(domain.NotifyDueSoon, domain.LocaleEN, data)
+	assert.Nil(t, err)
+	assert.Contains(t, msg.Subject, "Mar 5, 2024")
+	assert.Contains(t, msg.Body, "Hello Budi")
+
+	_, err = notification.Render(domain.NotifyDueSoon, "fr", data)
+	assert.ErrorIs(t, err, notification.ErrNoTemplate)
+}
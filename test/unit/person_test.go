@@ -4,6 +4,7 @@ import (
 	"base-gin/domain"
 	"base-gin/domain/dto"
 	"base-gin/util"
+	"context"
 	"testing"
 	"time"
 
@@ -21,11 +22,20 @@ func TestPerson_Update_Success(t *testing.T) {
 		BirthDate:    birthDate,
 	}
 
-	err := personRepo.Update(&params)
+	err := personRepo.Update(context.Background(), &params)
 	assert.Nil(t, err)
 
-	item, _ := personRepo.GetByID(dummyMember.ID)
+	item, _ := personRepo.GetByID(context.Background(), dummyMember.ID)
 	assert.Equal(t, params.Fullname, item.Fullname)
 	assert.EqualValues(t, params.Gender, string(*item.Gender))
 	assert.EqualValues(t, params.BirthDateStr, item.BirthDate.Format("2006-01-02"))
 }
+
+func TestPerson_GetByID_ContextCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	item, err := personRepo.GetByID(ctx, dummyMember.ID)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Nil(t, item)
+}
@@ -0,0 +1,174 @@
+package unit_test
+
+import (
+	"base-gin/domain"
+	"base-gin/domain/dao"
+	"base-gin/domain/dto"
+	"base-gin/exception"
+	"base-gin/repository"
+	"base-gin/scheduler"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func getJobRun(t *testing.T, repo *repository.JobRunRepository, name string) dto.JobRunResp {
+	run, err := repo.GetLatestByJobName(context.Background(), name)
+	assert.Nil(t, err)
+
+	var resp dto.JobRunResp
+	resp.FromEntity(&run)
+	return resp
+}
+
+func TestScheduler_Trigger_Success(t *testing.T) {
+	repo := repository.GetJobRunRepo()
+	s := scheduler.New(repo, time.Minute, "unit")
+	assert.Nil(t, s.Register(scheduler.Job{
+		Name: "unit_success",
+		Run: func(ctx context.Context) (string, error) {
+			return "done", nil
+		},
+	}))
+
+	run, err := s.Trigger(context.Background(), "unit_success")
+	assert.Nil(t, err)
+	assert.Equal(t, domain.JobRunning, run.Status)
+	assert.Equal(t, domain.JobTriggerManual, run.Trigger)
+	assert.Nil(t, s.Stop(context.Background()))
+
+	resp := getJobRun(t, repo, "unit_success")
+	assert.Equal(t, run.ID, resp.ID)
+	assert.Equal(t, string(domain.JobSucceeded), resp.Status)
+	assert.Equal(t, "done", *resp.Message)
+	assert.NotNil(t, resp.FinishedAt)
+}
+
+func TestScheduler_Trigger_Failure(t *testing.T) {
+	repo := repository.GetJobRunRepo()
+	jobs := []scheduler.Job{
+		{
+			Name: "unit_failure",
+			Run: func(ctx context.Context) (string, error) {
+				return "", errors.New("boom")
+			},
+		},
+		{
+			Name: "unit_panic",
+			Run: func(ctx context.Context) (string, error) {
+				panic("oops")
+			},
+		},
+	}
+
+	// One at a time, as the in-memory test database locks on concurrent writes.
+	for _, job := range jobs {
+		s := scheduler.New(repo, time.Minute, "unit")
+		assert.Nil(t, s.Register(job))
+
+		_, err := s.Trigger(context.Background(), job.Name)
+		assert.Nil(t, err)
+		assert.Nil(t, s.Stop(context.Background()))
+	}
+
+	resp := getJobRun(t, repo, "unit_failure")
+	assert.Equal(t, string(domain.JobFailed), resp.Status)
+	assert.Equal(t, "boom", *resp.Error)
+
+	resp = getJobRun(t, repo, "unit_panic")
+	assert.Equal(t, string(domain.JobFailed), resp.Status)
+	assert.Contains(t, *resp.Error, "oops")
+}
+
+func TestScheduler_Trigger_Running(t *testing.T) {
+	repo := repository.GetJobRunRepo()
+	s := scheduler.New(repo, time.Minute, "unit")
+	release := make(chan struct{})
+	assert.Nil(t, s.Register(scheduler.Job{
+		Name: "unit_slow",
+		Run: func(ctx context.Context) (string, error) {
+			<-release
+			return "", nil
+		},
+	}))
+
+	_, err := s.Trigger(context.Background(), "unit_slow")
+	assert.Nil(t, err)
+	_, err = s.Trigger(context.Background(), "unit_slow")
+	assert.ErrorIs(t, err, exception.ErrJobRunning)
+	assert.True(t, s.Jobs()[0].Running)
+
+	close(release)
+	assert.Nil(t, s.Stop(context.Background()))
+
+	_, err = s.Trigger(context.Background(), "unit_slow")
+	assert.ErrorIs(t, err, exception.ErrSchedulerStopped)
+}
+
+func TestScheduler_Stop_CancelsRunning(t *testing.T) {
+	repo := repository.GetJobRunRepo()
+	s := scheduler.New(repo, time.Minute, "unit")
+	assert.Nil(t, s.Register(scheduler.Job{
+		Name: "unit_blocked",
+		Run: func(ctx context.Context) (string, error) {
+			<-ctx.Done()
+			return "", ctx.Err()
+		},
+	}))
+
+	_, err := s.Trigger(context.Background(), "unit_blocked")
+	assert.Nil(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, s.Stop(ctx), context.DeadlineExceeded)
+
+	// The cancelled run is still recorded.
+	assert.Nil(t, s.Stop(context.Background()))
+	resp := getJobRun(t, repo, "unit_blocked")
+	assert.Equal(t, string(domain.JobFailed), resp.Status)
+	assert.Equal(t, context.Canceled.Error(), *resp.Error)
+}
+
+func TestScheduler_Register_Invalid(t *testing.T) {
+	s := scheduler.New(repository.GetJobRunRepo(), time.Minute, "unit")
+	run := func(ctx context.Context) (string, error) { return "", nil }
+
+	assert.NotNil(t, s.Register(scheduler.Job{Name: "unit_bad_spec", Spec: "every day", Run: run}))
+	assert.Nil(t, s.Register(scheduler.Job{Name: "unit_twice", Spec: "0 8 * * *", Run: run}))
+	assert.NotNil(t, s.Register(scheduler.Job{Name: "unit_twice", Run: run}))
+
+	_, err := s.Trigger(context.Background(), "unit_missing")
+	assert.ErrorIs(t, err, exception.ErrJobNotFound)
+
+	// The next run is only known once started.
+	assert.Nil(t, s.Jobs()[0].NextRun)
+}
+
+func TestScheduler_Start_FailsOwnRuns(t *testing.T) {
+	repo := repository.GetJobRunRepo()
+	for _, instance := range []string{"unit-a", "unit-b"} {
+		assert.Nil(t, repo.Create(context.Background(), &dao.JobRun{
+			JobName:   "unit_left_" + instance,
+			Instance:  instance,
+			Trigger:   domain.JobTriggerSchedule,
+			Status:    domain.JobRunning,
+			StartedAt: time.Now(),
+		}))
+	}
+
+	s := scheduler.New(repo, time.Minute, "unit-a")
+	assert.Nil(t, s.Start(context.Background()))
+	assert.Nil(t, s.Stop(context.Background()))
+
+	resp := getJobRun(t, repo, "unit_left_unit-a")
+	assert.Equal(t, string(domain.JobFailed), resp.Status)
+	assert.NotNil(t, resp.FinishedAt)
+
+	// Another instance may still be running its own.
+	resp = getJobRun(t, repo, "unit_left_unit-b")
+	assert.Equal(t, string(domain.JobRunning), resp.Status)
+}
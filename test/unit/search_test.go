@@ -0,0 +1,137 @@
+package unit_test
+
+import (
+	"base-gin/search"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newSearchIndex() *search.Index {
+	idx := search.New(
+		search.Field{Name: "title", Boost: 3},
+		search.Field{Name: "author", Boost: 1},
+	)
+	idx.Reset([]search.Document{
+		{
+			ID:     1,
+			Fields: map[string]string{"title": "Laskar Pelangi", "author": "Andrea Hirata"},
+			Facets: map[string][]search.Facet{"author": {{ID: 10, Name: "Andrea Hirata"}}},
+		},
+		{
+			ID:     2,
+			Fields: map[string]string{"title": "Sang Pemimpi", "author": "Andrea Hirata"},
+			Facets: map[string][]search.Facet{"author": {{ID: 10, Name: "Andrea Hirata"}}},
+		},
+		{
+			ID:     3,
+			Fields: map[string]string{"title": "Bumi Manusia", "author": "Pramoedya Ananta Toer"},
+			Facets: map[string][]search.Facet{"author": {{ID: 11, Name: "Pramoedya Ananta Toer"}}},
+		},
+		{
+			ID:     4,
+			Fields: map[string]string{"title": "Catatan Pelangi Hirata", "author": "Pramoedya Ananta Toer"},
+			Facets: map[string][]search.Facet{"author": {{ID: 11, Name: "Pramoedya Ananta Toer"}}},
+		},
+	})
+	return idx
+}
+
+func hitIDs(result search.Result) []uint {
+	ids := make([]uint, len(result.Hits))
+	for i, hit := range result.Hits {
+		ids[i] = hit.ID
+	}
+	return ids
+}
+
+func TestSearch_Tokenize(t *testing.T) {
+	assert.Equal(t, []string{"sejarah", "dunia", "jilid", "2"}, search.Tokenize("Sejarah Dunia: Jilid-2 "))
+	assert.Empty(t, search.Tokenize(" -- "))
+}
+
+func TestSearch_Ranking(t *testing.T) {
+	idx := newSearchIndex()
+
+	// A match in the title outranks one in the author.
+	result := idx.Search(search.Query{Text: "hirata"})
+	assert.Equal(t, 3, result.Total)
+	assert.Equal(t, uint(4), result.Hits[0].ID)
+	assert.ElementsMatch(t, []uint{1, 2, 4}, hitIDs(result))
+
+	// Every word must match.
+	result = idx.Search(search.Query{Text: "pelangi andrea"})
+	assert.Equal(t, []uint{1}, hitIDs(result))
+
+	assert.Empty(t, idx.Search(search.Query{Text: "  "}).Hits)
+}
+
+func TestSearch_Typos(t *testing.T) {
+	idx := newSearchIndex()
+
+	for _, text := range []string{"plangi", "pelagni", "pramudya"} {
+		assert.NotEmpty(t, idx.Search(search.Query{Text: text}).Hits, text)
+	}
+	// Short words must match exactly.
+	assert.Empty(t, idx.Search(search.Query{Text: "bmi"}).Hits)
+	// The last word may be incomplete.
+	assert.Equal(t, []uint{3}, hitIDs(idx.Search(search.Query{Text: "bumi man"})))
+	assert.Empty(t, idx.Search(search.Query{Text: "man bumi"}).Hits)
+}
+
+func TestSearch_Highlights(t *testing.T) {
+	idx := newSearchIndex()
+
+	result := idx.Search(search.Query{Text: "pelangi"})
+	assert.Equal(t, uint(1), result.Hits[0].ID)
+	assert.Equal(t, map[string]string{
+		"title": "Laskar <mark>Pelangi</mark>",
+	}, result.Hits[0].Highlights)
+
+	// Markup in the text is escaped, only the tags are HTML.
+	idx = search.New(search.Field{Name: "title", Boost: 1})
+	idx.Reset([]search.Document{
+		{ID: 1, Fields: map[string]string{"title": `<script>alert("Pelangi")</script> & Pelangi`}},
+	})
+	result = idx.Search(search.Query{Text: "pelangi"})
+	assert.Equal(t,
+		"&lt;script&gt;alert(&#34;<mark>Pelangi</mark>&#34;)&lt;/script&gt; &amp; <mark>Pelangi</mark>",
+		result.Hits[0].Highlights["title"])
+}
+
+func TestSearch_Facets(t *testing.T) {
+	idx := newSearchIndex()
+
+	result := idx.Search(search.Query{Text: "pelangi"})
+	assert.Equal(t, []search.FacetCount{
+		{Facet: search.Facet{ID: 10, Name: "Andrea Hirata"}, Count: 1},
+		{Facet: search.Facet{ID: 11, Name: "Pramoedya Ananta Toer"}, Count: 1},
+	}, result.Facets["author"])
+
+	result = idx.Search(search.Query{Text: "pelangi", Filters: map[string]uint{"author": 11}})
+	assert.Equal(t, []uint{4}, hitIDs(result))
+}
+
+func TestSearch_Paging(t *testing.T) {
+	idx := newSearchIndex()
+
+	result := idx.Search(search.Query{Text: "hirata", Start: 1, Limit: 1})
+	assert.Equal(t, 3, result.Total)
+	assert.Len(t, result.Hits, 1)
+
+	result = idx.Search(search.Query{Text: "hirata", Start: 5})
+	assert.Equal(t, 3, result.Total)
+	assert.Empty(t, result.Hits)
+}
+
+func TestSearch_PutDelete(t *testing.T) {
+	idx := newSearchIndex()
+
+	idx.Put(search.Document{ID: 3, Fields: map[string]string{"title": "Anak Semua Bangsa"}})
+	assert.Empty(t, idx.Search(search.Query{Text: "bumi"}).Hits)
+	assert.Equal(t, []uint{3}, hitIDs(idx.Search(search.Query{Text: "bangsa"})))
+
+	idx.Delete(3)
+	assert.Empty(t, idx.Search(search.Query{Text: "bangsa"}).Hits)
+	assert.Equal(t, 3, idx.Len())
+}
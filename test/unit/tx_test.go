@@ -0,0 +1,54 @@
+package unit_test
+
+import (
+	"base-gin/domain/dao"
+	"base-gin/repository"
+	"base-gin/util"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func countPersonByName(name string) int64 {
+	var count int64
+	db.Model(&dao.Person{}).Where("fullname = ?", name).Count(&count)
+	return count
+}
+
+func TestTxManager_WithTx_Commit(t *testing.T) {
+	name := util.RandomStringAlpha(12)
+
+	err := repository.GetTxManager().WithTx(context.Background(), func(repos *repository.Repositories) error {
+		return repos.Person.Create(context.Background(), &dao.Person{Fullname: name})
+	})
+	assert.Nil(t, err)
+	assert.EqualValues(t, 1, countPersonByName(name))
+}
+
+func TestTxManager_WithTx_RollbackOnError(t *testing.T) {
+	name := util.RandomStringAlpha(12)
+	errAbort := errors.New("abort")
+
+	err := repository.GetTxManager().WithTx(context.Background(), func(repos *repository.Repositories) error {
+		if err := repos.Person.Create(context.Background(), &dao.Person{Fullname: name}); err != nil {
+			return err
+		}
+		return errAbort
+	})
+	assert.ErrorIs(t, err, errAbort)
+	assert.EqualValues(t, 0, countPersonByName(name))
+}
+
+func TestTxManager_WithTx_RollbackOnPanic(t *testing.T) {
+	name := util.RandomStringAlpha(12)
+
+	assert.Panics(t, func() {
+		_ = repository.GetTxManager().WithTx(context.Background(), func(repos *repository.Repositories) error {
+			_ = repos.Person.Create(context.Background(), &dao.Person{Fullname: name})
+			panic("abort")
+		})
+	})
+	assert.EqualValues(t, 0, countPersonByName(name))
+}
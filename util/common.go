@@ -9,6 +9,7 @@ import (
 	"io"
 	"regexp"
 	"strings"
+	"unicode"
 
 	"github.com/google/uuid"
 	"golang.org/x/crypto/bcrypt"
@@ -162,3 +163,23 @@ func SanitiseName(str string) string {
 	re := regexp.MustCompile(`[:;|~!@#$%^*+={}\[\]\/"]+`)
 	return re.ReplaceAllString(str, "")
 }
+
+// Slugify lower-cases text and joins its runs of letters & digits with
+// hyphens, e.g. "Computer Science & AI" becomes "computer-science-ai".
+func Slugify(text string) string {
+	var b strings.Builder
+	pending := false
+	for _, r := range strings.ToLower(text) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if pending && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			pending = false
+			b.WriteRune(r)
+			continue
+		}
+		pending = true
+	}
+
+	return b.String()
+}
@@ -0,0 +1,131 @@
+package util
+
+import (
+	"errors"
+	"strings"
+)
+
+var ErrISBNInvalid = errors.New("ISBN tidak valid")
+
+// NormaliseISBN strips the hyphens & spaces ISBNs are often written with and
+// upper-cases the X check digit of an ISBN-10. The result is not validated.
+func NormaliseISBN(isbn string) string {
+	isbn = strings.NewReplacer("-", "", " ", "").Replace(isbn)
+	return strings.ToUpper(isbn)
+}
+
+// IsISBN10 tells whether a normalised ISBN is a valid ISBN-10, checksum
+// included.
+func IsISBN10(isbn string) bool {
+	if len(isbn) != 10 {
+		return false
+	}
+
+	sum := 0
+	for i := 0; i < 10; i++ {
+		var digit int
+		switch c := isbn[i]; {
+		case c >= '0' && c <= '9':
+			digit = int(c - '0')
+		case c == 'X' && i == 9:
+			digit = 10
+		default:
+			return false
+		}
+		sum += digit * (10 - i)
+	}
+
+	return sum%11 == 0
+}
+
+// IsISBN13 tells whether a normalised ISBN is a valid ISBN-13, checksum
+// included.
+func IsISBN13(isbn string) bool {
+	if len(isbn) != 13 || !isDigits(isbn) {
+		return false
+	}
+	if !strings.HasPrefix(isbn, "978") && !strings.HasPrefix(isbn, "979") {
+		return false
+	}
+
+	return isbn13CheckDigit(isbn[:12]) == isbn[12]
+}
+
+// IsISBN tells whether an ISBN, hyphenated or not, is a valid ISBN-10 or
+// ISBN-13.
+func IsISBN(isbn string) bool {
+	isbn = NormaliseISBN(isbn)
+	return IsISBN10(isbn) || IsISBN13(isbn)
+}
+
+// ISBN10To13 converts a valid ISBN-10 into its ISBN-13, prefixed with 978.
+func ISBN10To13(isbn string) (string, error) {
+	isbn = NormaliseISBN(isbn)
+	if !IsISBN10(isbn) {
+		return "", ErrISBNInvalid
+	}
+
+	body := "978" + isbn[:9]
+	return body + string(isbn13CheckDigit(body)), nil
+}
+
+// ISBN13To10 converts a valid ISBN-13 into its ISBN-10. Only ISBN-13s
+// prefixed with 978 have one; others fail with ErrISBNInvalid.
+func ISBN13To10(isbn string) (string, error) {
+	isbn = NormaliseISBN(isbn)
+	if !IsISBN13(isbn) || !strings.HasPrefix(isbn, "978") {
+		return "", ErrISBNInvalid
+	}
+
+	body := isbn[3:12]
+	sum := 0
+	for i := 0; i < 9; i++ {
+		sum += int(body[i]-'0') * (10 - i)
+	}
+	check := (11 - sum%11) % 11
+	if check == 10 {
+		return body + "X", nil
+	}
+	return body + string(rune('0'+check)), nil
+}
+
+// ParseISBN normalises a valid ISBN-10 or ISBN-13 and returns both forms. The
+// ISBN-10 is empty for ISBN-13s prefixed with 979, which have none.
+func ParseISBN(isbn string) (isbn10 string, isbn13 string, err error) {
+	isbn = NormaliseISBN(isbn)
+	switch {
+	case IsISBN10(isbn):
+		isbn13, err = ISBN10To13(isbn)
+		return isbn, isbn13, err
+	case IsISBN13(isbn):
+		if strings.HasPrefix(isbn, "978") {
+			isbn10, err = ISBN13To10(isbn)
+		}
+		return isbn10, isbn, err
+	default:
+		return "", "", ErrISBNInvalid
+	}
+}
+
+func isbn13CheckDigit(body string) byte {
+	sum := 0
+	for i := 0; i < 12; i++ {
+		digit := int(body[i] - '0')
+		if i%2 == 1 {
+			digit *= 3
+		}
+		sum += digit
+	}
+
+	return byte('0' + (10-sum%10)%10)
+}
+
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+
+	return true
+}
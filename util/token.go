@@ -24,14 +24,44 @@ var (
 
 type AuthAccessClaims struct {
 	Email string `json:"email"`
+	Role  string `json:"role,omitempty"`
 	jwt.RegisteredClaims
 }
 
-func CreateAuthAccessToken(cfg config.Config, subject string) (string, error) {
+// TokenInfo holds the registered claims needed to track or revoke a token.
+type TokenInfo struct {
+	ID        string
+	Subject   string
+	IssuedAt  time.Time
+	ExpiresAt time.Time
+}
+
+func NewTokenInfo(claims jwt.MapClaims) TokenInfo {
+	var info TokenInfo
+	info.ID, _ = claims["jti"].(string)
+	info.Subject, _ = claims["sub"].(string)
+	if iat, ok := claims["iat"].(float64); ok {
+		info.IssuedAt = time.Unix(int64(iat), 0).UTC()
+	}
+	if exp, ok := claims["exp"].(float64); ok {
+		info.ExpiresAt = time.Unix(int64(exp), 0).UTC()
+	}
+
+	return info
+}
+
+// CreateAuthAccessToken issues an access token identified by tokenID, which is
+// what the revocation denylist refers to. The role claim is informational,
+// access checks use the role currently stored on the account.
+func CreateAuthAccessToken(cfg config.Config, subject, tokenID, role string) (string, error) {
+	now := time.Now().UTC()
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, AuthAccessClaims{
+		Role: role,
 		RegisteredClaims: jwt.RegisteredClaims{
-			Subject: subject,
-			ExpiresAt: jwt.NewNumericDate(time.Now().UTC().
+			ID:       tokenID,
+			IssuedAt: jwt.NewNumericDate(now),
+			Subject:  subject,
+			ExpiresAt: jwt.NewNumericDate(now.
 				Add(time.Duration(cfg.AuthN.JWTAuthTTL) * time.Second),
 			),
 			Issuer:   tokenIssuer,
@@ -46,10 +76,15 @@ func CreateAuthAccessToken(cfg config.Config, subject string) (string, error) {
 	return signedToken, nil
 }
 
-func CreateAuthRefreshToken(cfg config.Config, subject string) (string, error) {
+// CreateAuthRefreshToken issues a refresh token identified by tokenID, which
+// callers keep to rotate or revoke the token later.
+func CreateAuthRefreshToken(cfg config.Config, subject, tokenID string) (string, error) {
+	now := time.Now().UTC()
 	refreshClaims := &jwt.RegisteredClaims{
-		Subject: subject,
-		ExpiresAt: jwt.NewNumericDate(time.Now().UTC().
+		ID:       tokenID,
+		IssuedAt: jwt.NewNumericDate(now),
+		Subject:  subject,
+		ExpiresAt: jwt.NewNumericDate(now.
 			Add(time.Duration(cfg.AuthN.JWTRefreshTTL) * time.Second),
 		),
 		Issuer:   tokenIssuer,